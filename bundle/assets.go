@@ -0,0 +1,103 @@
+package bundle
+
+import (
+	"regexp"
+
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	ecmpb "github.com/eolymp/go-sdk/eolymp/ecm"
+	executorpb "github.com/eolymp/go-sdk/eolymp/executor"
+)
+
+// Kind of asset, defines how asset is uploaded back to eolymp.
+type Kind string
+
+const (
+	KindTest       Kind = "test"       // test input or answer, uploaded as text in chunks
+	KindFile       Kind = "file"       // extra file for checker, validator, interactor, script or template
+	KindAttachment Kind = "attachment" // problem attachment, uploaded as is
+	KindImage      Kind = "image"      // image referenced from statement or editorial, uploaded as is
+)
+
+var (
+	latexImage    = regexp.MustCompile(`(\\includegraphics.*?{)(https?://.+?)(})`)
+	markdownImage = regexp.MustCompile(`(!\[[^\]]*\]\()(https?://[^)\s]+)(\))`)
+	htmlImage     = regexp.MustCompile(`(<img[^>]+src=")(https?://[^"]+)(")`)
+)
+
+// walk calls fn for every asset link in the snapshot and replaces the link with the value returned by fn.
+func walk(snap *atlaspb.Snapshot, fn func(kind Kind, link string) string) {
+	replace := func(kind Kind, link *string) {
+		if *link != "" {
+			*link = fn(kind, *link)
+		}
+	}
+
+	files := func(files []*executorpb.File) {
+		for _, file := range files {
+			replace(KindFile, &file.SourceUrl)
+		}
+	}
+
+	content := func(c *ecmpb.Content) {
+		switch v := c.GetValue().(type) {
+		case *ecmpb.Content_Latex:
+			v.Latex = images(latexImage, v.Latex, fn)
+		case *ecmpb.Content_Markdown:
+			v.Markdown = images(markdownImage, v.Markdown, fn)
+		case *ecmpb.Content_Html:
+			v.Html = images(htmlImage, v.Html, fn)
+		}
+	}
+
+	for _, statement := range snap.GetStatements() {
+		content(statement.GetContent())
+	}
+
+	for _, editorial := range snap.GetEditorials() {
+		content(editorial.GetContent())
+	}
+
+	for _, attachment := range snap.GetAttachments() {
+		replace(KindAttachment, &attachment.Link)
+	}
+
+	if snap.GetChecker() != nil {
+		files(snap.Checker.Files)
+	}
+
+	if snap.GetValidator() != nil {
+		files(snap.Validator.Files)
+	}
+
+	if snap.GetInteractor() != nil {
+		files(snap.Interactor.Files)
+	}
+
+	for _, script := range snap.GetScripts() {
+		files(script.Files)
+	}
+
+	for _, template := range snap.GetTemplates() {
+		files(template.Files)
+	}
+
+	for _, test := range snap.GetTests() {
+		if input, ok := test.Input.(*atlaspb.Test_InputUrl); ok {
+			replace(KindTest, &input.InputUrl)
+		}
+
+		if answer, ok := test.Answer.(*atlaspb.Test_AnswerUrl); ok {
+			replace(KindTest, &answer.AnswerUrl)
+		}
+
+		replace(KindTest, &test.ExampleInputUrl)
+		replace(KindTest, &test.ExampleAnswerUrl)
+	}
+}
+
+func images(pattern *regexp.Regexp, text string, fn func(kind Kind, link string) string) string {
+	return pattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := pattern.FindStringSubmatch(match)
+		return parts[1] + fn(KindImage, parts[2]) + parts[3]
+	})
+}
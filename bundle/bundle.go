@@ -0,0 +1,311 @@
+// Package bundle stores problem snapshots together with their assets in a portable directory.
+//
+// A bundle consists of a manifest, snapshot serialized as protojson and a directory with asset files referenced from
+// the snapshot (tests, extra files, attachments and images). Bundles can be used for offline review, archival and to
+// replay an import into another eolymp environment without fetching the problem from its origin again.
+//
+// Layout of the bundle directory:
+//
+//	manifest.json
+//	snapshot.json
+//	assets/<sha256>/<name>
+package bundle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// Format identifies bundle manifests.
+	Format = "eolymp-snapshot-bundle"
+
+	// Version of the bundle format, bumped when layout or manifest changes in incompatible way.
+	Version = 1
+
+	manifestFile = "manifest.json"
+	snapshotFile = "snapshot.json"
+	assetsDir    = "assets"
+)
+
+// Manifest describes bundle content.
+type Manifest struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Snapshot  File      `json:"snapshot"`
+	Assets    []Asset   `json:"assets"`
+}
+
+// File within a bundle.
+type File struct {
+	Path   string `json:"path"`   // path relative to the bundle directory
+	SHA256 string `json:"sha256"` // hex-encoded SHA256 of the file content
+	Size   int64  `json:"size"`
+}
+
+// Asset is a file referenced from the snapshot.
+type Asset struct {
+	File
+	Link string `json:"link"` // link used in the snapshot
+	Name string `json:"name"` // original file name
+	Kind Kind   `json:"kind"`
+}
+
+// Fetcher downloads assets referenced from the snapshot.
+type Fetcher interface {
+	Fetch(ctx context.Context, link string) (io.ReadCloser, error)
+}
+
+// HTTPFetcher downloads assets over HTTP.
+type HTTPFetcher struct {
+	Client *http.Client
+}
+
+func (f HTTPFetcher) Fetch(ctx context.Context, link string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compose HTTP request: %w", err)
+	}
+
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("response status code is not OK: %v", resp.Status)
+	}
+
+	return resp.Body, nil
+}
+
+// Bundle is a snapshot loaded from the bundle directory.
+type Bundle struct {
+	Path     string
+	Manifest *Manifest
+	Snapshot *atlaspb.Snapshot
+}
+
+// Write stores snapshot and all its assets into the directory. Assets are downloaded using fetch, if fetch is nil
+// assets are downloaded over HTTP.
+func Write(ctx context.Context, dir string, snap *atlaspb.Snapshot, fetch Fetcher) (*Manifest, error) {
+	if fetch == nil {
+		fetch = HTTPFetcher{}
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, assetsDir), 0777); err != nil {
+		return nil, fmt.Errorf("unable to create bundle directory: %w", err)
+	}
+
+	// collect assets, walk is run on a copy to make sure snapshot is not modified
+	kinds := map[string]Kind{}
+	walk(proto.Clone(snap).(*atlaspb.Snapshot), func(kind Kind, link string) string {
+		if _, ok := kinds[link]; !ok {
+			kinds[link] = kind
+		}
+
+		return link
+	})
+
+	var links []string
+	for link := range kinds {
+		links = append(links, link)
+	}
+
+	sort.Strings(links)
+
+	manifest := &Manifest{Format: Format, Version: Version, CreatedAt: time.Now().UTC()}
+
+	for _, link := range links {
+		asset, err := writeAsset(ctx, dir, fetch, link)
+		if err != nil {
+			return nil, fmt.Errorf("unable to save asset %#v: %w", link, err)
+		}
+
+		asset.Kind = kinds[link]
+		manifest.Assets = append(manifest.Assets, *asset)
+	}
+
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(snap)
+	if err != nil {
+		return nil, fmt.Errorf("unable to serialize snapshot: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, snapshotFile), data, 0666); err != nil {
+		return nil, fmt.Errorf("unable to write snapshot: %w", err)
+	}
+
+	manifest.Snapshot = File{Path: snapshotFile, SHA256: fmt.Sprintf("%x", sha256.Sum256(data)), Size: int64(len(data))}
+
+	data, err = json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("unable to serialize manifest: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, manifestFile), data, 0666); err != nil {
+		return nil, fmt.Errorf("unable to write manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+func writeAsset(ctx context.Context, dir string, fetch Fetcher, link string) (*Asset, error) {
+	src, err := fetch.Fetch(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+
+	defer src.Close()
+
+	// download into a temporary file first, since the final location depends on the hash
+	tmp, err := os.CreateTemp(filepath.Join(dir, assetsDir), ".download-*")
+	if err != nil {
+		return nil, err
+	}
+
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+
+	size, err := io.Copy(io.MultiWriter(tmp, hash), src)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+
+	sum := fmt.Sprintf("%x", hash.Sum(nil))
+	name := assetName(link)
+	rel := path.Join(assetsDir, sum, name)
+
+	if err := os.MkdirAll(filepath.Join(dir, assetsDir, sum), 0777); err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, filepath.FromSlash(rel))); err != nil {
+		return nil, err
+	}
+
+	return &Asset{File: File{Path: rel, SHA256: sum, Size: size}, Link: link, Name: name}, nil
+}
+
+// Read loads bundle from the directory and verifies its integrity.
+func Read(dir string) (*Bundle, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read manifest: %w", err)
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("unable to parse manifest: %w", err)
+	}
+
+	if manifest.Format != Format {
+		return nil, fmt.Errorf("directory does not contain a bundle: unknown format %#v", manifest.Format)
+	}
+
+	if manifest.Version > Version {
+		return nil, fmt.Errorf("bundle version %v is not supported, upgrade to read it", manifest.Version)
+	}
+
+	bundle := &Bundle{Path: dir, Manifest: manifest}
+
+	if err := bundle.Verify(); err != nil {
+		return nil, err
+	}
+
+	data, err = os.ReadFile(bundle.path(manifest.Snapshot.Path))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read snapshot: %w", err)
+	}
+
+	bundle.Snapshot = &atlaspb.Snapshot{}
+	if err := protojson.Unmarshal(data, bundle.Snapshot); err != nil {
+		return nil, fmt.Errorf("unable to parse snapshot: %w", err)
+	}
+
+	return bundle, nil
+}
+
+// Verify checks sizes and hashes of the snapshot and all assets against the manifest.
+func (b *Bundle) Verify() error {
+	var errs []error
+
+	if err := b.verify(b.Manifest.Snapshot); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, asset := range b.Manifest.Assets {
+		if err := b.verify(asset.File); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (b *Bundle) verify(file File) error {
+	f, err := os.Open(b.path(file.Path))
+	if err != nil {
+		return fmt.Errorf("file %#v is missing: %w", file.Path, err)
+	}
+
+	defer f.Close()
+
+	hash := sha256.New()
+
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return fmt.Errorf("unable to read file %#v: %w", file.Path, err)
+	}
+
+	if size != file.Size {
+		return fmt.Errorf("file %#v is corrupted: size is %d, expected %d", file.Path, size, file.Size)
+	}
+
+	if sum := fmt.Sprintf("%x", hash.Sum(nil)); sum != file.SHA256 {
+		return fmt.Errorf("file %#v is corrupted: hash is %v, expected %v", file.Path, sum, file.SHA256)
+	}
+
+	return nil
+}
+
+func (b *Bundle) path(rel string) string {
+	return filepath.Join(b.Path, filepath.FromSlash(path.Clean("/"+rel)))
+}
+
+// assetName picks file name from the asset link
+func assetName(link string) string {
+	name := "asset"
+	if u, err := url.Parse(link); err == nil && path.Base(u.Path) != "/" && path.Base(u.Path) != "." {
+		name = path.Base(u.Path)
+	}
+
+	return name
+}
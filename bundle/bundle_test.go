@@ -0,0 +1,115 @@
+package bundle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/eolymp/go-problems/connector/testing"
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	ecmpb "github.com/eolymp/go-sdk/eolymp/ecm"
+	executorpb "github.com/eolymp/go-sdk/eolymp/executor"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+)
+
+var opts = []cmp.Option{
+	cmp.Comparer(proto.Equal),
+}
+
+func TestBundle(t *testing.T) {
+	ctx := context.Background()
+
+	files := map[string]string{
+		"/file/01":        "1 2\n",
+		"/file/01.a":      "3\n",
+		"/file/image.png": "PNG",
+		"/file/lib.h":     "// lib",
+		"/file/grader":    "grader",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		_, _ = w.Write([]byte(data))
+	}))
+
+	defer server.Close()
+
+	snap := &atlaspb.Snapshot{
+		Statements: []*atlaspb.Statement{{Locale: "en", Title: "Sum", Content: &ecmpb.Content{Value: &ecmpb.Content_Latex{Latex: "\\includegraphics[width=3cm]{" + server.URL + "/file/image.png}"}}}},
+		Checker:    &atlaspb.Checker{Type: executorpb.Checker_PROGRAM, Files: []*executorpb.File{{Path: "lib.h", SourceUrl: server.URL + "/file/lib.h"}}},
+		Attachments: []*atlaspb.Attachment{
+			{Name: "grader.cpp", Link: server.URL + "/file/grader"},
+		},
+		Tests: []*atlaspb.Test{
+			{Index: 1, Input: &atlaspb.Test_InputUrl{InputUrl: server.URL + "/file/01"}, Answer: &atlaspb.Test_AnswerUrl{AnswerUrl: server.URL + "/file/01.a"}},
+		},
+	}
+
+	dir := filepath.Join(t.TempDir(), "bundle")
+
+	manifest, err := Write(ctx, dir, snap, nil)
+	if err != nil {
+		t.Fatal("Unable to write bundle:", err)
+	}
+
+	if want, got := 5, len(manifest.Assets); want != got {
+		t.Fatalf("Bundle must contain %v assets, got %v", want, got)
+	}
+
+	t.Run("read", func(t *testing.T) {
+		bundle, err := Read(dir)
+		if err != nil {
+			t.Fatal("Unable to read bundle:", err)
+		}
+
+		if !cmp.Equal(snap, bundle.Snapshot, opts...) {
+			t.Errorf("Snapshot does not match:\n%s", cmp.Diff(snap, bundle.Snapshot, opts...))
+		}
+	})
+
+	t.Run("upload", func(t *testing.T) {
+		bundle, err := Read(dir)
+		if err != nil {
+			t.Fatal("Unable to read bundle:", err)
+		}
+
+		got, err := bundle.Upload(ctx, MockUploader(), MockLogger(t))
+		if err != nil {
+			t.Fatal("Unable to upload bundle:", err)
+		}
+
+		want := &atlaspb.Snapshot{
+			Statements: []*atlaspb.Statement{{Locale: "en", Title: "Sum", Content: &ecmpb.Content{Value: &ecmpb.Content_Latex{Latex: "\\includegraphics[width=3cm]{https://eolympusercontent.com/file/image.png.55505ba281b015ec31f03ccb151b2a34}"}}}},
+			Checker:    &atlaspb.Checker{Type: executorpb.Checker_PROGRAM, Files: []*executorpb.File{{Path: "lib.h", SourceUrl: "https://eolympusercontent.com/file/lib.h.86b494ec23ebe2d184420bf345b0da90"}}},
+			Attachments: []*atlaspb.Attachment{
+				{Name: "grader.cpp", Link: "https://eolympusercontent.com/file/grader.26a3a4f381618d73e57568820db006f6"},
+			},
+			Tests: []*atlaspb.Test{
+				{Index: 1, Input: &atlaspb.Test_InputUrl{InputUrl: "https://eolympusercontent.com/file/01.f303b7d2f2b87f9e16df05e2bca7c409"}, Answer: &atlaspb.Test_AnswerUrl{AnswerUrl: "https://eolympusercontent.com/file/01.a.6d7fce9fee471194aa8b5b6e47267f03"}},
+			},
+		}
+
+		if !cmp.Equal(want, got, opts...) {
+			t.Errorf("Uploaded snapshot does not match:\n%s", cmp.Diff(want, got, opts...))
+		}
+	})
+
+	t.Run("corrupted", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(dir, manifest.Assets[0].Path), []byte("corrupted"), 0666); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Read(dir); err == nil {
+			t.Errorf("Reading corrupted bundle must fail")
+		}
+	})
+}
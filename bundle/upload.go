@@ -0,0 +1,70 @@
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/eolymp/go-problems/connector"
+	assetpb "github.com/eolymp/go-sdk/eolymp/asset"
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	"google.golang.org/protobuf/proto"
+)
+
+// Upload uploads all bundle assets using the uploader and returns a copy of the snapshot with links pointing to
+// uploaded assets.
+func (b *Bundle) Upload(ctx context.Context, upload connector.Uploader, log connector.Logger) (*atlaspb.Snapshot, error) {
+	uploader := connector.NewMultipartUploader(upload, log)
+
+	links := map[string]string{}
+	for _, asset := range b.Manifest.Assets {
+		link, err := b.upload(ctx, uploader, asset)
+		if err != nil {
+			return nil, fmt.Errorf("unable to upload asset %#v: %w", asset.Path, err)
+		}
+
+		links[asset.Link] = link
+	}
+
+	snap := proto.Clone(b.Snapshot).(*atlaspb.Snapshot)
+
+	var errs int
+	walk(snap, func(kind Kind, link string) string {
+		replacement, ok := links[link]
+		if !ok {
+			errs++
+			log.Errorf("Asset %#v is not included into the bundle", link)
+			return link
+		}
+
+		return replacement
+	})
+
+	if errs > 0 {
+		return nil, fmt.Errorf("%d asset(s) referenced by the snapshot are missing in the bundle", errs)
+	}
+
+	return snap, nil
+}
+
+// upload test data and files in chunks, just like loaders do, attachments and images are uploaded as is
+func (b *Bundle) upload(ctx context.Context, uploader *connector.MultipartUploader, asset Asset) (string, error) {
+	path := b.path(asset.Path)
+
+	switch asset.Kind {
+	case KindTest, KindFile:
+		return uploader.UploadFile(ctx, path)
+	default:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+
+		out, err := uploader.UploadAsset(ctx, &assetpb.UploadAssetInput{Name: asset.Name, Data: data})
+		if err != nil {
+			return "", err
+		}
+
+		return out.GetAssetUrl(), nil
+	}
+}
@@ -0,0 +1,677 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/eolymp/go-problems/bundle"
+	"github.com/eolymp/go-problems/connector"
+	"github.com/eolymp/go-problems/importer"
+	"github.com/eolymp/go-problems/lint"
+	"github.com/eolymp/go-problems/polygon"
+	"github.com/eolymp/go-problems/runtimes"
+	"github.com/eolymp/go-problems/topics"
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func runImport(ctx context.Context, args []string) error {
+	conf := &config{}
+	set := newFlagSet("import", conf)
+	output := set.String("o", "", "write snapshot into the file instead of stdout")
+	origin := set.String("provenance", "", "write provenance record (where the snapshot came from) into the file")
+
+	link, err := parse(set, args)
+	if err != nil {
+		return err
+	}
+
+	result, err := fetch(ctx, conf, link, conf.uploader())
+	if err != nil {
+		return err
+	}
+
+	for _, w := range result.Warnings {
+		fmt.Fprintln(os.Stderr, "warning:", w)
+	}
+
+	if *origin != "" {
+		data, err := json.MarshalIndent(result.Provenance, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(*origin, append(data, '\n'), 0644); err != nil {
+			return err
+		}
+	}
+
+	return writeSnapshot(result.Snapshot, *output)
+}
+
+func runBatch(ctx context.Context, args []string) error {
+	conf := &config{}
+	set := newFlagSet("batch", conf)
+	set.Usage = func() {
+		fmt.Fprintf(set.Output(), "Usage: problems batch [flags] <link>...\n\nFlags:\n")
+		set.PrintDefaults()
+	}
+
+	output := set.String("o", "", "write snapshots into the directory, named by problem number (1.json, 2.json, ...)")
+	problems := set.Int("problems", 4, "number of problems fetched concurrently")
+	uploads := set.Int("uploads", 10, "number of concurrent uploads shared by all problems")
+	dry := set.Bool("dry-run", false, "do not upload anything")
+
+	if err := set.Parse(args); err != nil {
+		return errFailed
+	}
+
+	if set.NArg() == 0 {
+		set.Usage()
+		return errFailed
+	}
+
+	pipeline, err := conf.transforms()
+	if err != nil {
+		return err
+	}
+
+	loader, err := conf.options()
+	if err != nil {
+		return err
+	}
+
+	opts := []func(*importer.Batch){
+		importer.UseProblemConcurrency(*problems),
+		importer.UseUploadConcurrency(*uploads),
+		importer.UseTransform(pipeline),
+		importer.UseImportOptions(loader...),
+	}
+	if conf.format != "" {
+		format, err := importer.ParseFormat(conf.format)
+		if err != nil {
+			return err
+		}
+
+		opts = append(opts, importer.UseFormat(format))
+	}
+
+	links := make([]string, set.NArg())
+	for i, link := range set.Args() {
+		links[i] = conf.credentials(link)
+	}
+
+	upload := conf.uploader()
+	if *dry {
+		upload = &dryUploader{}
+	}
+
+	results := importer.NewBatch(upload, logger{verbose: conf.verbose}, opts...).Import(ctx, links)
+
+	if *output != "" {
+		if err := os.MkdirAll(*output, 0755); err != nil {
+			return err
+		}
+
+		for i, result := range results {
+			if result.Snapshot == nil {
+				continue
+			}
+
+			if err := writeSnapshot(result.Snapshot, filepath.Join(*output, fmt.Sprintf("%d.json", i+1))); err != nil {
+				return err
+			}
+		}
+	}
+
+	if conf.json {
+		type row struct {
+			Link       string                `json:"link"`
+			Format     string                `json:"format,omitempty"`
+			Provenance *connector.Provenance `json:"provenance,omitempty"`
+			Warnings   []connector.Warning   `json:"warnings,omitempty"`
+			Tests      int                   `json:"tests"`
+			Duration   string                `json:"duration"`
+			Error      string                `json:"error,omitempty"`
+		}
+
+		rows := make([]row, len(results))
+		for i, result := range results {
+			rows[i] = row{
+				Link:       connector.Redact(result.Link),
+				Format:     string(result.Format),
+				Provenance: result.Provenance,
+				Warnings:   result.Warnings,
+				Tests:      len(result.Snapshot.GetTests()),
+				Duration:   result.Duration.String(),
+			}
+
+			if result.Error != nil {
+				rows[i].Error = result.Error.Error()
+			}
+		}
+
+		if err := printJSON(rows); err != nil {
+			return err
+		}
+	} else if err := importer.WriteTable(os.Stdout, results); err != nil {
+		return err
+	}
+
+	if importer.Failed(results) > 0 {
+		return errFailed
+	}
+
+	return nil
+}
+
+func runDryRun(ctx context.Context, args []string) error {
+	conf := &config{}
+	set := newFlagSet("dry-run", conf)
+
+	link, err := parse(set, args)
+	if err != nil {
+		return err
+	}
+
+	result, err := fetch(ctx, conf, link, &dryUploader{})
+	if err != nil {
+		return err
+	}
+
+	findings := lint.Lint(result.Snapshot)
+
+	if conf.json {
+		return printJSON(struct {
+			Summary    summary               `json:"summary"`
+			Provenance *connector.Provenance `json:"provenance"`
+			Warnings   []connector.Warning   `json:"warnings"`
+			Findings   []lint.Finding        `json:"findings"`
+		}{Summary: summarize(result.Snapshot), Provenance: result.Provenance, Warnings: result.Warnings, Findings: findings})
+	}
+
+	printProvenance(result.Provenance)
+	printSummary(summarize(result.Snapshot))
+	printWarnings(result.Warnings)
+	printFindings(findings)
+
+	return nil
+}
+
+func runInspect(ctx context.Context, args []string) error {
+	conf := &config{}
+	set := newFlagSet("inspect", conf)
+
+	link, err := parse(set, args)
+	if err != nil {
+		return err
+	}
+
+	snap, err := load(ctx, conf, link)
+	if err != nil {
+		return err
+	}
+
+	if conf.json {
+		return printJSON(summarize(snap))
+	}
+
+	printSummary(summarize(snap))
+
+	return nil
+}
+
+func runLint(ctx context.Context, args []string) error {
+	conf := &config{}
+	set := newFlagSet("lint", conf)
+
+	link, err := parse(set, args)
+	if err != nil {
+		return err
+	}
+
+	snap, err := load(ctx, conf, link)
+	if err != nil {
+		return err
+	}
+
+	findings := lint.Lint(snap)
+
+	if conf.json {
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+
+		if err := printJSON(findings); err != nil {
+			return err
+		}
+	} else {
+		printFindings(findings)
+	}
+
+	if lint.HasErrors(findings) {
+		return errFailed
+	}
+
+	return nil
+}
+
+func runExport(ctx context.Context, args []string) error {
+	conf := &config{}
+	set := newFlagSet("export", conf)
+	set.Usage = func() {
+		fmt.Fprintf(set.Output(), "Usage: problems export [flags] <snapshot>\n\nSnapshot is a JSON file or a bundle.\n\nFlags:\n")
+		set.PrintDefaults()
+	}
+
+	problem := set.Int("problem-id", 0, "Polygon problem the snapshot is exported into")
+	name := set.String("name", "", "create Polygon problem with the name and export the snapshot into it")
+
+	link, err := parse(set, args)
+	if err != nil {
+		return err
+	}
+
+	if (*problem == 0) == (*name == "") {
+		return errors.New("either -problem-id or -name must be given")
+	}
+
+	snap, err := load(ctx, conf, link)
+	if err != nil {
+		return err
+	}
+
+	var opts []func(*polygon.ProblemExporter)
+
+	if conf.runtimes != "" {
+		registry, err := runtimes.Load(conf.runtimes)
+		if err != nil {
+			return err
+		}
+
+		opts = append(opts, polygon.UseExportRuntimes(registry))
+	}
+
+	if conf.topics != "" {
+		mapper, err := topics.Load(conf.topics)
+		if err != nil {
+			return err
+		}
+
+		opts = append(opts, polygon.UseExportTopics(mapper))
+	}
+
+	var client []func(*polygon.Client)
+	if conf.rate > 0 {
+		client = append(client, polygon.UseRateLimit(conf.rate))
+	}
+
+	poly := polygon.New(conf.key, conf.secret, client...)
+
+	if *name != "" {
+		created, err := poly.CreateProblem(ctx, polygon.CreateProblemInput{Name: *name})
+		if err != nil {
+			return fmt.Errorf("unable to create problem: %w", err)
+		}
+
+		*problem = created.ID
+	}
+
+	warnings, err := polygon.NewProblemExporter(poly, logger{verbose: conf.verbose}, opts...).Export(ctx, *problem, snap)
+	if err != nil {
+		return err
+	}
+
+	if conf.json {
+		return printJSON(map[string]any{"problem_id": *problem, "warnings": warnings})
+	}
+
+	printWarnings(warnings)
+	fmt.Println("Exported into Polygon problem", *problem)
+
+	return nil
+}
+
+func runWatch(ctx context.Context, args []string) error {
+	conf := &config{}
+	set := newFlagSet("watch", conf)
+	set.Usage = func() {
+		fmt.Fprintf(set.Output(), "Usage: problems watch [flags] <link>...\n\nLinks must be polygon:// links.\n\nFlags:\n")
+		set.PrintDefaults()
+	}
+
+	state := set.String("state", ".problems-watch", "directory where last imported revisions and snapshots are kept")
+	output := set.String("o", "", "write updated snapshots into the directory, named by Polygon problem ID (123.json)")
+	interval := set.Duration("interval", 5*time.Minute, "how often problems are checked")
+	once := set.Bool("once", false, "check problems once and exit")
+	dry := set.Bool("dry-run", false, "do not upload anything")
+
+	if err := set.Parse(args); err != nil {
+		return errFailed
+	}
+
+	if set.NArg() == 0 {
+		set.Usage()
+		return errFailed
+	}
+
+	opts, err := conf.options()
+	if err != nil {
+		return err
+	}
+
+	upload := conf.uploader()
+	if *dry {
+		upload = &dryUploader{}
+	}
+
+	log := logger{verbose: conf.verbose}
+
+	loader, err := importer.New(importer.FormatPolygon, upload, log, opts...)
+	if err != nil {
+		return err
+	}
+
+	store, err := polygon.NewFileStore(*state)
+	if err != nil {
+		return err
+	}
+
+	links := make([]string, set.NArg())
+	for i, link := range set.Args() {
+		links[i] = conf.credentials(link)
+	}
+
+	if *output != "" {
+		if err := os.MkdirAll(*output, 0755); err != nil {
+			return err
+		}
+	}
+
+	handle := func(ctx context.Context, update polygon.Update) error {
+		if *output != "" {
+			if err := writeSnapshot(update.Result.Snapshot, filepath.Join(*output, fmt.Sprintf("%d.json", update.ProblemID))); err != nil {
+				return err
+			}
+		}
+
+		if conf.json {
+			return printJSON(map[string]any{
+				"problem_id": update.ProblemID,
+				"previous":   update.Previous,
+				"revision":   update.Revision,
+				"provenance": update.Result.Provenance,
+				"warnings":   update.Result.Warnings,
+				"diff":       update.Diff,
+			})
+		}
+
+		fmt.Printf("Problem %v: revision %v → %v\n", update.ProblemID, update.Previous, update.Revision)
+		printWarnings(update.Result.Warnings)
+
+		if update.Diff != nil {
+			fmt.Print(update.Diff.Summary())
+		}
+
+		return nil
+	}
+
+	watcher := polygon.NewWatcher(loader.(*polygon.ProblemLoader), store, log, polygon.UseWatchInterval(*interval))
+
+	if *once {
+		return watcher.Check(ctx, links, handle)
+	}
+
+	if err := watcher.Run(ctx, links, handle); !errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	return nil
+}
+
+func runContest(ctx context.Context, args []string) error {
+	conf := &config{}
+	set := newFlagSet("contest", conf)
+	set.Usage = func() {
+		fmt.Fprintf(set.Output(), "Usage: problems contest [flags] <link>\n\nLink is a polygon:// link with contestId or a path to the contest package.\n\nFlags:\n")
+		set.PrintDefaults()
+	}
+
+	output := set.String("o", "", "write snapshots into the directory, named by problem index (A.json, B.json, ...)")
+	dry := set.Bool("dry-run", false, "do not upload anything")
+
+	link, err := parse(set, args)
+	if err != nil {
+		return err
+	}
+
+	if path, ok := importer.Local(link); ok {
+		link = (&url.URL{Scheme: "file", Path: path}).String()
+	}
+
+	pipeline, err := conf.transforms()
+	if err != nil {
+		return err
+	}
+
+	opts, err := conf.options()
+	if err != nil {
+		return err
+	}
+
+	upload := conf.uploader()
+	if *dry {
+		upload = &dryUploader{}
+	}
+
+	loader, err := importer.New(importer.FormatPolygon, upload, logger{verbose: conf.verbose}, opts...)
+	if err != nil {
+		return err
+	}
+
+	contest, err := loader.(*polygon.ProblemLoader).ImportContest(ctx, conf.credentials(link))
+	if err != nil {
+		return err
+	}
+
+	for _, problem := range contest.Problems {
+		if err := pipeline.Apply(problem.Result.Snapshot); err != nil {
+			return fmt.Errorf("problem %v: %w", problem.Index, err)
+		}
+	}
+
+	if *output != "" {
+		if err := os.MkdirAll(*output, 0755); err != nil {
+			return err
+		}
+
+		for _, problem := range contest.Problems {
+			if err := writeSnapshot(problem.Result.Snapshot, filepath.Join(*output, problem.Index+".json")); err != nil {
+				return err
+			}
+		}
+	}
+
+	if conf.json {
+		type row struct {
+			Index      string                `json:"index"`
+			Provenance *connector.Provenance `json:"provenance"`
+			Warnings   []connector.Warning   `json:"warnings,omitempty"`
+			Tests      int                   `json:"tests"`
+		}
+
+		rows := make([]row, len(contest.Problems))
+		for i, problem := range contest.Problems {
+			rows[i] = row{Index: problem.Index, Provenance: problem.Result.Provenance, Warnings: problem.Result.Warnings, Tests: len(problem.Result.Snapshot.GetTests())}
+		}
+
+		return printJSON(map[string]any{"name": contest.Name, "languages": contest.Languages, "problems": rows})
+	}
+
+	if contest.Name != "" {
+		fmt.Println("Contest:", contest.Name)
+	}
+
+	fmt.Println("Languages:", strings.Join(contest.Languages, ", "))
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "INDEX\tPROBLEM\tREVISION\tTESTS\tWARNINGS")
+
+	for _, problem := range contest.Problems {
+		p := problem.Result.Provenance
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%d\t%d\n", problem.Index, p.Name, p.Revision, len(problem.Result.Snapshot.GetTests()), len(problem.Result.Warnings))
+	}
+
+	return tw.Flush()
+}
+
+func runDetect(ctx context.Context, args []string) error {
+	conf := &config{}
+	set := newFlagSet("detect", conf)
+
+	link, err := parse(set, args)
+	if err != nil {
+		return err
+	}
+
+	format, err := importer.Detect(link)
+	if err != nil {
+		return err
+	}
+
+	if conf.json {
+		return printJSON(map[string]any{"format": format})
+	}
+
+	fmt.Println(format)
+
+	return nil
+}
+
+// fetch downloads and converts the problem
+func fetch(ctx context.Context, conf *config, link string, upload connector.Uploader) (*connector.Result, error) {
+	pipeline, err := conf.transforms()
+	if err != nil {
+		return nil, err
+	}
+
+	format, err := conf.detect(link)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := conf.options()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := importer.Import(ctx, format, conf.credentials(link), upload, logger{verbose: conf.verbose}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pipeline.Apply(result.Snapshot); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// load reads snapshot from a JSON file or a bundle, any other link is fetched without uploading assets
+func load(ctx context.Context, conf *config, link string) (*atlaspb.Snapshot, error) {
+	if path, ok := importer.Local(link); ok {
+		var snap *atlaspb.Snapshot
+
+		if strings.HasSuffix(path, ".json") {
+			read, err := readSnapshot(path)
+			if err != nil {
+				return nil, err
+			}
+
+			snap = read
+		} else if _, err := os.Stat(filepath.Join(path, "manifest.json")); err == nil {
+			b, err := bundle.Read(path)
+			if err != nil {
+				return nil, err
+			}
+
+			snap = b.Snapshot
+		}
+
+		if snap != nil {
+			pipeline, err := conf.transforms()
+			if err != nil {
+				return nil, err
+			}
+
+			return snap, pipeline.Apply(snap)
+		}
+	}
+
+	result, err := fetch(ctx, conf, link, &dryUploader{})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Snapshot, nil
+}
+
+func readSnapshot(path string) (*atlaspb.Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read snapshot: %w", err)
+	}
+
+	snap := &atlaspb.Snapshot{}
+	if err := protojson.Unmarshal(data, snap); err != nil {
+		return nil, fmt.Errorf("unable to parse snapshot: %w", err)
+	}
+
+	return snap, nil
+}
+
+func writeSnapshot(snap *atlaspb.Snapshot, path string) error {
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("unable to encode snapshot: %w", err)
+	}
+
+	if path == "" {
+		_, err = os.Stdout.Write(append(data, '\n'))
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
+
+func printWarnings(warnings []connector.Warning) {
+	for _, w := range warnings {
+		fmt.Println("warning:", w)
+	}
+}
+
+func printFindings(findings []lint.Finding) {
+	if len(findings) == 0 {
+		fmt.Println("No issues found")
+		return
+	}
+
+	for _, f := range findings {
+		fmt.Println(f)
+	}
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/eolymp/go-problems/connector"
+	"github.com/eolymp/go-problems/importer"
+	"github.com/eolymp/go-problems/polygon"
+	"github.com/eolymp/go-problems/runtimes"
+	"github.com/eolymp/go-problems/topics"
+	"github.com/eolymp/go-problems/transform"
+	assetpb "github.com/eolymp/go-sdk/eolymp/asset"
+	"google.golang.org/grpc"
+)
+
+// config holds flags shared by all commands
+type config struct {
+	format   string
+	json     bool
+	verbose  bool
+	key      string
+	secret   string
+	username string
+	password string
+	apiURL   string
+	token    string
+	pipeline string
+	runtimes string
+	topics   string
+	mode     string
+	rate     float64
+	types    string
+	latest   bool
+	build    bool
+	verify   bool
+	timeout  time.Duration
+	testsets string
+}
+
+func newFlagSet(name string, conf *config) *flag.FlagSet {
+	set := flag.NewFlagSet(name, flag.ContinueOnError)
+	set.Usage = func() {
+		fmt.Fprintf(set.Output(), "Usage: problems %v [flags] <link>\n\nFlags:\n", name)
+		set.PrintDefaults()
+	}
+
+	set.StringVar(&conf.format, "format", "", "problem format (polygon or kattis), detected automatically if empty")
+	set.BoolVar(&conf.json, "json", false, "print output as JSON")
+	set.BoolVar(&conf.verbose, "v", false, "print loader progress to stderr")
+	set.StringVar(&conf.key, "polygon-key", os.Getenv("POLYGON_API_KEY"), "Polygon API key")
+	set.StringVar(&conf.secret, "polygon-secret", os.Getenv("POLYGON_API_SECRET"), "Polygon API secret")
+	set.StringVar(&conf.username, "polygon-username", os.Getenv("POLYGON_USERNAME"), "Polygon username, used for package links")
+	set.StringVar(&conf.password, "polygon-password", os.Getenv("POLYGON_PASSWORD"), "Polygon password, used for package links")
+	set.StringVar(&conf.apiURL, "api-url", os.Getenv("EOLYMP_API_URL"), "Eolymp API URL")
+	set.StringVar(&conf.token, "token", os.Getenv("EOLYMP_TOKEN"), "Eolymp API token")
+	set.StringVar(&conf.runtimes, "runtimes", "", "override runtime registry with the YAML or JSON file")
+	set.StringVar(&conf.topics, "topics", "", "override tag to topic mapping with the YAML or JSON file")
+	set.StringVar(&conf.mode, "polygon-mode", "", "how polygon:// problems are fetched: package (default) or api, api mode does not need a built package")
+	set.Float64Var(&conf.rate, "polygon-rate", 0, "maximum number of Polygon API requests per second, unlimited if zero")
+	set.StringVar(&conf.types, "polygon-types", "", "comma separated Polygon package types in order of preference (windows, linux, standard)")
+	set.BoolVar(&conf.latest, "polygon-latest", false, "import the newest Polygon package even if it's not of the preferred type")
+	set.BoolVar(&conf.build, "polygon-build", false, "build Polygon package if there is no ready package for the latest revision")
+	set.BoolVar(&conf.verify, "polygon-verify", false, "verify solutions when building Polygon package")
+	set.DurationVar(&conf.timeout, "polygon-build-timeout", 30*time.Minute, "how long to wait for Polygon package build")
+	set.StringVar(&conf.testsets, "polygon-testsets", "", "comma separated Polygon testset roles, e.g. pretests=final,tests=skip (roles: final, pretests, skip)")
+	set.StringVar(&conf.pipeline, "transform", "", "apply transforms declared in the YAML or JSON file to the snapshot")
+
+	return set
+}
+
+// parse parses flags and returns the link
+func parse(set *flag.FlagSet, args []string) (string, error) {
+	if err := set.Parse(args); err != nil {
+		return "", errFailed
+	}
+
+	if set.NArg() != 1 {
+		set.Usage()
+		return "", errFailed
+	}
+
+	return set.Arg(0), nil
+}
+
+// detect returns problem format, using format flag when it's given
+func (c *config) detect(link string) (importer.Format, error) {
+	if c.format != "" {
+		return importer.ParseFormat(c.format)
+	}
+
+	return importer.Detect(link)
+}
+
+// options returns importer options configured by flags
+func (c *config) options() ([]importer.Option, error) {
+	var opts []importer.Option
+
+	if c.runtimes != "" {
+		registry, err := runtimes.Load(c.runtimes)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, importer.UseRuntimes(registry))
+	}
+
+	if c.topics != "" {
+		mapper, err := topics.Load(c.topics)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, importer.UseTopics(mapper))
+	}
+
+	switch polygon.Mode(c.mode) {
+	case "", polygon.ModePackage:
+	case polygon.ModeAPI:
+		opts = append(opts, importer.UsePolygonMode(polygon.ModeAPI))
+	default:
+		return nil, fmt.Errorf("polygon mode %#v is not supported", c.mode)
+	}
+
+	if c.rate > 0 {
+		opts = append(opts, importer.UsePolygonClient(polygon.UseRateLimit(c.rate)))
+	}
+
+	if c.types != "" || c.latest {
+		policy := polygon.PackagePolicy{Latest: c.latest}
+		if c.types != "" {
+			for _, kind := range strings.Split(c.types, ",") {
+				if kind != "windows" && kind != "linux" && kind != "standard" {
+					return nil, fmt.Errorf("package type %#v is not supported", kind)
+				}
+
+				policy.Types = append(policy.Types, kind)
+			}
+		}
+
+		opts = append(opts, importer.UsePackagePolicy(policy))
+	}
+
+	if c.build {
+		opts = append(opts, importer.UsePackageBuild(polygon.BuildOptions{Verify: c.verify, Timeout: c.timeout}))
+	}
+
+	if c.testsets != "" {
+		roles := map[string]polygon.TestsetRole{}
+		for _, item := range strings.Split(c.testsets, ",") {
+			name, value, ok := strings.Cut(item, "=")
+			if !ok {
+				return nil, fmt.Errorf("testset role %#v must be given as name=role", item)
+			}
+
+			role, err := polygon.ParseTestsetRole(strings.TrimSpace(value))
+			if err != nil {
+				return nil, err
+			}
+
+			roles[strings.TrimSpace(name)] = role
+		}
+
+		opts = append(opts, importer.UseTestsetRoles(roles))
+	}
+
+	return opts, nil
+}
+
+// transforms loads transform pipeline, nil pipeline does nothing
+func (c *config) transforms() (*transform.Pipeline, error) {
+	if c.pipeline == "" {
+		return nil, nil
+	}
+
+	return transform.Load(c.pipeline)
+}
+
+// credentials injects Polygon credentials into the link, unless link already has them
+func (c *config) credentials(link string) string {
+	origin, err := url.Parse(link)
+	if err != nil || origin.User != nil {
+		return link
+	}
+
+	switch {
+	case origin.Scheme == "polygon" && c.key != "":
+		origin.User = url.UserPassword(c.key, c.secret)
+	case origin.Scheme == "https" && origin.Hostname() == "polygon.codeforces.com" && c.username != "":
+		origin.User = url.UserPassword(c.username, c.password)
+	default:
+		return link
+	}
+
+	return origin.String()
+}
+
+// client creates HTTP client for Eolymp API
+func (c *config) client() *http.Client {
+	if c.token == "" {
+		return http.DefaultClient
+	}
+
+	return &http.Client{Transport: &bearer{token: c.token, next: http.DefaultTransport}}
+}
+
+type bearer struct {
+	token string
+	next  http.RoundTripper
+}
+
+func (b *bearer) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+strings.TrimPrefix(b.token, "Bearer "))
+
+	return b.next.RoundTrip(req)
+}
+
+// logger prints loader progress to stderr
+type logger struct {
+	verbose bool
+}
+
+func (l logger) Printf(format string, args ...any) {
+	if l.verbose {
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
+	}
+}
+
+func (l logger) Errorf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "error: "+format+"\n", args...)
+}
+
+// uploader creates uploader which stores assets in Eolymp
+func (c *config) uploader() connector.Uploader {
+	return &assetClient{cli: assetpb.NewAssetServiceHttpClient(c.apiURL, c.client())}
+}
+
+// assetClient adapts HTTP client to the connector.Uploader interface, HTTP client does not accept gRPC call options
+type assetClient struct {
+	cli *assetpb.AssetServiceService
+}
+
+func (a *assetClient) LookupAsset(ctx context.Context, in *assetpb.LookupAssetInput, _ ...grpc.CallOption) (*assetpb.LookupAssetOutput, error) {
+	return a.cli.LookupAsset(ctx, in)
+}
+
+func (a *assetClient) UploadAsset(ctx context.Context, in *assetpb.UploadAssetInput, _ ...grpc.CallOption) (*assetpb.UploadAssetOutput, error) {
+	return a.cli.UploadAsset(ctx, in)
+}
+
+func (a *assetClient) StartMultipartUpload(ctx context.Context, in *assetpb.StartMultipartUploadInput, _ ...grpc.CallOption) (*assetpb.StartMultipartUploadOutput, error) {
+	return a.cli.StartMultipartUpload(ctx, in)
+}
+
+func (a *assetClient) UploadPart(ctx context.Context, in *assetpb.UploadPartInput, _ ...grpc.CallOption) (*assetpb.UploadPartOutput, error) {
+	return a.cli.UploadPart(ctx, in)
+}
+
+func (a *assetClient) CompleteMultipartUpload(ctx context.Context, in *assetpb.CompleteMultipartUploadInput, _ ...grpc.CallOption) (*assetpb.CompleteMultipartUploadOutput, error) {
+	return a.cli.CompleteMultipartUpload(ctx, in)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"sync"
+
+	assetpb "github.com/eolymp/go-sdk/eolymp/asset"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// dryUploader pretends to upload assets, it returns fake links which point to nowhere
+type dryUploader struct {
+	lock  sync.Mutex
+	parts map[string]int
+}
+
+func (*dryUploader) LookupAsset(ctx context.Context, in *assetpb.LookupAssetInput, opts ...grpc.CallOption) (*assetpb.LookupAssetOutput, error) {
+	return nil, status.Error(codes.NotFound, "not found")
+}
+
+func (*dryUploader) UploadAsset(ctx context.Context, in *assetpb.UploadAssetInput, opts ...grpc.CallOption) (*assetpb.UploadAssetOutput, error) {
+	return &assetpb.UploadAssetOutput{AssetUrl: fmt.Sprintf("dry-run://%x/%v", sha1.Sum(in.GetData()), in.GetName())}, nil
+}
+
+func (u *dryUploader) StartMultipartUpload(ctx context.Context, in *assetpb.StartMultipartUploadInput, opts ...grpc.CallOption) (*assetpb.StartMultipartUploadOutput, error) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	if u.parts == nil {
+		u.parts = map[string]int{}
+	}
+
+	id := fmt.Sprintf("%v-%v", len(u.parts)+1, in.GetName())
+	u.parts[id] = 0
+
+	return &assetpb.StartMultipartUploadOutput{UploadId: id}, nil
+}
+
+func (u *dryUploader) UploadPart(ctx context.Context, in *assetpb.UploadPartInput, opts ...grpc.CallOption) (*assetpb.UploadPartOutput, error) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	u.parts[in.GetUploadId()] += len(in.GetData())
+
+	return &assetpb.UploadPartOutput{}, nil
+}
+
+func (u *dryUploader) CompleteMultipartUpload(ctx context.Context, in *assetpb.CompleteMultipartUploadInput, opts ...grpc.CallOption) (*assetpb.CompleteMultipartUploadOutput, error) {
+	return &assetpb.CompleteMultipartUploadOutput{AssetUrl: "dry-run://upload/" + in.GetUploadId()}, nil
+}
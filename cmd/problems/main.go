@@ -0,0 +1,91 @@
+// Command problems fetches Polygon and Kattis problems and converts them into Eolymp snapshots.
+//
+// Usage:
+//
+//	problems <command> [flags] <link>
+//
+// Commands:
+//
+//	import   fetch the problem, upload its assets and print the snapshot
+//	batch    import several problems at once and print a table of results
+//	dry-run  fetch and convert the problem without uploading anything
+//	inspect  print a readable summary of the snapshot
+//	lint     check snapshot for common mistakes, exits with code 1 if there are errors
+//	detect   print format of the problem
+//	export   recreate snapshot in a Polygon problem
+//	watch    re-import Polygon problems when their revision changes
+//	contest  import all problems of a Polygon contest
+//
+// Link is a Polygon or Kattis link, a path to the local problem archive or directory. Commands inspect and lint also
+// accept snapshot (.json) files and snapshot bundles, command export accepts only them. Command watch accepts
+// polygon:// links only, command contest accepts polygon:// links with contestId and contest packages.
+//
+// Credentials are read from environment variables POLYGON_API_KEY, POLYGON_API_SECRET, POLYGON_USERNAME,
+// POLYGON_PASSWORD, EOLYMP_API_URL and EOLYMP_TOKEN, or from the corresponding flags.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+var commands = map[string]func(ctx context.Context, args []string) error{
+	"import":  runImport,
+	"batch":   runBatch,
+	"dry-run": runDryRun,
+	"inspect": runInspect,
+	"lint":    runLint,
+	"detect":  runDetect,
+	"export":  runExport,
+	"watch":   runWatch,
+	"contest": runContest,
+}
+
+// errFailed indicates command has already reported the failure and should exit with non-zero code
+var errFailed = errors.New("failed")
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	command, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %#v\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := command(ctx, os.Args[2:]); err != nil {
+		if !errors.Is(err, errFailed) {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+
+		stop()
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: problems <command> [flags] <link>")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  import   fetch the problem, upload its assets and print the snapshot")
+	fmt.Fprintln(os.Stderr, "  batch    import several problems at once and print a table of results")
+	fmt.Fprintln(os.Stderr, "  dry-run  fetch and convert the problem without uploading anything")
+	fmt.Fprintln(os.Stderr, "  inspect  print a readable summary of the snapshot")
+	fmt.Fprintln(os.Stderr, "  lint     check snapshot for common mistakes")
+	fmt.Fprintln(os.Stderr, "  detect   print format of the problem")
+	fmt.Fprintln(os.Stderr, "  export   recreate snapshot in a Polygon problem")
+	fmt.Fprintln(os.Stderr, "  watch    re-import Polygon problems when their revision changes")
+	fmt.Fprintln(os.Stderr, "  contest  import all problems of a Polygon contest")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run problems <command> -h to see command flags.")
+}
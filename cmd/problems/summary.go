@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eolymp/go-problems/connector"
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	executorpb "github.com/eolymp/go-sdk/eolymp/executor"
+)
+
+// summary is a readable overview of the snapshot
+type summary struct {
+	Title       string           `json:"title,omitempty"`
+	Topics      []string         `json:"topics,omitempty"`
+	Difficulty  uint32           `json:"difficulty,omitempty"`
+	Checker     string           `json:"checker,omitempty"`
+	Interactive bool             `json:"interactive,omitempty"`
+	Locales     []string         `json:"locales,omitempty"`
+	Editorials  []string         `json:"editorials,omitempty"`
+	Templates   []string         `json:"templates,omitempty"`
+	Solutions   []string         `json:"solutions,omitempty"`
+	Attachments []string         `json:"attachments,omitempty"`
+	Scripts     []string         `json:"scripts,omitempty"`
+	Testsets    []testsetSummary `json:"testsets,omitempty"`
+	Tests       int              `json:"tests"`
+	Score       float32          `json:"score"`
+}
+
+type testsetSummary struct {
+	Index       uint32   `json:"index"`
+	Tests       int      `json:"tests"`
+	Examples    int      `json:"examples,omitempty"`
+	Score       float32  `json:"score"`
+	Scoring     string   `json:"scoring,omitempty"`
+	TimeLimit   uint32   `json:"time_limit,omitempty"`
+	MemoryLimit uint64   `json:"memory_limit,omitempty"`
+	DependsOn   []uint32 `json:"depends_on,omitempty"`
+}
+
+func summarize(snap *atlaspb.Snapshot) summary {
+	s := summary{
+		Topics:      snap.GetProblem().GetTopics(),
+		Difficulty:  snap.GetProblem().GetDifficulty(),
+		Checker:     snap.GetChecker().GetType().String(),
+		Interactive: snap.GetInteractor().GetType() != executorpb.Interactor_NONE,
+		Tests:       len(snap.GetTests()),
+	}
+
+	for _, statement := range snap.GetStatements() {
+		if s.Title == "" || statement.GetLocale() == "en" {
+			s.Title = statement.GetTitle()
+		}
+
+		s.Locales = append(s.Locales, statement.GetLocale())
+	}
+
+	for _, editorial := range snap.GetEditorials() {
+		s.Editorials = append(s.Editorials, editorial.GetLocale())
+	}
+
+	for _, template := range snap.GetTemplates() {
+		s.Templates = append(s.Templates, template.GetRuntime())
+	}
+
+	for _, solution := range snap.GetSolutions() {
+		s.Solutions = append(s.Solutions, fmt.Sprintf("%v (%v, %v)", solution.GetName(), solution.GetRuntime(), solution.GetType()))
+	}
+
+	for _, attachment := range snap.GetAttachments() {
+		s.Attachments = append(s.Attachments, attachment.GetName())
+	}
+
+	for _, script := range snap.GetScripts() {
+		s.Scripts = append(s.Scripts, script.GetName())
+	}
+
+	index := map[string]int{}
+	for _, testset := range snap.GetTestsets() {
+		index[testset.GetId()] = len(s.Testsets)
+		s.Testsets = append(s.Testsets, testsetSummary{
+			Index:       testset.GetIndex(),
+			Scoring:     testset.GetScoringMode().String(),
+			TimeLimit:   max(testset.GetTimeLimit(), testset.GetCpuLimit()),
+			MemoryLimit: testset.GetMemoryLimit(),
+			DependsOn:   testset.GetDependencies(),
+		})
+	}
+
+	for _, test := range snap.GetTests() {
+		s.Score += test.GetScore()
+
+		i, ok := index[test.GetTestsetId()]
+		if !ok {
+			continue
+		}
+
+		s.Testsets[i].Tests++
+		s.Testsets[i].Score += test.GetScore()
+		if test.GetExample() {
+			s.Testsets[i].Examples++
+		}
+	}
+
+	sort.Strings(s.Locales)
+	sort.Strings(s.Editorials)
+	sort.Strings(s.Templates)
+	sort.Slice(s.Testsets, func(i, j int) bool { return s.Testsets[i].Index < s.Testsets[j].Index })
+
+	return s
+}
+
+func printSummary(s summary) {
+	fmt.Printf("Title:       %v\n", s.Title)
+	fmt.Printf("Topics:      %v\n", list(s.Topics))
+	fmt.Printf("Difficulty:  %v\n", s.Difficulty)
+	fmt.Printf("Checker:     %v\n", s.Checker)
+	fmt.Printf("Interactive: %v\n", s.Interactive)
+	fmt.Printf("Statements:  %v\n", list(s.Locales))
+	fmt.Printf("Editorials:  %v\n", list(s.Editorials))
+	fmt.Printf("Templates:   %v\n", list(s.Templates))
+	fmt.Printf("Attachments: %v\n", list(s.Attachments))
+	fmt.Printf("Scripts:     %v\n", list(s.Scripts))
+	fmt.Printf("Solutions:   %v\n", list(s.Solutions))
+	fmt.Printf("Tests:       %v (score %v)\n", s.Tests, s.Score)
+
+	for _, ts := range s.Testsets {
+		fmt.Printf("  testset %v: %v tests, %v examples, score %v, %v, %v ms, %v bytes", ts.Index, ts.Tests, ts.Examples, ts.Score, ts.Scoring, ts.TimeLimit, ts.MemoryLimit)
+		if len(ts.DependsOn) > 0 {
+			fmt.Printf(", depends on %v", ts.DependsOn)
+		}
+
+		fmt.Println()
+	}
+}
+
+func printProvenance(p *connector.Provenance) {
+	if p == nil {
+		return
+	}
+
+	fmt.Printf("Origin:      %v (%v)\n", p.Origin, p.Format)
+
+	if p.Name != "" {
+		fmt.Printf("Name:        %v\n", p.Name)
+	}
+
+	if p.ProblemID != "" || p.PackageID != "" || p.Revision != "" {
+		fmt.Printf("Problem:     %v, package %v, revision %v\n", or(p.ProblemID), or(p.PackageID), or(p.Revision))
+	}
+
+	if p.Source != "" || p.License != "" {
+		fmt.Printf("Source:      %v, license %v\n", or(p.Source), or(p.License))
+	}
+
+	if p.ArchiveSHA256 != "" {
+		fmt.Printf("Archive:     sha256:%v\n", p.ArchiveSHA256)
+	}
+}
+
+func or(v string) string {
+	if v == "" {
+		return "-"
+	}
+
+	return v
+}
+
+func list(items []string) string {
+	if len(items) == 0 {
+		return "-"
+	}
+
+	return strings.Join(items, ", ")
+}
@@ -0,0 +1,125 @@
+package connector
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"sync"
+
+	assetpb "github.com/eolymp/go-sdk/eolymp/asset"
+	"google.golang.org/grpc"
+)
+
+// CachedUploader remembers uploaded assets and reuses their links. Assets uploaded with UploadAsset are identified
+// by name and content hash, files uploaded using multipart upload are identified by their keys (see
+// MultipartUploader), so LookupAsset finds them without calling the underlying Uploader.
+//
+// Sharing a CachedUploader between loaders avoids uploading the same file twice when several problems are imported
+// together. Concurrent uploads of the same asset with UploadAsset are merged into a single call.
+type CachedUploader struct {
+	Uploader
+
+	lock    sync.Mutex
+	assets  map[string]*cacheEntry
+	keys    map[string]string   // asset key to link
+	uploads map[string][]string // multipart upload ID to keys
+}
+
+type cacheEntry struct {
+	done chan struct{}
+	link string
+}
+
+func NewCachedUploader(upload Uploader) *CachedUploader {
+	return &CachedUploader{
+		Uploader: upload,
+		assets:   map[string]*cacheEntry{},
+		keys:     map[string]string{},
+		uploads:  map[string][]string{},
+	}
+}
+
+func (u *CachedUploader) LookupAsset(ctx context.Context, in *assetpb.LookupAssetInput, opts ...grpc.CallOption) (*assetpb.LookupAssetOutput, error) {
+	u.lock.Lock()
+	link, ok := u.keys[in.GetKey()]
+	u.lock.Unlock()
+
+	if ok {
+		return &assetpb.LookupAssetOutput{AssetUrl: link}, nil
+	}
+
+	return u.Uploader.LookupAsset(ctx, in, opts...)
+}
+
+func (u *CachedUploader) UploadAsset(ctx context.Context, in *assetpb.UploadAssetInput, opts ...grpc.CallOption) (*assetpb.UploadAssetOutput, error) {
+	key := fmt.Sprintf("%v:%x", in.GetName(), sha1.Sum(in.GetData()))
+
+	for {
+		u.lock.Lock()
+		entry, ok := u.assets[key]
+		if !ok {
+			entry = &cacheEntry{done: make(chan struct{})}
+			u.assets[key] = entry
+		}
+		u.lock.Unlock()
+
+		// somebody else uploads the asset, wait and reuse the link
+		if ok {
+			select {
+			case <-entry.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+
+			if entry.link != "" {
+				return &assetpb.UploadAssetOutput{AssetUrl: entry.link}, nil
+			}
+
+			continue // previous upload has failed, try again
+		}
+
+		out, err := u.Uploader.UploadAsset(ctx, in, opts...)
+
+		u.lock.Lock()
+		if err != nil {
+			delete(u.assets, key)
+		} else {
+			entry.link = out.GetAssetUrl()
+		}
+		close(entry.done)
+		u.lock.Unlock()
+
+		return out, err
+	}
+}
+
+func (u *CachedUploader) StartMultipartUpload(ctx context.Context, in *assetpb.StartMultipartUploadInput, opts ...grpc.CallOption) (*assetpb.StartMultipartUploadOutput, error) {
+	out, err := u.Uploader.StartMultipartUpload(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	u.uploads[out.GetUploadId()] = in.GetKeys()
+
+	return out, nil
+}
+
+func (u *CachedUploader) CompleteMultipartUpload(ctx context.Context, in *assetpb.CompleteMultipartUploadInput, opts ...grpc.CallOption) (*assetpb.CompleteMultipartUploadOutput, error) {
+	out, err := u.Uploader.CompleteMultipartUpload(ctx, in, opts...)
+
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	if err == nil {
+		for _, key := range u.uploads[in.GetUploadId()] {
+			u.keys[key] = out.GetAssetUrl()
+		}
+	}
+
+	delete(u.uploads, in.GetUploadId())
+
+	return out, err
+}
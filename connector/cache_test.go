@@ -0,0 +1,184 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	assetpb "github.com/eolymp/go-sdk/eolymp/asset"
+	"google.golang.org/grpc"
+)
+
+// countingUploader counts calls, uploads wait until release is closed (if set)
+type countingUploader struct {
+	release chan struct{}
+	uploads atomic.Int32
+	lookups atomic.Int32
+	active  atomic.Int32
+	peak    atomic.Int32
+	fail    atomic.Bool
+}
+
+func (u *countingUploader) enter() func() {
+	active := u.active.Add(1)
+	for {
+		peak := u.peak.Load()
+		if active <= peak || u.peak.CompareAndSwap(peak, active) {
+			break
+		}
+	}
+
+	if u.release != nil {
+		<-u.release
+	}
+
+	return func() { u.active.Add(-1) }
+}
+
+func (u *countingUploader) LookupAsset(ctx context.Context, in *assetpb.LookupAssetInput, opts ...grpc.CallOption) (*assetpb.LookupAssetOutput, error) {
+	defer u.enter()()
+
+	u.lookups.Add(1)
+	return nil, errors.New("not found")
+}
+
+func (u *countingUploader) UploadAsset(ctx context.Context, in *assetpb.UploadAssetInput, opts ...grpc.CallOption) (*assetpb.UploadAssetOutput, error) {
+	defer u.enter()()
+
+	n := u.uploads.Add(1)
+	if u.fail.Load() {
+		return nil, errors.New("unavailable")
+	}
+
+	return &assetpb.UploadAssetOutput{AssetUrl: fmt.Sprintf("https://example.com/%v/%v", n, in.GetName())}, nil
+}
+
+func (u *countingUploader) StartMultipartUpload(ctx context.Context, in *assetpb.StartMultipartUploadInput, opts ...grpc.CallOption) (*assetpb.StartMultipartUploadOutput, error) {
+	defer u.enter()()
+
+	return &assetpb.StartMultipartUploadOutput{UploadId: "upload-" + in.GetName()}, nil
+}
+
+func (u *countingUploader) UploadPart(ctx context.Context, in *assetpb.UploadPartInput, opts ...grpc.CallOption) (*assetpb.UploadPartOutput, error) {
+	defer u.enter()()
+
+	return &assetpb.UploadPartOutput{}, nil
+}
+
+func (u *countingUploader) CompleteMultipartUpload(ctx context.Context, in *assetpb.CompleteMultipartUploadInput, opts ...grpc.CallOption) (*assetpb.CompleteMultipartUploadOutput, error) {
+	defer u.enter()()
+
+	return &assetpb.CompleteMultipartUploadOutput{AssetUrl: "https://example.com/" + in.GetUploadId()}, nil
+}
+
+func TestCachedUploader_UploadAsset(t *testing.T) {
+	ctx := context.Background()
+	upstream := &countingUploader{release: make(chan struct{})}
+	cache := NewCachedUploader(upstream)
+
+	var wg sync.WaitGroup
+	links := make([]string, 10)
+
+	for i := range links {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			out, err := cache.UploadAsset(ctx, &assetpb.UploadAssetInput{Name: "1.in", Data: []byte("1 2\n")})
+			if err != nil {
+				t.Errorf("UploadAsset has failed: %v", err)
+				return
+			}
+
+			links[i] = out.GetAssetUrl()
+		}()
+	}
+
+	// let all the calls reach the cache before the upload completes
+	time.Sleep(50 * time.Millisecond)
+	close(upstream.release)
+	wg.Wait()
+
+	if n := upstream.uploads.Load(); n != 1 {
+		t.Errorf("concurrent uploads of the same asset must be merged, got %v uploads", n)
+	}
+
+	for i, link := range links {
+		if link != links[0] {
+			t.Errorf("upload %v must return link %#v, got %#v", i, links[0], link)
+		}
+	}
+
+	// different content or name is uploaded separately
+	if _, err := cache.UploadAsset(ctx, &assetpb.UploadAssetInput{Name: "1.in", Data: []byte("2 3\n")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cache.UploadAsset(ctx, &assetpb.UploadAssetInput{Name: "2.in", Data: []byte("1 2\n")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := upstream.uploads.Load(); n != 3 {
+		t.Errorf("different assets must be uploaded, got %v uploads", n)
+	}
+}
+
+func TestCachedUploader_UploadAsset_failed(t *testing.T) {
+	ctx := context.Background()
+	upstream := &countingUploader{}
+	cache := NewCachedUploader(upstream)
+
+	upstream.fail.Store(true)
+	if _, err := cache.UploadAsset(ctx, &assetpb.UploadAssetInput{Name: "1.in", Data: []byte("1")}); err == nil {
+		t.Fatal("UploadAsset must fail")
+	}
+
+	upstream.fail.Store(false)
+	if _, err := cache.UploadAsset(ctx, &assetpb.UploadAssetInput{Name: "1.in", Data: []byte("1")}); err != nil {
+		t.Fatal("failed upload must not be cached:", err)
+	}
+
+	if n := upstream.uploads.Load(); n != 2 {
+		t.Errorf("upload must be retried, got %v uploads", n)
+	}
+}
+
+func TestCachedUploader_LookupAsset(t *testing.T) {
+	ctx := context.Background()
+	upstream := &countingUploader{}
+	cache := NewCachedUploader(upstream)
+
+	// unknown keys are looked up in the underlying uploader
+	if _, err := cache.LookupAsset(ctx, &assetpb.LookupAssetInput{Key: "sha1:abc"}); err == nil {
+		t.Fatal("LookupAsset must fail for unknown key")
+	}
+
+	upload, err := cache.StartMultipartUpload(ctx, &assetpb.StartMultipartUploadInput{Name: "big.in", Keys: []string{"sha1:abc", "md5:def"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := cache.CompleteMultipartUpload(ctx, &assetpb.CompleteMultipartUploadInput{UploadId: upload.GetUploadId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"sha1:abc", "md5:def"} {
+		found, err := cache.LookupAsset(ctx, &assetpb.LookupAssetInput{Key: key})
+		if err != nil {
+			t.Fatalf("LookupAsset(%v) has failed: %v", key, err)
+		}
+
+		if found.GetAssetUrl() != out.GetAssetUrl() {
+			t.Errorf("LookupAsset(%v) must return %#v, got %#v", key, out.GetAssetUrl(), found.GetAssetUrl())
+		}
+	}
+
+	if n := upstream.lookups.Load(); n != 1 {
+		t.Errorf("uploaded keys must be found in cache, got %v lookups", n)
+	}
+}
@@ -0,0 +1,87 @@
+package connector
+
+import (
+	"context"
+
+	assetpb "github.com/eolymp/go-sdk/eolymp/asset"
+	"google.golang.org/grpc"
+)
+
+// LimitedUploader limits number of concurrent calls to the underlying Uploader. A single LimitedUploader can be shared
+// by several loaders to enforce a global upload concurrency budget.
+type LimitedUploader struct {
+	Uploader
+	slots chan struct{}
+}
+
+// NewLimitedUploader creates uploader which allows at most n concurrent calls.
+func NewLimitedUploader(upload Uploader, n int) *LimitedUploader {
+	if n < 1 {
+		n = 1
+	}
+
+	return &LimitedUploader{Uploader: upload, slots: make(chan struct{}, n)}
+}
+
+func (u *LimitedUploader) acquire(ctx context.Context) error {
+	select {
+	case u.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (u *LimitedUploader) release() {
+	<-u.slots
+}
+
+func (u *LimitedUploader) LookupAsset(ctx context.Context, in *assetpb.LookupAssetInput, opts ...grpc.CallOption) (*assetpb.LookupAssetOutput, error) {
+	if err := u.acquire(ctx); err != nil {
+		return nil, err
+	}
+
+	defer u.release()
+
+	return u.Uploader.LookupAsset(ctx, in, opts...)
+}
+
+func (u *LimitedUploader) UploadAsset(ctx context.Context, in *assetpb.UploadAssetInput, opts ...grpc.CallOption) (*assetpb.UploadAssetOutput, error) {
+	if err := u.acquire(ctx); err != nil {
+		return nil, err
+	}
+
+	defer u.release()
+
+	return u.Uploader.UploadAsset(ctx, in, opts...)
+}
+
+func (u *LimitedUploader) StartMultipartUpload(ctx context.Context, in *assetpb.StartMultipartUploadInput, opts ...grpc.CallOption) (*assetpb.StartMultipartUploadOutput, error) {
+	if err := u.acquire(ctx); err != nil {
+		return nil, err
+	}
+
+	defer u.release()
+
+	return u.Uploader.StartMultipartUpload(ctx, in, opts...)
+}
+
+func (u *LimitedUploader) UploadPart(ctx context.Context, in *assetpb.UploadPartInput, opts ...grpc.CallOption) (*assetpb.UploadPartOutput, error) {
+	if err := u.acquire(ctx); err != nil {
+		return nil, err
+	}
+
+	defer u.release()
+
+	return u.Uploader.UploadPart(ctx, in, opts...)
+}
+
+func (u *LimitedUploader) CompleteMultipartUpload(ctx context.Context, in *assetpb.CompleteMultipartUploadInput, opts ...grpc.CallOption) (*assetpb.CompleteMultipartUploadOutput, error) {
+	if err := u.acquire(ctx); err != nil {
+		return nil, err
+	}
+
+	defer u.release()
+
+	return u.Uploader.CompleteMultipartUpload(ctx, in, opts...)
+}
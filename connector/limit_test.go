@@ -0,0 +1,65 @@
+package connector
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	assetpb "github.com/eolymp/go-sdk/eolymp/asset"
+)
+
+func TestLimitedUploader(t *testing.T) {
+	ctx := context.Background()
+	upstream := &countingUploader{release: make(chan struct{})}
+	limited := NewLimitedUploader(upstream, 2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if _, err := limited.UploadAsset(ctx, &assetpb.UploadAssetInput{Name: "1.in"}); err != nil {
+				t.Errorf("UploadAsset has failed: %v", err)
+			}
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if n := upstream.active.Load(); n != 2 {
+		t.Errorf("2 uploads must be running, got %v", n)
+	}
+
+	close(upstream.release)
+	wg.Wait()
+
+	if n := upstream.peak.Load(); n > 2 {
+		t.Errorf("at most 2 uploads must run concurrently, got %v", n)
+	}
+
+	if n := upstream.uploads.Load(); n != 6 {
+		t.Errorf("all uploads must complete, got %v", n)
+	}
+}
+
+func TestLimitedUploader_cancel(t *testing.T) {
+	upstream := &countingUploader{release: make(chan struct{})}
+	defer close(upstream.release)
+
+	limited := NewLimitedUploader(upstream, 1)
+
+	go func() {
+		_, _ = limited.UploadAsset(context.Background(), &assetpb.UploadAssetInput{Name: "1.in"})
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := limited.UploadAsset(ctx, &assetpb.UploadAssetInput{Name: "2.in"}); err == nil {
+		t.Error("UploadAsset must fail when context is cancelled while waiting for a slot")
+	}
+}
@@ -0,0 +1,124 @@
+package connector
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"runtime/debug"
+	"time"
+
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+)
+
+const module = "github.com/eolymp/go-problems"
+
+// Result of the problem import: the snapshot, the information where it came from and warnings about things which
+// were not imported exactly as they are in the original package.
+type Result struct {
+	Snapshot   *atlaspb.Snapshot
+	Provenance *Provenance
+	Warnings   []Warning
+}
+
+// Warning about a lossy conversion made during the import.
+type Warning struct {
+	Kind    string `json:"kind"`           // kind of the warning, e.g. runtime-downgrade
+	Path    string `json:"path,omitempty"` // file in the problem package the warning is about
+	Message string `json:"message"`        // human-readable description
+}
+
+func (w Warning) String() string {
+	if w.Path == "" {
+		return fmt.Sprintf("%v (%v)", w.Message, w.Kind)
+	}
+
+	return fmt.Sprintf("%v: %v (%v)", w.Path, w.Message, w.Kind)
+}
+
+// Provenance describes where the snapshot came from.
+type Provenance struct {
+	Origin        string    `json:"origin"`                   // link to the problem, credentials are removed
+	Format        string    `json:"format"`                   // problem format: polygon or kattis
+	ProblemID     string    `json:"problem_id,omitempty"`     // Polygon problem ID or Kattis problem UUID
+	PackageID     string    `json:"package_id,omitempty"`     // Polygon package ID
+	Revision      string    `json:"revision,omitempty"`       // Polygon problem revision or Kattis problem version
+	Name          string    `json:"name,omitempty"`           // short name of the problem
+	Source        string    `json:"source,omitempty"`         // contest or event the problem comes from
+	License       string    `json:"license,omitempty"`        // problem license
+	ArchiveSHA256 string    `json:"archive_sha256,omitempty"` // hash of the problem archive, empty for directories
+	LoaderVersion string    `json:"loader_version"`           // version of this module
+	ImportedAt    time.Time `json:"imported_at"`
+}
+
+// NewProvenance creates provenance record for a problem fetched from the link.
+func NewProvenance(format, link string) *Provenance {
+	return &Provenance{
+		Origin:        Redact(link),
+		Format:        format,
+		LoaderVersion: Version(),
+		ImportedAt:    time.Now().UTC(),
+	}
+}
+
+// Redact removes credentials from the link, so it can be safely printed or stored.
+func Redact(link string) string {
+	origin, err := url.Parse(link)
+	if err != nil || origin.User == nil {
+		return link
+	}
+
+	origin.User = nil
+
+	return origin.String()
+}
+
+// Version returns version of this module as recorded in the binary build info.
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(unknown)"
+	}
+
+	return version(info)
+}
+
+// version finds this module in the build info, replaced module without version (e.g. a local directory) is reported
+// with the version it replaces
+func version(info *debug.BuildInfo) string {
+	if info.Main.Path == module {
+		return info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path != module {
+			continue
+		}
+
+		if dep.Replace != nil && dep.Replace.Version != "" {
+			return dep.Replace.Version
+		}
+
+		return dep.Version
+	}
+
+	return "(devel)"
+}
+
+// HashFile returns SHA256 of the file content.
+func HashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
@@ -0,0 +1,48 @@
+package connector
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	tt := []struct {
+		name string
+		info *debug.BuildInfo
+		want string
+	}{
+		{
+			name: "main module",
+			info: &debug.BuildInfo{Main: debug.Module{Path: module, Version: "v1.2.0"}},
+			want: "v1.2.0",
+		},
+		{
+			name: "dependency",
+			info: &debug.BuildInfo{Deps: []*debug.Module{{Path: module, Version: "v1.2.0"}}},
+			want: "v1.2.0",
+		},
+		{
+			name: "replaced dependency",
+			info: &debug.BuildInfo{Deps: []*debug.Module{{Path: module, Version: "v1.2.0", Replace: &debug.Module{Path: "github.com/fork/go-problems", Version: "v1.2.1"}}}},
+			want: "v1.2.1",
+		},
+		{
+			name: "dependency replaced with directory",
+			info: &debug.BuildInfo{Deps: []*debug.Module{{Path: module, Version: "v1.2.0", Replace: &debug.Module{Path: "../go-problems"}}}},
+			want: "v1.2.0",
+		},
+		{
+			name: "no module",
+			info: &debug.BuildInfo{},
+			want: "(devel)",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := version(tc.info); got != tc.want {
+				t.Errorf("version must be %#v, got %#v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,402 @@
+package diff
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	ecmpb "github.com/eolymp/go-sdk/eolymp/ecm"
+	executorpb "github.com/eolymp/go-sdk/eolymp/executor"
+	"google.golang.org/protobuf/proto"
+)
+
+// Compare returns semantic difference between old and new snapshots.
+//
+// Test data, attachments and files are compared by links, see CompareContent to compare them by content.
+func Compare(old, new *atlaspb.Snapshot) *Diff {
+	return compareSnapshots(&comparer{}, old, new)
+}
+
+func compareSnapshots(c *comparer, old, new *atlaspb.Snapshot) *Diff {
+	c.problem(old.GetProblem(), new.GetProblem())
+	c.testing(old.GetTesting(), new.GetTesting())
+	c.checker(old.GetChecker(), new.GetChecker())
+	c.validator(old.GetValidator(), new.GetValidator())
+	c.interactor(old.GetInteractor(), new.GetInteractor())
+	c.statements(old.GetStatements(), new.GetStatements())
+	c.editorials(old.GetEditorials(), new.GetEditorials())
+	c.testsets(old.GetTestsets(), new.GetTestsets())
+	c.tests(old, new)
+	c.solutions(old.GetSolutions(), new.GetSolutions())
+	c.scripts(old.GetScripts(), new.GetScripts())
+	c.templates(old.GetTemplates(), new.GetTemplates())
+	c.attachments(old.GetAttachments(), new.GetAttachments())
+
+	return &Diff{Changes: c.changes}
+}
+
+type comparer struct {
+	changes []Change
+	digests map[string]string // link to content digest, links without digest are compared as is
+}
+
+// link returns content digest of the link if it's known
+func (c *comparer) link(v string) string {
+	if digest, ok := c.digests[v]; ok {
+		return digest
+	}
+
+	return v
+}
+
+func (c *comparer) add(kind Kind, section, key string) {
+	c.changes = append(c.changes, Change{Kind: kind, Section: section, Key: key})
+}
+
+func (c *comparer) field(section, key, field string, old, new any) {
+	o, n := fmt.Sprint(old), fmt.Sprint(new)
+	if o == n {
+		return
+	}
+
+	c.changes = append(c.changes, Change{Kind: Changed, Section: section, Key: key, Field: field, Old: o, New: n})
+}
+
+// text compares large text values, like source code or statement content, and reports digests instead of values
+func (c *comparer) text(section, key, field string, old, new string) {
+	if old == new {
+		return
+	}
+
+	c.changes = append(c.changes, Change{Kind: Changed, Section: section, Key: key, Field: field, Old: digest(old), New: digest(new)})
+}
+
+func (c *comparer) files(section, key string, old, new []*executorpb.File) {
+	c.field(section, key, "files", c.filelist(old), c.filelist(new))
+}
+
+func (c *comparer) problem(old, new *atlaspb.Problem) {
+	c.field("problem", "", "type", old.GetType(), new.GetType())
+
+	for _, topic := range missing(old.GetTopics(), new.GetTopics()) {
+		c.add(Removed, "topic", topic)
+	}
+
+	for _, topic := range missing(new.GetTopics(), old.GetTopics()) {
+		c.add(Added, "topic", topic)
+	}
+}
+
+func (c *comparer) testing(old, new *atlaspb.TestingConfig) {
+	c.field("testing", "", "run_count", old.GetRunCount(), new.GetRunCount())
+	c.field("testing", "", "interactive_followup", old.GetInteractiveFollowup(), new.GetInteractiveFollowup())
+}
+
+func (c *comparer) checker(old, new *atlaspb.Checker) {
+	c.field("checker", "", "type", old.GetType(), new.GetType())
+	c.field("checker", "", "runtime", old.GetRuntime(), new.GetRuntime())
+	c.field("checker", "", "precision", old.GetPrecision(), new.GetPrecision())
+	c.field("checker", "", "case_sensitive", old.GetCaseSensitive(), new.GetCaseSensitive())
+	c.text("checker", "", "source", old.GetSource(), new.GetSource())
+	c.files("checker", "", old.GetFiles(), new.GetFiles())
+}
+
+func (c *comparer) validator(old, new *atlaspb.Validator) {
+	switch {
+	case old == nil && new == nil:
+		return
+	case old == nil:
+		c.add(Added, "validator", "")
+	case new == nil:
+		c.add(Removed, "validator", "")
+	default:
+		c.field("validator", "", "runtime", old.GetRuntime(), new.GetRuntime())
+		c.text("validator", "", "source", old.GetSource(), new.GetSource())
+		c.files("validator", "", old.GetFiles(), new.GetFiles())
+	}
+}
+
+func (c *comparer) interactor(old, new *atlaspb.Interactor) {
+	// empty interactor is the same as no interactor
+	if old.GetType() == executorpb.Interactor_NONE && old.GetSource() == "" {
+		old = nil
+	}
+
+	if new.GetType() == executorpb.Interactor_NONE && new.GetSource() == "" {
+		new = nil
+	}
+
+	switch {
+	case old == nil && new == nil:
+		return
+	case old == nil:
+		c.add(Added, "interactor", "")
+	case new == nil:
+		c.add(Removed, "interactor", "")
+	default:
+		c.field("interactor", "", "type", old.GetType(), new.GetType())
+		c.field("interactor", "", "runtime", old.GetRuntime(), new.GetRuntime())
+		c.text("interactor", "", "source", old.GetSource(), new.GetSource())
+		c.files("interactor", "", old.GetFiles(), new.GetFiles())
+	}
+}
+
+func (c *comparer) statements(old, new []*atlaspb.Statement) {
+	compare(c, "statement", old, new, (*atlaspb.Statement).GetLocale, func(key string, o, n *atlaspb.Statement) {
+		c.field("statement", key, "title", o.GetTitle(), n.GetTitle())
+		c.field("statement", key, "author", o.GetAuthor(), n.GetAuthor())
+		c.field("statement", key, "source", o.GetSource(), n.GetSource())
+		c.text("statement", key, "content", content(o.GetContent()), content(n.GetContent()))
+	})
+}
+
+func (c *comparer) editorials(old, new []*atlaspb.Editorial) {
+	compare(c, "editorial", old, new, (*atlaspb.Editorial).GetLocale, func(key string, o, n *atlaspb.Editorial) {
+		c.text("editorial", key, "content", content(o.GetContent()), content(n.GetContent()))
+	})
+}
+
+func (c *comparer) testsets(old, new []*atlaspb.Testset) {
+	index := func(ts *atlaspb.Testset) string { return fmt.Sprint(ts.GetIndex()) }
+
+	compare(c, "testset", old, new, index, func(key string, o, n *atlaspb.Testset) {
+		c.field("testset", key, "time_limit", o.GetTimeLimit(), n.GetTimeLimit())
+		c.field("testset", key, "cpu_limit", o.GetCpuLimit(), n.GetCpuLimit())
+		c.field("testset", key, "memory_limit", o.GetMemoryLimit(), n.GetMemoryLimit())
+		c.field("testset", key, "file_size_limit", o.GetFileSizeLimit(), n.GetFileSizeLimit())
+		c.field("testset", key, "scoring_mode", o.GetScoringMode(), n.GetScoringMode())
+		c.field("testset", key, "feedback_policy", o.GetFeedbackPolicy(), n.GetFeedbackPolicy())
+		c.field("testset", key, "dependency_mode", o.GetDependencyMode(), n.GetDependencyMode())
+		c.field("testset", key, "dependencies", o.GetDependencies(), n.GetDependencies())
+	})
+}
+
+// tests are matched by testset and test index first, unmatched tests are then matched by content to detect tests
+// which have been moved or renumbered
+func (c *comparer) tests(old, new *atlaspb.Snapshot) {
+	okeys, otests := testKeys(old)
+	nkeys, ntests := testKeys(new)
+
+	var removed, added []string
+
+	for _, key := range okeys {
+		o := otests[key]
+		n, ok := ntests[key]
+		if !ok {
+			removed = append(removed, key)
+			continue
+		}
+
+		c.field("test", key, "score", o.GetScore(), n.GetScore())
+		c.field("test", key, "example", o.GetExample(), n.GetExample())
+		c.field("test", key, "inactive", o.GetInactive(), n.GetInactive())
+		c.field("test", key, "input", c.input(o), c.input(n))
+		c.field("test", key, "answer", c.answer(o), c.answer(n))
+		c.field("test", key, "example_input", c.link(o.GetExampleInputUrl()), c.link(n.GetExampleInputUrl()))
+		c.field("test", key, "example_answer", c.link(o.GetExampleAnswerUrl()), c.link(n.GetExampleAnswerUrl()))
+	}
+
+	for _, key := range nkeys {
+		if _, ok := otests[key]; !ok {
+			added = append(added, key)
+		}
+	}
+
+	// match removed and added tests by content
+	moved := map[string]string{} // new key -> old key
+	byContent := map[string][]string{}
+	for _, key := range removed {
+		hash := c.input(otests[key]) + "\n" + c.answer(otests[key])
+		byContent[hash] = append(byContent[hash], key)
+	}
+
+	for _, key := range added {
+		hash := c.input(ntests[key]) + "\n" + c.answer(ntests[key])
+		if candidates := byContent[hash]; len(candidates) > 0 {
+			moved[key] = candidates[0]
+			byContent[hash] = candidates[1:]
+		}
+	}
+
+	matched := map[string]bool{}
+	for _, key := range moved {
+		matched[key] = true
+	}
+
+	for _, key := range removed {
+		if !matched[key] {
+			c.add(Removed, "test", key)
+		}
+	}
+
+	for _, key := range added {
+		if from, ok := moved[key]; ok {
+			c.changes = append(c.changes, Change{Kind: Moved, Section: "test", Key: key, Old: from, New: key})
+			c.field("test", key, "score", otests[from].GetScore(), ntests[key].GetScore())
+			c.field("test", key, "example", otests[from].GetExample(), ntests[key].GetExample())
+			continue
+		}
+
+		c.add(Added, "test", key)
+	}
+}
+
+func (c *comparer) solutions(old, new []*atlaspb.Solution) {
+	compare(c, "solution", old, new, (*atlaspb.Solution).GetName, func(key string, o, n *atlaspb.Solution) {
+		c.field("solution", key, "type", o.GetType(), n.GetType())
+		c.field("solution", key, "runtime", o.GetRuntime(), n.GetRuntime())
+		c.text("solution", key, "source", o.GetSource(), n.GetSource())
+	})
+}
+
+func (c *comparer) scripts(old, new []*atlaspb.Script) {
+	compare(c, "script", old, new, (*atlaspb.Script).GetName, func(key string, o, n *atlaspb.Script) {
+		c.field("script", key, "runtime", o.GetRuntime(), n.GetRuntime())
+		c.text("script", key, "source", o.GetSource(), n.GetSource())
+		c.files("script", key, o.GetFiles(), n.GetFiles())
+	})
+}
+
+func (c *comparer) templates(old, new []*atlaspb.Template) {
+	compare(c, "template", old, new, (*atlaspb.Template).GetRuntime, func(key string, o, n *atlaspb.Template) {
+		c.text("template", key, "source", o.GetSource(), n.GetSource())
+		c.text("template", key, "header", o.GetHeader(), n.GetHeader())
+		c.text("template", key, "footer", o.GetFooter(), n.GetFooter())
+		c.files("template", key, o.GetFiles(), n.GetFiles())
+	})
+}
+
+func (c *comparer) attachments(old, new []*atlaspb.Attachment) {
+	compare(c, "attachment", old, new, (*atlaspb.Attachment).GetName, func(key string, o, n *atlaspb.Attachment) {
+		c.field("attachment", key, "link", c.link(o.GetLink()), c.link(n.GetLink()))
+	})
+}
+
+// compare matches items from two lists by key, reports added and removed items, and calls fn for items present in
+// both lists. Changes are reported in the order of keys in new list, removed items go first.
+func compare[T any](c *comparer, section string, old, new []T, key func(T) string, fn func(key string, o, n T)) {
+	okeys, oitems := index(old, key)
+	nkeys, nitems := index(new, key)
+
+	for _, k := range okeys {
+		if _, ok := nitems[k]; !ok {
+			c.add(Removed, section, k)
+		}
+	}
+
+	for _, k := range nkeys {
+		o, ok := oitems[k]
+		if !ok {
+			c.add(Added, section, k)
+			continue
+		}
+
+		fn(k, o, nitems[k])
+	}
+}
+
+func index[T any](items []T, key func(T) string) (keys []string, byKey map[string]T) {
+	byKey = map[string]T{}
+	for _, item := range items {
+		k := key(item)
+		if _, ok := byKey[k]; ok {
+			continue
+		}
+
+		keys = append(keys, k)
+		byKey[k] = item
+	}
+
+	return
+}
+
+// testKeys builds "<testset index>/<test index>" keys for tests, since testset IDs are random
+func testKeys(snap *atlaspb.Snapshot) (keys []string, tests map[string]*atlaspb.Test) {
+	testsets := map[string]uint32{}
+	for _, testset := range snap.GetTestsets() {
+		testsets[testset.GetId()] = testset.GetIndex()
+	}
+
+	tests = map[string]*atlaspb.Test{}
+	for _, test := range snap.GetTests() {
+		key := fmt.Sprintf("%d/%d", testsets[test.GetTestsetId()], test.GetIndex())
+		if _, ok := tests[key]; ok {
+			continue
+		}
+
+		keys = append(keys, key)
+		tests[key] = test
+	}
+
+	return
+}
+
+func (c *comparer) input(test *atlaspb.Test) string {
+	if gen := test.GetInputGenerator(); gen != nil {
+		return "generator:" + strings.Join(append([]string{gen.GetScriptName()}, gen.GetArguments()...), " ")
+	}
+
+	return c.link(test.GetInputUrl())
+}
+
+func (c *comparer) answer(test *atlaspb.Test) string {
+	if gen := test.GetAnswerGenerator(); gen != nil {
+		return "generator:" + strings.Join(append([]string{gen.GetScriptName()}, gen.GetArguments()...), " ")
+	}
+
+	return c.link(test.GetAnswerUrl())
+}
+
+func content(c *ecmpb.Content) string {
+	switch v := c.GetValue().(type) {
+	case *ecmpb.Content_Latex:
+		return v.Latex
+	case *ecmpb.Content_Html:
+		return v.Html
+	case *ecmpb.Content_Markdown:
+		return v.Markdown
+	default:
+		data, _ := proto.MarshalOptions{Deterministic: true}.Marshal(c)
+		return string(data)
+	}
+}
+
+func (c *comparer) filelist(files []*executorpb.File) string {
+	var parts []string
+	for _, file := range files {
+		parts = append(parts, file.GetPath()+"="+c.link(file.GetSourceUrl()))
+	}
+
+	sort.Strings(parts)
+
+	return strings.Join(parts, ", ")
+}
+
+// missing returns items from a which are not in b
+func missing(a, b []string) (items []string) {
+	set := map[string]bool{}
+	for _, item := range b {
+		set[item] = true
+	}
+
+	for _, item := range a {
+		if !set[item] {
+			items = append(items, item)
+		}
+	}
+
+	return
+}
+
+// digest returns short hash of a large value, so it can be shown to the user
+func digest(v string) string {
+	if v == "" {
+		return ""
+	}
+
+	hash := sha256.Sum256([]byte(v))
+
+	return fmt.Sprintf("sha256:%x (%d bytes)", hash[:6], len(v))
+}
@@ -0,0 +1,138 @@
+package diff
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	"golang.org/x/sync/errgroup"
+)
+
+// Hasher returns digest of the content behind a link, so the same content uploaded twice (and so having different
+// links) is recognized as identical.
+type Hasher interface {
+	Hash(ctx context.Context, link string) (string, error)
+}
+
+// HTTPHasher downloads content over HTTP and hashes it using SHA-256.
+type HTTPHasher struct {
+	Client *http.Client
+}
+
+func (h HTTPHasher) Hash(ctx context.Context, link string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to compose HTTP request: %w", err)
+	}
+
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("response status code is not OK: %v", resp.Status)
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, resp.Body); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("sha256:%x", hash.Sum(nil)), nil
+}
+
+// CompareContent is like Compare, but test data, attachments and files are compared by digests of their content
+// instead of links. Use it when snapshots are imported separately, since every import uploads assets under new links.
+func CompareContent(ctx context.Context, old, new *atlaspb.Snapshot, hasher Hasher) (*Diff, error) {
+	links := map[string]bool{}
+	for _, snap := range []*atlaspb.Snapshot{old, new} {
+		for _, link := range assets(snap) {
+			links[link] = true
+		}
+	}
+
+	var lock sync.Mutex
+	digests := map[string]string{}
+
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(8)
+
+	for link := range links {
+		eg.Go(func() error {
+			digest, err := hasher.Hash(ctx, link)
+			if err != nil {
+				return fmt.Errorf("unable to hash %v: %w", link, err)
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			digests[link] = digest
+			return nil
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	return compareSnapshots(&comparer{digests: digests}, old, new), nil
+}
+
+// assets returns links to content which is compared by digest
+func assets(snap *atlaspb.Snapshot) (links []string) {
+	add := func(link string) {
+		if link != "" {
+			links = append(links, link)
+		}
+	}
+
+	for _, test := range snap.GetTests() {
+		add(test.GetInputUrl())
+		add(test.GetAnswerUrl())
+		add(test.GetExampleInputUrl())
+		add(test.GetExampleAnswerUrl())
+	}
+
+	for _, attachment := range snap.GetAttachments() {
+		add(attachment.GetLink())
+	}
+
+	for _, file := range snap.GetChecker().GetFiles() {
+		add(file.GetSourceUrl())
+	}
+
+	for _, file := range snap.GetValidator().GetFiles() {
+		add(file.GetSourceUrl())
+	}
+
+	for _, file := range snap.GetInteractor().GetFiles() {
+		add(file.GetSourceUrl())
+	}
+
+	for _, script := range snap.GetScripts() {
+		for _, file := range script.GetFiles() {
+			add(file.GetSourceUrl())
+		}
+	}
+
+	for _, template := range snap.GetTemplates() {
+		for _, file := range template.GetFiles() {
+			add(file.GetSourceUrl())
+		}
+	}
+
+	return
+}
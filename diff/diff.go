@@ -0,0 +1,107 @@
+// Package diff compares two problem snapshots semantically.
+//
+// Unlike comparing protobuf messages directly, diff ignores identifiers assigned at import time (testset and test
+// IDs) and matches entities by their natural keys: statements and editorials by locale, testsets by index, tests by
+// testset and test index (falling back to content), solutions and scripts by name, templates by runtime.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind of change.
+type Kind string
+
+const (
+	Added   Kind = "added"
+	Removed Kind = "removed"
+	Changed Kind = "changed"
+	Moved   Kind = "moved"
+)
+
+// Change describes a single difference between two snapshots.
+type Change struct {
+	Kind    Kind   `json:"kind"`
+	Section string `json:"section"`         // problem, testing, checker, statement, testset, test, solution etc.
+	Key     string `json:"key,omitempty"`   // identifies entity within the section, e.g. locale or solution name
+	Field   string `json:"field,omitempty"` // name of the changed field, only for Changed
+	Old     string `json:"old,omitempty"`   // old value (or its digest for large values)
+	New     string `json:"new,omitempty"`   // new value (or its digest for large values)
+}
+
+func (c Change) String() string {
+	subject := c.Section
+	if c.Key != "" {
+		subject += " " + c.Key
+	}
+
+	switch c.Kind {
+	case Added:
+		return "+ " + subject
+	case Removed:
+		return "- " + subject
+	case Moved:
+		return fmt.Sprintf("> %v moved from %v", subject, c.Old)
+	default:
+		return fmt.Sprintf("~ %v: %v %v → %v", subject, c.Field, display(c.Old), display(c.New))
+	}
+}
+
+// Diff is a list of changes between two snapshots.
+type Diff struct {
+	Changes []Change `json:"changes"`
+}
+
+// Empty returns true if snapshots are semantically identical.
+func (d *Diff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// Section returns changes in the given section.
+func (d *Diff) Section(name string) (changes []Change) {
+	for _, change := range d.Changes {
+		if change.Section == name {
+			changes = append(changes, change)
+		}
+	}
+
+	return
+}
+
+// Summary returns human-readable description of the changes, one change per line, preceded by totals.
+func (d *Diff) Summary() string {
+	if d.Empty() {
+		return "No changes\n"
+	}
+
+	var added, removed, changed int
+	for _, change := range d.Changes {
+		switch change.Kind {
+		case Added:
+			added++
+		case Removed:
+			removed++
+		default:
+			changed++
+		}
+	}
+
+	b := &strings.Builder{}
+	_, _ = fmt.Fprintf(b, "%d added, %d removed, %d changed\n", added, removed, changed)
+
+	for _, change := range d.Changes {
+		b.WriteString(change.String())
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func display(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+
+	return v
+}
@@ -0,0 +1,177 @@
+package diff
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	ecmpb "github.com/eolymp/go-sdk/eolymp/ecm"
+	"github.com/google/go-cmp/cmp"
+)
+
+func snapshot(testset string) *atlaspb.Snapshot {
+	return &atlaspb.Snapshot{
+		Problem: &atlaspb.Problem{Topics: []string{"dp"}},
+		Checker: &atlaspb.Checker{Runtime: "cpp:17-gnu10", Source: "checker"},
+		Statements: []*atlaspb.Statement{
+			{Locale: "en", Title: "Sum", Content: &ecmpb.Content{Value: &ecmpb.Content_Latex{Latex: "Find sum"}}},
+		},
+		Testsets: []*atlaspb.Testset{
+			{Id: testset + "0", Index: 0, CpuLimit: 1000},
+			{Id: testset + "1", Index: 1, CpuLimit: 1000, Dependencies: []uint32{0}},
+		},
+		Tests: []*atlaspb.Test{
+			{Id: testset + "t1", TestsetId: testset + "0", Index: 1, Example: true, Input: &atlaspb.Test_InputUrl{InputUrl: "in1"}, Answer: &atlaspb.Test_AnswerUrl{AnswerUrl: "ans1"}},
+			{Id: testset + "t2", TestsetId: testset + "1", Index: 2, Score: 50, Input: &atlaspb.Test_InputUrl{InputUrl: "in2"}, Answer: &atlaspb.Test_AnswerUrl{AnswerUrl: "ans2"}},
+			{Id: testset + "t3", TestsetId: testset + "1", Index: 3, Score: 50, Input: &atlaspb.Test_InputUrl{InputUrl: "in3"}, Answer: &atlaspb.Test_AnswerUrl{AnswerUrl: "ans3"}},
+		},
+		Solutions: []*atlaspb.Solution{
+			{Name: "main.cpp", Runtime: "cpp:17-gnu10", Source: "main", Type: atlaspb.Solution_CORRECT},
+		},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Run("identical snapshots with different IDs", func(t *testing.T) {
+		got := Compare(snapshot("a"), snapshot("b"))
+		if !got.Empty() {
+			t.Errorf("Snapshots must be identical, got:\n%s", got.Summary())
+		}
+	})
+
+	t.Run("changes", func(t *testing.T) {
+		old := snapshot("a")
+		new := snapshot("b")
+
+		new.Problem.Topics = []string{"greedy"}
+		new.Checker.Source = "new checker"
+		new.Statements[0].Title = "Array Sum"
+		new.Statements = append(new.Statements, &atlaspb.Statement{Locale: "uk", Title: "Сума"})
+		new.Testsets[1].CpuLimit = 2000
+		new.Tests[1].Score = 40
+		new.Tests = append(new.Tests, &atlaspb.Test{TestsetId: "b1", Index: 4, Score: 10, Input: &atlaspb.Test_InputUrl{InputUrl: "in4"}, Answer: &atlaspb.Test_AnswerUrl{AnswerUrl: "ans4"}})
+		new.Solutions = append(new.Solutions, &atlaspb.Solution{Name: "wa.cpp", Runtime: "cpp:17-gnu10", Type: atlaspb.Solution_WRONG_ANSWER})
+
+		got := Compare(old, new).Changes
+		want := []Change{
+			{Kind: Removed, Section: "topic", Key: "dp"},
+			{Kind: Added, Section: "topic", Key: "greedy"},
+			{Kind: Changed, Section: "checker", Field: "source", Old: "sha256:d2d2328e3359 (7 bytes)", New: "sha256:7c5108de09dd (11 bytes)"},
+			{Kind: Changed, Section: "statement", Key: "en", Field: "title", Old: "Sum", New: "Array Sum"},
+			{Kind: Added, Section: "statement", Key: "uk"},
+			{Kind: Changed, Section: "testset", Key: "1", Field: "cpu_limit", Old: "1000", New: "2000"},
+			{Kind: Changed, Section: "test", Key: "1/2", Field: "score", Old: "50", New: "40"},
+			{Kind: Added, Section: "test", Key: "1/4"},
+			{Kind: Added, Section: "solution", Key: "wa.cpp"},
+		}
+
+		if !cmp.Equal(want, got) {
+			t.Errorf("Changes do not match:\n%s", cmp.Diff(want, got))
+		}
+	})
+
+	t.Run("moved test", func(t *testing.T) {
+		old := snapshot("a")
+		new := snapshot("b")
+
+		// move last test into a new testset
+		new.Testsets = append(new.Testsets, &atlaspb.Testset{Id: "b2", Index: 2, CpuLimit: 1000})
+		new.Tests[2].TestsetId = "b2"
+
+		got := Compare(old, new).Changes
+		want := []Change{
+			{Kind: Added, Section: "testset", Key: "2"},
+			{Kind: Moved, Section: "test", Key: "2/3", Old: "1/3", New: "2/3"},
+		}
+
+		if !cmp.Equal(want, got) {
+			t.Errorf("Changes do not match:\n%s", cmp.Diff(want, got))
+		}
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		new := snapshot("b")
+		new.Testsets[0].CpuLimit = 500
+		new.Solutions = nil
+
+		got := Compare(snapshot("a"), new).Summary()
+		want := "0 added, 1 removed, 1 changed\n~ testset 0: cpu_limit 1000 → 500\n- solution main.cpp\n"
+
+		if want != got {
+			t.Errorf("Summary does not match:\n%s", cmp.Diff(want, got))
+		}
+	})
+}
+
+func TestCompareContent(t *testing.T) {
+	ctx := context.Background()
+
+	// the same content is available under different links, like after importing the problem twice
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(path.Base(r.URL.Path)))
+	}))
+
+	defer server.Close()
+
+	uploaded := func(snap *atlaspb.Snapshot, upload string) *atlaspb.Snapshot {
+		for _, test := range snap.Tests {
+			test.Input = &atlaspb.Test_InputUrl{InputUrl: server.URL + "/" + upload + "/" + test.GetInputUrl()}
+			test.Answer = &atlaspb.Test_AnswerUrl{AnswerUrl: server.URL + "/" + upload + "/" + test.GetAnswerUrl()}
+		}
+
+		return snap
+	}
+
+	t.Run("identical content behind different links", func(t *testing.T) {
+		old := uploaded(snapshot("a"), "first")
+		new := uploaded(snapshot("b"), "second")
+
+		if got := Compare(old, new); got.Empty() {
+			t.Fatal("Compare must report changed links")
+		}
+
+		got, err := CompareContent(ctx, old, new, HTTPHasher{Client: server.Client()})
+		if err != nil {
+			t.Fatal("CompareContent has failed:", err)
+		}
+
+		if !got.Empty() {
+			t.Errorf("Snapshots must be identical, got:\n%s", got.Summary())
+		}
+	})
+
+	t.Run("moved test", func(t *testing.T) {
+		old := uploaded(snapshot("a"), "first")
+		new := uploaded(snapshot("b"), "second")
+
+		new.Testsets = append(new.Testsets, &atlaspb.Testset{Id: "b2", Index: 2, CpuLimit: 1000})
+		new.Tests[2].TestsetId = "b2"
+
+		got, err := CompareContent(ctx, old, new, HTTPHasher{Client: server.Client()})
+		if err != nil {
+			t.Fatal("CompareContent has failed:", err)
+		}
+
+		want := []Change{
+			{Kind: Added, Section: "testset", Key: "2"},
+			{Kind: Moved, Section: "test", Key: "2/3", Old: "1/3", New: "2/3"},
+		}
+
+		if !cmp.Equal(want, got.Changes) {
+			t.Errorf("Changes do not match:\n%s", cmp.Diff(want, got.Changes))
+		}
+	})
+
+	t.Run("unavailable content", func(t *testing.T) {
+		old := uploaded(snapshot("a"), "first")
+		new := snapshot("b")
+		new.Tests[0].Input = &atlaspb.Test_InputUrl{InputUrl: "http://127.0.0.1:0/in1"}
+
+		if _, err := CompareContent(ctx, old, new, HTTPHasher{Client: server.Client()}); err == nil {
+			t.Error("CompareContent must fail when content can not be downloaded")
+		}
+	})
+}
@@ -0,0 +1,209 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/eolymp/go-problems/connector"
+	"github.com/eolymp/go-problems/transform"
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+)
+
+// Batch imports several problems at once. Problems are fetched concurrently, all loaders share a single upload
+// concurrency budget and a cache of uploaded assets, so files repeated across problems are uploaded once.
+type Batch struct {
+	upload   connector.Uploader
+	log      connector.Logger
+	problems int
+	uploads  int
+	format   Format
+	pipeline *transform.Pipeline
+	options  []Option
+}
+
+// BatchResult describes import of a single problem in the batch.
+type BatchResult struct {
+	Link       string                `json:"link"`
+	Format     Format                `json:"format,omitempty"`
+	Snapshot   *atlaspb.Snapshot     `json:"-"`
+	Provenance *connector.Provenance `json:"provenance,omitempty"`
+	Warnings   []connector.Warning   `json:"warnings,omitempty"`
+	Error      error                 `json:"-"`
+	Duration   time.Duration         `json:"duration"`
+}
+
+// UseProblemConcurrency sets number of problems fetched concurrently, 4 by default.
+func UseProblemConcurrency(n int) func(*Batch) {
+	return func(b *Batch) {
+		b.problems = n
+	}
+}
+
+// UseUploadConcurrency sets number of concurrent upload calls shared by all problems, 10 by default.
+func UseUploadConcurrency(n int) func(*Batch) {
+	return func(b *Batch) {
+		b.uploads = n
+	}
+}
+
+// UseFormat disables format detection and imports all problems in the given format.
+func UseFormat(format Format) func(*Batch) {
+	return func(b *Batch) {
+		b.format = format
+	}
+}
+
+// UseTransform applies the pipeline to each imported snapshot.
+func UseTransform(pipeline *transform.Pipeline) func(*Batch) {
+	return func(b *Batch) {
+		b.pipeline = pipeline
+	}
+}
+
+// UseImportOptions configures loaders used by the batch.
+func UseImportOptions(opts ...Option) func(*Batch) {
+	return func(b *Batch) {
+		b.options = append(b.options, opts...)
+	}
+}
+
+func NewBatch(upload connector.Uploader, log connector.Logger, opts ...func(*Batch)) *Batch {
+	b := &Batch{upload: upload, log: log, problems: 4, uploads: 10}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// Import fetches all problems. Failure to import one problem does not stop the others, results are returned in the
+// same order as links.
+func (b *Batch) Import(ctx context.Context, links []string) []BatchResult {
+	upload := connector.NewCachedUploader(connector.NewLimitedUploader(b.upload, b.uploads))
+	results := make([]BatchResult, len(links))
+
+	slots := make(chan struct{}, max(b.problems, 1))
+	wg := sync.WaitGroup{}
+
+	for i, link := range links {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			results[i] = b.fetch(ctx, link, upload, &prefixLogger{prefix: fmt.Sprintf("[%d] ", i+1), log: b.log})
+		}()
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+func (b *Batch) fetch(ctx context.Context, link string, upload connector.Uploader, log connector.Logger) (result BatchResult) {
+	result.Link = link
+
+	start := time.Now()
+	defer func() {
+		result.Duration = time.Since(start)
+
+		if r := recover(); r != nil {
+			result.Error = fmt.Errorf("loader has panicked: %v", r)
+		}
+
+		if result.Error != nil {
+			log.Errorf("Import of %v has failed: %v", connector.Redact(link), result.Error)
+		}
+	}()
+
+	if err := ctx.Err(); err != nil {
+		result.Error = err
+		return
+	}
+
+	result.Format = b.format
+	if result.Format == "" {
+		result.Format, result.Error = Detect(link)
+		if result.Error != nil {
+			return
+		}
+	}
+
+	imported, err := Import(ctx, result.Format, link, upload, log, b.options...)
+	if err != nil {
+		result.Error = err
+		return
+	}
+
+	result.Provenance = imported.Provenance
+	result.Warnings = imported.Warnings
+
+	if err := b.pipeline.Apply(imported.Snapshot); err != nil {
+		result.Error = err
+		return
+	}
+
+	result.Snapshot = imported.Snapshot
+
+	return
+}
+
+// Failed returns number of problems which failed to import.
+func Failed(results []BatchResult) (n int) {
+	for _, result := range results {
+		if result.Error != nil {
+			n++
+		}
+	}
+
+	return
+}
+
+// WriteTable prints results as a table, one row per problem.
+func WriteTable(w io.Writer, results []BatchResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "#\tLINK\tFORMAT\tSTATUS\tTESTS\tWARNINGS\tDURATION\tERROR")
+
+	for i, result := range results {
+		status := "ok"
+		if result.Error != nil {
+			status = "failed"
+		}
+
+		errmsg := "-"
+		if result.Error != nil {
+			errmsg = result.Error.Error()
+		}
+
+		format := string(result.Format)
+		if format == "" {
+			format = "-"
+		}
+
+		fmt.Fprintf(tw, "%d\t%v\t%v\t%v\t%d\t%d\t%v\t%v\n", i+1, connector.Redact(result.Link), format, status, len(result.Snapshot.GetTests()), len(result.Warnings), result.Duration.Round(time.Millisecond), errmsg)
+	}
+
+	return tw.Flush()
+}
+
+// prefixLogger marks log lines with problem number
+type prefixLogger struct {
+	prefix string
+	log    connector.Logger
+}
+
+func (l *prefixLogger) Printf(format string, args ...any) {
+	l.log.Printf(l.prefix+format, args...)
+}
+
+func (l *prefixLogger) Errorf(format string, args ...any) {
+	l.log.Errorf(l.prefix+format, args...)
+}
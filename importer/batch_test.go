@@ -0,0 +1,113 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/eolymp/go-problems/connector/testing"
+	assetpb "github.com/eolymp/go-sdk/eolymp/asset"
+	"google.golang.org/grpc"
+)
+
+// countingUploader counts uploads and tracks maximum number of concurrent calls
+type countingUploader struct {
+	*TestUploader
+	uploads atomic.Int32
+
+	lock    sync.Mutex
+	current int
+	peak    int
+}
+
+func (u *countingUploader) enter() func() {
+	u.lock.Lock()
+	u.current++
+	u.peak = max(u.peak, u.current)
+	u.lock.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	return func() {
+		u.lock.Lock()
+		u.current--
+		u.lock.Unlock()
+	}
+}
+
+func (u *countingUploader) UploadAsset(ctx context.Context, in *assetpb.UploadAssetInput, opts ...grpc.CallOption) (*assetpb.UploadAssetOutput, error) {
+	defer u.enter()()
+	u.uploads.Add(1)
+	return u.TestUploader.UploadAsset(ctx, in, opts...)
+}
+
+func (u *countingUploader) UploadPart(ctx context.Context, in *assetpb.UploadPartInput, opts ...grpc.CallOption) (*assetpb.UploadPartOutput, error) {
+	defer u.enter()()
+	return u.TestUploader.UploadPart(ctx, in, opts...)
+}
+
+func (u *countingUploader) CompleteMultipartUpload(ctx context.Context, in *assetpb.CompleteMultipartUploadInput, opts ...grpc.CallOption) (*assetpb.CompleteMultipartUploadOutput, error) {
+	defer u.enter()()
+	u.uploads.Add(1)
+	return u.TestUploader.CompleteMultipartUpload(ctx, in, opts...)
+}
+
+func TestBatch_Import(t *testing.T) {
+	ctx := context.Background()
+
+	single := &countingUploader{TestUploader: MockUploader()}
+	if _, err := FetchAs(ctx, FormatPolygon, "../polygon/.testdata/03-test-scoring-with-points", single, MockLogger(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	upload := &countingUploader{TestUploader: MockUploader()}
+	batch := NewBatch(upload, MockLogger(t), UseUploadConcurrency(2), UseProblemConcurrency(3))
+
+	results := batch.Import(ctx, []string{
+		"../polygon/.testdata/03-test-scoring-with-points",
+		"../polygon/.testdata/03-test-scoring-with-points",
+		"../polygon/.testdata/does-not-exist",
+		"../kattis/problems/scoring",
+	})
+
+	if len(results) != 4 {
+		t.Fatalf("batch must return 4 results, got %v", len(results))
+	}
+
+	if Failed(results) != 1 || results[2].Error == nil {
+		t.Errorf("only the third problem must fail, got %v failures", Failed(results))
+	}
+
+	for i, result := range results {
+		if i != 2 && (result.Error != nil || len(result.Snapshot.GetTests()) == 0) {
+			t.Errorf("problem #%d must be imported, got error %v", i+1, result.Error)
+		}
+	}
+
+	if upload.peak > 2 {
+		t.Errorf("at most 2 concurrent uploads are allowed, got %v", upload.peak)
+	}
+
+	kattis := &countingUploader{TestUploader: MockUploader()}
+	if _, err := FetchAs(ctx, FormatKattis, "../kattis/problems/scoring", kattis, MockLogger(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	// identical problems share uploads
+	if got, limit := upload.uploads.Load(), single.uploads.Load()+kattis.uploads.Load(); got > limit {
+		t.Errorf("duplicate files must be uploaded once, got %v uploads, expected at most %v", got, limit)
+	}
+
+	var table bytes.Buffer
+	if err := WriteTable(&table, results); err != nil {
+		t.Fatal(err)
+	}
+
+	if lines := strings.Count(table.String(), "\n"); lines != 5 {
+		t.Errorf("table must have a header and 4 rows, got:\n%v", table.String())
+	}
+}
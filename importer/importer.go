@@ -0,0 +1,297 @@
+// Package importer picks the right problem loader for a link or a local path.
+package importer
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eolymp/go-problems/connector"
+	"github.com/eolymp/go-problems/kattis"
+	"github.com/eolymp/go-problems/polygon"
+	"github.com/eolymp/go-problems/runtimes"
+	"github.com/eolymp/go-problems/topics"
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+)
+
+// Format of the problem package.
+type Format string
+
+const (
+	FormatPolygon Format = "polygon"
+	FormatKattis  Format = "kattis"
+)
+
+// ParseFormat checks if format is supported.
+func ParseFormat(format string) (Format, error) {
+	switch f := Format(format); f {
+	case FormatPolygon, FormatKattis:
+		return f, nil
+	default:
+		return "", fmt.Errorf("format %#v is not supported", format)
+	}
+}
+
+// Loader fetches problem and converts it into a snapshot.
+type Loader interface {
+	Fetch(ctx context.Context, link string) (*atlaspb.Snapshot, error)
+	Snapshot(ctx context.Context, path string) (*atlaspb.Snapshot, error)
+	Import(ctx context.Context, link string) (*connector.Result, error)
+	ImportDir(ctx context.Context, path string) (*connector.Result, error)
+}
+
+// Option configures loaders created by New.
+type Option func(*options)
+
+type options struct {
+	registry *runtimes.Registry
+	topics   *topics.Mapper
+	mode     polygon.Mode
+	policy   polygon.PackagePolicy
+	build    *polygon.BuildOptions
+	roles    map[string]polygon.TestsetRole
+	client   []func(*polygon.Client)
+}
+
+// UseRuntimes sets runtime registry used by loaders.
+func UseRuntimes(registry *runtimes.Registry) Option {
+	return func(o *options) {
+		o.registry = registry
+	}
+}
+
+// UseTopics sets mapping of tags to Eolymp topics used by loaders.
+func UseTopics(mapper *topics.Mapper) Option {
+	return func(o *options) {
+		o.topics = mapper
+	}
+}
+
+// UsePolygonMode sets how problems are fetched through Polygon API: by downloading a package or by assembling them
+// from API calls.
+func UsePolygonMode(mode polygon.Mode) Option {
+	return func(o *options) {
+		o.mode = mode
+	}
+}
+
+// UsePackagePolicy sets which Polygon packages are imported.
+func UsePackagePolicy(policy polygon.PackagePolicy) Option {
+	return func(o *options) {
+		o.policy = policy
+	}
+}
+
+// UsePackageBuild makes Polygon loader build a package when there is no ready package for the latest revision.
+func UsePackageBuild(opts polygon.BuildOptions) Option {
+	return func(o *options) {
+		o.build = &opts
+	}
+}
+
+// UseTestsetRoles sets how Polygon testsets are imported, by their names.
+func UseTestsetRoles(roles map[string]polygon.TestsetRole) Option {
+	return func(o *options) {
+		o.roles = roles
+	}
+}
+
+// UsePolygonClient sets options of the Polygon API client, e.g. rate limit or retries.
+func UsePolygonClient(opts ...func(*polygon.Client)) Option {
+	return func(o *options) {
+		o.client = append(o.client, opts...)
+	}
+}
+
+// New creates loader for the format.
+func New(format Format, upload connector.Uploader, log connector.Logger, opts ...Option) (Loader, error) {
+	o := &options{registry: runtimes.Default(), topics: topics.Default()}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	switch format {
+	case FormatPolygon:
+		popts := []func(*polygon.ProblemLoader){polygon.UseRuntimes(o.registry), polygon.UseTopics(o.topics), polygon.UseMode(o.mode), polygon.UsePackagePolicy(o.policy), polygon.UsePolygonOptions(o.client...)}
+		if o.build != nil {
+			popts = append(popts, polygon.UsePackageBuild(*o.build))
+		}
+
+		if o.roles != nil {
+			popts = append(popts, polygon.UseTestsetRoles(o.roles))
+		}
+
+		return polygon.NewProblemLoader(upload, log, popts...), nil
+	case FormatKattis:
+		return kattis.NewProblemLoader(upload, log, kattis.UseRuntimes(o.registry), kattis.UseTopics(o.topics)), nil
+	default:
+		return nil, fmt.Errorf("format %#v is not supported", format)
+	}
+}
+
+// Detect figures out problem format from the link. Links to local files and directories are inspected to find
+// problem.xml (Polygon) or problem.yaml (Kattis).
+//
+// Supported links:
+//   - polygon://api-key:api-secret@/?problemId=123
+//   - https://polygon.codeforces.com/... (problem package link)
+//   - https://example.com/problem.zip (Kattis problem package)
+//   - file:///path/to/problem.zip, /path/to/problem.zip or /path/to/problem
+func Detect(link string) (Format, error) {
+	if path, ok := Local(link); ok {
+		return detectPath(path)
+	}
+
+	origin, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("invalid problem origin: %w", err)
+	}
+
+	switch {
+	case origin.Scheme == "polygon":
+		return FormatPolygon, nil
+	case origin.Scheme == "https" && origin.Hostname() == "polygon.codeforces.com":
+		return FormatPolygon, nil
+	case origin.Scheme == "http" || origin.Scheme == "https":
+		return FormatKattis, nil
+	default:
+		return "", fmt.Errorf("invalid problem origin: schema %#v is not supported", origin.Scheme)
+	}
+}
+
+// Fetch detects problem format and fetches the problem using an appropriate loader.
+func Fetch(ctx context.Context, link string, upload connector.Uploader, log connector.Logger, opts ...Option) (*atlaspb.Snapshot, Format, error) {
+	format, err := Detect(link)
+	if err != nil {
+		return nil, "", err
+	}
+
+	snap, err := FetchAs(ctx, format, link, upload, log, opts...)
+	return snap, format, err
+}
+
+// FetchAs fetches the problem in a given format. Local directories are read in place, local archives are unpacked.
+func FetchAs(ctx context.Context, format Format, link string, upload connector.Uploader, log connector.Logger, opts ...Option) (*atlaspb.Snapshot, error) {
+	result, err := Import(ctx, format, link, upload, log, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Snapshot, nil
+}
+
+// Import works like FetchAs, but also returns provenance of the snapshot.
+func Import(ctx context.Context, format Format, link string, upload connector.Uploader, log connector.Logger, opts ...Option) (*connector.Result, error) {
+	loader, err := New(format, upload, log, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	path, ok := Local(link)
+	if !ok {
+		return loader.Import(ctx, link)
+	}
+
+	if stat, err := os.Stat(path); err == nil && stat.IsDir() {
+		return loader.ImportDir(ctx, root(path, format))
+	}
+
+	return loader.Import(ctx, (&url.URL{Scheme: "file", Path: path}).String())
+}
+
+// Local returns path if the link points to a local file or directory.
+func Local(link string) (string, bool) {
+	if strings.HasPrefix(link, "file://") {
+		origin, err := url.Parse(link)
+		if err != nil {
+			return "", false
+		}
+
+		return origin.Path, true
+	}
+
+	if strings.Contains(link, "://") {
+		return "", false
+	}
+
+	if _, err := os.Stat(link); err != nil {
+		return "", false
+	}
+
+	path, err := filepath.Abs(link)
+	if err != nil {
+		return "", false
+	}
+
+	return path, true
+}
+
+func detectPath(path string) (Format, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	if stat.IsDir() {
+		switch {
+		case exists(filepath.Join(path, "problem.xml")):
+			return FormatPolygon, nil
+		case exists(filepath.Join(root(path, FormatKattis), "problem.yaml")):
+			return FormatKattis, nil
+		default:
+			return "", fmt.Errorf("directory %#v does not contain problem.xml or problem.yaml", path)
+		}
+	}
+
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to open problem archive: %w", err)
+	}
+
+	defer reader.Close()
+
+	for _, file := range reader.File {
+		switch name := strings.Trim(file.Name, "/"); {
+		case name == "problem.xml":
+			return FormatPolygon, nil
+		case name == "problem.yaml" || strings.Count(name, "/") == 1 && strings.HasSuffix(name, "/problem.yaml"):
+			return FormatKattis, nil
+		}
+	}
+
+	return "", fmt.Errorf("archive %#v does not contain problem.xml or problem.yaml", path)
+}
+
+// root finds the problem directory, Kattis problems are often packed into a folder named after the problem
+func root(path string, format Format) string {
+	if format != FormatKattis || exists(filepath.Join(path, "problem.yaml")) {
+		return path
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return path
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			dirs = append(dirs, entry.Name())
+		}
+	}
+
+	if len(dirs) == 1 && exists(filepath.Join(path, dirs[0], "problem.yaml")) {
+		return filepath.Join(path, dirs[0])
+	}
+
+	return path
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,85 @@
+package importer
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := map[string]Format{
+		"polygon://key:secret@/?problemId=123":                               FormatPolygon,
+		"https://polygon.codeforces.com/p0abc/user/problem/problem-1.zip":    FormatPolygon,
+		"https://example.com/problem.zip":                                    FormatKattis,
+		"../polygon/.testdata/01-topics":                                     FormatPolygon,
+		"../kattis/problems/scoring":                                         FormatKattis,
+		"file://" + abs(t, "../polygon/.testdata/01-topics"):                 FormatPolygon,
+		archive(t, map[string]string{"problem.xml": "<problem/>"}):           FormatPolygon,
+		archive(t, map[string]string{"hello/problem.yaml": "name: hello"}):   FormatKattis,
+		"file://" + archive(t, map[string]string{"problem.yaml": "name: x"}): FormatKattis,
+	}
+
+	for link, want := range tests {
+		t.Run(link, func(t *testing.T) {
+			got, err := Detect(link)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != want {
+				t.Errorf("Detect(%#v) = %v, want %v", link, got, want)
+			}
+		})
+	}
+}
+
+func TestDetect_unknown(t *testing.T) {
+	for _, link := range []string{
+		"ftp://example.com/problem.zip",
+		t.TempDir(),
+		archive(t, map[string]string{"readme.md": "hello"}),
+	} {
+		if _, err := Detect(link); err == nil {
+			t.Errorf("Detect(%#v) must fail", link)
+		}
+	}
+}
+
+func abs(t *testing.T, path string) string {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func archive(t *testing.T, files map[string]string) string {
+	path := filepath.Join(t.TempDir(), "problem.zip")
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	w := zip.NewWriter(file)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
@@ -15,12 +15,15 @@ import (
 	"regexp"
 
 	// "slices"
-	// "sort"
+	"sort"
 	// "strconv"
 	"strings"
 	"time"
 
 	"github.com/eolymp/go-problems/connector"
+	"github.com/eolymp/go-problems/locales"
+	"github.com/eolymp/go-problems/runtimes"
+	"github.com/eolymp/go-problems/topics"
 	assetpb "github.com/eolymp/go-sdk/eolymp/asset"
 	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
 	ecmpb "github.com/eolymp/go-sdk/eolymp/ecm"
@@ -32,16 +35,61 @@ import (
 
 var imageFinder = regexp.MustCompile("(\\\\includegraphics.*?{)(.+?)(})")
 
+// testsetNamespace is used to derive stable testset IDs from problem and group names, so importing the same package
+// twice produces identical snapshots.
+var testsetNamespace = uuid.MustParse("9b7e2d14-3c8a-4f61-a0d5-6e2b9c4f8a37")
+
 type ProblemLoader struct {
-	upload *connector.MultipartUploader
-	log    connector.Logger
+	upload   *connector.MultipartUploader
+	log      connector.Logger
+	registry *runtimes.Registry
+	topics   *topics.Mapper
+}
+
+// UseRuntimes sets registry used to map source files to Eolymp runtimes, runtimes.Default() is used by default.
+func UseRuntimes(registry *runtimes.Registry) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.registry = registry
+	}
+}
+
+// UseTopics sets mapping of Kattis keywords to Eolymp topics, topics.Default() is used by default.
+func UseTopics(mapper *topics.Mapper) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.topics = mapper
+	}
+}
+
+func NewProblemLoader(upload connector.Uploader, log connector.Logger, opts ...func(*ProblemLoader)) *ProblemLoader {
+	p := &ProblemLoader{
+		log:      log,
+		upload:   connector.NewMultipartUploader(upload, log),
+		registry: runtimes.Default(),
+		topics:   topics.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// language finds Eolymp language by source file extension
+func (p *ProblemLoader) language(ext string) (string, bool) {
+	lang, ok := p.registry.LanguageByExtension(ext)
+	return lang.Name, ok
 }
 
-func NewProblemLoader(upload connector.Uploader, log connector.Logger) *ProblemLoader {
-	return &ProblemLoader{
-		log:    log,
-		upload: connector.NewMultipartUploader(upload, log),
+// runtime returns Eolymp runtime used for sources in the language, Kattis does not tell language version, so it's
+// either the runtime pinned for the language or the default one
+func (p *ProblemLoader) runtime(lang string) (string, bool) {
+	resolution, ok := p.registry.Pick(runtimes.Source{Language: lang})
+	if !ok {
+		return "", false
 	}
+
+	return resolution.Runtime.ID, true
 }
 
 // helper to get past the root folder created after downloader the zip
@@ -71,7 +119,19 @@ func resolveRoot(path string) (string, error) {
 	return "", fmt.Errorf("problem.yaml not found")
 }
 
+// Fetch downloads, parses and normalizes problem for it to be imported into the Eolymp database.
 func (p *ProblemLoader) Fetch(ctx context.Context, link string) (*atlaspb.Snapshot, error) {
+	result, err := p.Import(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Snapshot, nil
+}
+
+// Import works like Fetch, but also returns provenance of the snapshot: problem UUID, version, source, license and
+// hash of the problem archive.
+func (p *ProblemLoader) Import(ctx context.Context, link string) (*connector.Result, error) {
 	// create workspace
 	path := filepath.Join(os.TempDir(), uuid.New().String())
 	if err := os.Mkdir(path, 0777); err != nil {
@@ -81,6 +141,7 @@ func (p *ProblemLoader) Fetch(ctx context.Context, link string) (*atlaspb.Snapsh
 	defer p.cleanup(path)
 
 	start := time.Now()
+	provenance := connector.NewProvenance("kattis", link)
 
 	p.log.Printf("Downloading problem archive")
 
@@ -91,6 +152,13 @@ func (p *ProblemLoader) Fetch(ctx context.Context, link string) (*atlaspb.Snapsh
 
 	p.log.Printf("Downloaded in %v", time.Since(start))
 
+	hash, err := connector.HashFile(filepath.Join(path, "problem.zip"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to hash problem archive: %w", err)
+	}
+
+	provenance.ArchiveSHA256 = hash
+
 	start = time.Now()
 
 	if err := p.unpack(ctx, path); err != nil {
@@ -99,16 +167,38 @@ func (p *ProblemLoader) Fetch(ctx context.Context, link string) (*atlaspb.Snapsh
 
 	p.log.Printf("Unpacked in %v!", time.Since(start))
 
-	path, err := resolveRoot(path)
+	root, err := resolveRoot(path)
 	if err != nil {
 		return nil, err
 	}
 
-	return p.Snapshot(ctx, path)
+	// problems are usually packed into a folder named after the problem
+	if root != path {
+		provenance.Name = filepath.Base(root)
+	}
+
+	return p.read(ctx, root, provenance)
 }
 
 // Snapshot reads problem specification from the unpacked problem archive and returns a Snapshot of the problem.
 func (p *ProblemLoader) Snapshot(ctx context.Context, path string) (*atlaspb.Snapshot, error) {
+	result, err := p.ImportDir(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Snapshot, nil
+}
+
+// ImportDir works like Snapshot, but also returns provenance of the snapshot.
+func (p *ProblemLoader) ImportDir(ctx context.Context, path string) (*connector.Result, error) {
+	provenance := connector.NewProvenance("kattis", (&url.URL{Scheme: "file", Path: path}).String())
+	provenance.Name = filepath.Base(path)
+
+	return p.read(ctx, path, provenance)
+}
+
+func (p *ProblemLoader) read(ctx context.Context, path string, provenance *connector.Provenance) (*connector.Result, error) {
 	file, err := os.Open(filepath.Join(path, "problem.yaml"))
 	if err != nil {
 		return nil, fmt.Errorf("unable to open problem.yaml: %w", err)
@@ -169,8 +259,26 @@ func (p *ProblemLoader) Snapshot(ctx context.Context, path string) (*atlaspb.Sna
 		return nil, fmt.Errorf("unable to read solutions: %w", err)
 	}
 
-	return &atlaspb.Snapshot{
-		Problem:     &atlaspb.Problem{Topics: TopicsFromTags(spec.Keywords), Type: atlaspb.Problem_PROGRAM},
+	links, unmapped, fuzzy := p.topics.Map(spec.Keywords)
+
+	var warnings []connector.Warning
+	for _, tag := range unmapped {
+		p.log.Printf("Keyword %#v does not match any topic", tag)
+		warnings = append(warnings, connector.Warning{Kind: "unmapped-tag", Message: fmt.Sprintf("keyword %#v does not match any topic", tag)})
+	}
+
+	for _, match := range fuzzy {
+		p.log.Printf("Keyword %#v is matched to similar tag %#v", match.Tag, match.Match)
+		warnings = append(warnings, connector.Warning{Kind: "fuzzy-tag", Message: fmt.Sprintf("keyword %#v is matched to similar tag %#v", match.Tag, match.Match)})
+	}
+
+	provenance.ProblemID = spec.UUID
+	provenance.Revision = spec.Version
+	provenance.Source = spec.Source.Name()
+	provenance.License = spec.License
+
+	snapshot := &atlaspb.Snapshot{
+		Problem:     &atlaspb.Problem{Topics: links, Type: atlaspb.Problem_PROGRAM},
 		Testing:     &atlaspb.TestingConfig{},
 		Checker:     checker,
 		Validator:   validator,
@@ -183,7 +291,9 @@ func (p *ProblemLoader) Snapshot(ctx context.Context, path string) (*atlaspb.Sna
 		Editorials:  editorials,
 		Solutions:   solutions,
 		Scripts:     scripts,
-	}, nil
+	}
+
+	return &connector.Result{Snapshot: snapshot, Provenance: provenance, Warnings: warnings}, nil
 }
 
 func (p *ProblemLoader) download(ctx context.Context, path string, link string) error {
@@ -191,9 +301,34 @@ func (p *ProblemLoader) download(ctx context.Context, path string, link string)
 	if err != nil {
 		return fmt.Errorf("invalid problem origin: %w", err)
 	}
+	if origin.Scheme == "file" {
+		return p.download_by_path(path, origin.Path)
+	}
+
 	return p.download_by_link(ctx, path, origin)
 }
 
+// copies local archive and stores it as <path>/problem.zip.
+func (p *ProblemLoader) download_by_path(path string, archive string) error {
+	src, err := os.Open(archive)
+	if err != nil {
+		return fmt.Errorf("open local archive: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := os.Create(filepath.Join(path, "problem.zip"))
+	if err != nil {
+		return fmt.Errorf("create local archive: %w", err)
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		return fmt.Errorf("write local archive: %w", err)
+	}
+
+	return nil
+}
+
 // fetches ANY public .zip URL and stores it as <path>/problem.zip.
 func (p *ProblemLoader) download_by_link(ctx context.Context, path string, link *url.URL) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link.String(), nil)
@@ -338,12 +473,6 @@ func (p *ProblemLoader) checker(ctx context.Context, path string) (*atlaspb.Chec
 		}
 	}
 
-	// ext to lang
-	extToLang := map[string]string{}
-	for lang, ext := range LanguageExtensions {
-		extToLang[ext] = lang
-	}
-
 	for _, ent := range entries {
 		if ent.IsDir() {
 			continue
@@ -352,13 +481,13 @@ func (p *ProblemLoader) checker(ctx context.Context, path string) (*atlaspb.Chec
 		name := ent.Name()
 		ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".")
 
-		lang, ok := extToLang[ext]
+		lang, ok := p.language(ext)
 		if !ok {
 			p.log.Printf("Unknown validator extension .%s – skipping %q", ext, name)
 			continue
 		}
 
-		runtime, ok := RuntimeMapping[lang]
+		runtime, ok := p.runtime(lang)
 		if !ok {
 			p.log.Printf("No runtime mapping for language %s – skipping %q", lang, name)
 			continue
@@ -420,11 +549,6 @@ func (p *ProblemLoader) validator(ctx context.Context, path string) (*atlaspb.Va
 		}
 	}
 
-	extToLang := map[string]string{}
-	for lang, ext := range LanguageExtensions {
-		extToLang[ext] = lang
-	}
-
 	for _, ent := range entries {
 		if ent.IsDir() {
 			continue
@@ -432,12 +556,12 @@ func (p *ProblemLoader) validator(ctx context.Context, path string) (*atlaspb.Va
 
 		name := ent.Name()
 		ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".")
-		lang, ok := extToLang[ext]
+		lang, ok := p.language(ext)
 		if !ok {
 			continue
 		}
 
-		runtime, ok := RuntimeMapping[lang]
+		runtime, ok := p.runtime(lang)
 		if !ok {
 			p.log.Printf("Skipping validator %q: no runtime for %s", name, lang)
 			continue
@@ -502,10 +626,14 @@ func (p *ProblemLoader) statements(ctx context.Context, path string, spec *Speci
 		if v, ok := spec.Name.Map["en"]; ok {
 			title = v
 		} else {
-			for _, v := range spec.Name.Map {
-				title = v
-				break
+			// pick the first locale alphabetically, so the title does not depend on map order
+			var langs []string
+			for locale := range spec.Name.Map {
+				langs = append(langs, locale)
 			}
+
+			sort.Strings(langs)
+			title = spec.Name.Map[langs[0]]
 		}
 	default:
 		title = "Problem"
@@ -542,12 +670,6 @@ func (p *ProblemLoader) statements(ctx context.Context, path string, spec *Speci
 		name := e.Name()
 		ext := strings.ToLower(filepath.Ext(name))
 
-		// lang detection
-		locale := "en"
-		if dot := strings.Index(name, "."); dot == 2 {
-			locale = strings.ToLower(name[:2])
-		}
-
 		// read file
 		path := filepath.Join(dir, name)
 		raw, rerr := os.ReadFile(path)
@@ -567,6 +689,23 @@ func (p *ProblemLoader) statements(ctx context.Context, path string, spec *Speci
 			continue
 		}
 
+		// problem.<lang>.<ext> or <lang>.<ext>, problem.<ext> is in English
+		locale := "en"
+		stem := strings.TrimSuffix(name, filepath.Ext(name))
+		if _, lang, ok := strings.Cut(stem, "."); ok || len(stem) == 2 {
+			if !ok {
+				lang = stem
+			}
+
+			parsed, err := locales.Parse(lang)
+			if err != nil {
+				p.log.Printf("Skipping statement %q: %v", name, err)
+				continue
+			}
+
+			locale = parsed
+		}
+
 		stmts = append(stmts, &atlaspb.Statement{
 			Locale:  locale,
 			Title:   title,
@@ -674,7 +813,7 @@ func (p *ProblemLoader) testing(ctx context.Context, path string, spec *Specific
 		}
 
 		// build testset and apply yaml overrides
-		set := newSet(nextSetIdx, groupName, isExample, spec.Limits)
+		set := newSet(nextSetIdx, problemKey(spec, path)+"/"+groupName, isExample, spec.Limits)
 		nextSetIdx++
 
 		if cfg.FullFeedback {
@@ -781,6 +920,36 @@ func dirExists(p string) bool {
 	return false
 }
 
+// problemKey identifies the problem for deriving stable testset IDs, it uses UUID from problem.yaml when present,
+// otherwise the name (english one or the first one by locale for per-language names) and the directory name.
+func problemKey(spec *Specification, path string) string {
+	if spec.UUID != "" {
+		return spec.UUID
+	}
+
+	if spec.Name.One != "" {
+		return spec.Name.One
+	}
+
+	if name := spec.Name.Map["en"]; name != "" {
+		return name
+	}
+
+	langs := make([]string, 0, len(spec.Name.Map))
+	for locale, name := range spec.Name.Map {
+		if name != "" {
+			langs = append(langs, locale)
+		}
+	}
+
+	if len(langs) > 0 {
+		sort.Strings(langs)
+		return spec.Name.Map[langs[0]]
+	}
+
+	return filepath.Base(path)
+}
+
 // helper to make a new set
 func newSet(idx int, name string, sample bool, lim Limits) *atlaspb.Testset {
 	ms := uint32(lim.TimeLimit * 1000)
@@ -797,7 +966,7 @@ func newSet(idx int, name string, sample bool, lim Limits) *atlaspb.Testset {
 	}
 
 	ts := &atlaspb.Testset{
-		Id:             uuid.New().String(),
+		Id:             uuid.NewSHA1(testsetNamespace, []byte(name)).String(),
 		Index:          uint32(idx),
 		CpuLimit:       ms,
 		MemoryLimit:    mem,
@@ -840,7 +1009,7 @@ func (p *ProblemLoader) editorials(ctx context.Context, path string) (editorials
 		if parts := strings.SplitN(name, ".", 3); len(parts) == 3 {
 			lang := parts[1]
 			var convErr error
-			locale, convErr = LocaleFromLanguage(lang)
+			locale, convErr = locales.Parse(lang)
 			if convErr != nil {
 				p.log.Printf("Skipping solution %q: %v", name, convErr)
 				continue
@@ -872,11 +1041,6 @@ func (p *ProblemLoader) scripts(ctx context.Context, path string) (scripts []*at
 		return nil, nil
 	}
 
-	extToLang := map[string]string{}
-	for lang, ext := range LanguageExtensions {
-		extToLang[ext] = lang
-	}
-
 	walkErr := filepath.WalkDir(genDir, func(fp string, d os.DirEntry, walkErr error) error {
 		if walkErr != nil {
 			return walkErr
@@ -891,13 +1055,13 @@ func (p *ProblemLoader) scripts(ctx context.Context, path string) (scripts []*at
 		}
 
 		ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(d.Name())), ".")
-		lang, ok := extToLang[ext]
+		lang, ok := p.language(ext)
 		if !ok {
 			p.log.Printf("Skipping generator %q – unknown extension .%s", d.Name(), ext)
 			return nil
 		}
 
-		runtime, ok := RuntimeMapping[lang]
+		runtime, ok := p.runtime(lang)
 		if !ok {
 			p.log.Printf("Skipping generator %q – no runtime for lang %s", d.Name(), lang)
 			return nil
@@ -952,11 +1116,6 @@ func (p *ProblemLoader) solutions(ctx context.Context, path string) (solutions [
 		return nil, nil
 	}
 
-	extToLang := map[string]string{}
-	for lang, ext := range LanguageExtensions {
-		extToLang[ext] = lang
-	}
-
 	verdictMap := map[string]atlaspb.Solution_Type{
 		"accepted":                        atlaspb.Solution_CORRECT,
 		"reference":                       atlaspb.Solution_CORRECT,
@@ -984,13 +1143,13 @@ func (p *ProblemLoader) solutions(ctx context.Context, path string) (solutions [
 		}
 
 		ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(fp)), ".")
-		lang, ok := extToLang[ext]
+		lang, ok := p.language(ext)
 		if !ok {
 			p.log.Printf("Skipping submission %q: unmapped extension .%s", fp, ext)
 			return nil
 		}
 
-		runtime, ok := RuntimeMapping[lang]
+		runtime, ok := p.runtime(lang)
 		if !ok {
 			p.log.Printf("Skipping submission %q: no runtime for language %s", fp, lang)
 			return nil
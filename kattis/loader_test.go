@@ -1,21 +1,26 @@
 package kattis
 
 import (
+	"bytes"
 	"context"
 	// "fmt"
 	// "fmt"
 	// "net/url"
 	// "os"
 	// "sort"
+	"os"
 	"path/filepath"
+	"sort"
 	"testing"
 
 	. "github.com/eolymp/go-problems/connector/testing"
+	"github.com/eolymp/go-problems/runtimes"
 	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
 	// ecmpb "github.com/eolymp/go-sdk/eolymp/ecm"
 	// executorpb "github.com/eolymp/go-sdk/eolymp/executor"
 	// "github.com/google/go-cmp/cmp"
-	// "google.golang.org/protobuf/proto"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
 )
 
 // used to test the problem packages in the kattis directory
@@ -209,4 +214,146 @@ func TestProblemLoader_Snapshot_darkride(t *testing.T) {
 			t.Fatalf("wrong name, the name is: %s",snap.GetStatements()[0].GetTitle())
 		}
 	})
-}
\ No newline at end of file
+}
+
+func TestProblemLoader_Snapshot_deterministic(t *testing.T) {
+	ctx := context.Background()
+	URL := filepath.Join("problems", "scoring")
+
+	ldr := NewProblemLoader(MockUploader(), MockLogger(t))
+
+	first, err := ldr.Snapshot(ctx, URL)
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	again, err := ldr.Snapshot(ctx, URL)
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	want, _ := proto.MarshalOptions{Deterministic: true}.Marshal(first)
+	got, _ := proto.MarshalOptions{Deterministic: true}.Marshal(again)
+
+	if !bytes.Equal(want, got) {
+		t.Fatalf("snapshots do not match")
+	}
+}
+
+func TestProblemLoader_Snapshot_localizedNames(t *testing.T) {
+	ctx := context.Background()
+	ldr := NewProblemLoader(MockUploader(), MockLogger(t))
+
+	ids := map[string]string{}
+	for _, name := range []string{"Alpha", "Beta"} {
+		root := t.TempDir()
+		files := map[string]string{
+			"problem.yaml":             "problem_format_version: 2023-07-draft\nname:\n  en: " + name + "\n  sv: " + name + " (sv)\n",
+			"statement/problem.en.tex": "\\problemname{" + name + "}\n",
+			"data/secret/1.in":         "1\n",
+			"data/secret/1.ans":        "1\n",
+		}
+
+		for file, content := range files {
+			if err := os.MkdirAll(filepath.Dir(filepath.Join(root, file)), 0o755); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := os.WriteFile(filepath.Join(root, file), []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		snap, err := ldr.Snapshot(ctx, root)
+		if err != nil {
+			t.Fatalf("Snapshot(%v): %v", name, err)
+		}
+
+		for _, testset := range snap.GetTestsets() {
+			if other, ok := ids[testset.GetId()]; ok {
+				t.Errorf("testset %v of problem %v has the same ID as testset of problem %v", testset.GetId(), name, other)
+			}
+
+			ids[testset.GetId()] = name
+		}
+	}
+
+	if len(ids) == 0 {
+		t.Fatal("snapshots must have testsets")
+	}
+}
+
+func TestProblemLoader_ImportDir(t *testing.T) {
+	ctx := context.Background()
+	ldr := NewProblemLoader(MockUploader(), MockLogger(t))
+
+	result, err := ldr.ImportDir(ctx, filepath.Join("problems", "scoring"))
+	if err != nil {
+		t.Fatalf("ImportDir: %v", err)
+	}
+
+	got := result.Provenance
+
+	if got.Format != "kattis" || got.Name != "scoring" {
+		t.Errorf("unexpected format or name: %+v", got)
+	}
+
+	if got.ProblemID != "db3e1e32-dd6f-4158-8f9f-909a383fe8d5" {
+		t.Errorf("problem ID must be taken from problem.yaml, got %#v", got.ProblemID)
+	}
+
+	if got.Source != "My Contest 2024" || got.License != "cc by-sa" {
+		t.Errorf("source and license must be taken from problem.yaml, got %#v and %#v", got.Source, got.License)
+	}
+
+	if got.ArchiveSHA256 != "" {
+		t.Errorf("directories do not have archive hash, got %#v", got.ArchiveSHA256)
+	}
+}
+
+func TestProblemLoader_Snapshot_runtimes(t *testing.T) {
+	ctx := context.Background()
+
+	registry, err := runtimes.Default().Override([]byte(`{languages: [{name: python, extensions: [py], runtime: "python:3.11-python"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ldr := NewProblemLoader(MockUploader(), MockLogger(t), UseRuntimes(registry))
+
+	snap, err := ldr.Snapshot(ctx, filepath.Join("problems", "scoring"))
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	if len(snap.GetSolutions()) == 0 {
+		t.Fatal("snapshot must have solutions")
+	}
+
+	for _, solution := range snap.GetSolutions() {
+		if solution.GetRuntime() != "python:3.11-python" {
+			t.Errorf("solution %v must use runtime from the registry, got %v", solution.GetName(), solution.GetRuntime())
+		}
+	}
+}
+
+func TestProblemLoader_Snapshot_locales(t *testing.T) {
+	ctx := context.Background()
+	ldr := NewProblemLoader(MockUploader(), MockLogger(t))
+
+	snap, err := ldr.Snapshot(ctx, filepath.Join("problems", "maximal"))
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	var got []string
+	for _, statement := range snap.GetStatements() {
+		got = append(got, statement.GetLocale())
+	}
+
+	sort.Strings(got)
+
+	if want := []string{"en", "sv"}; !cmp.Equal(want, got) {
+		t.Errorf("statement locales do not match:\n%s", cmp.Diff(want, got))
+	}
+}
@@ -2,6 +2,7 @@ package kattis
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -61,6 +62,19 @@ func (m *StringSeqMap) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return fmt.Errorf("must be string, sequence, or map")
 }
 
+// Name returns a single string describing the value: the string itself, the "name" key of the map or the elements
+// joined with a comma.
+func (m StringSeqMap) Name() string {
+	switch {
+	case m.String != "":
+		return m.String
+	case m.Map["name"] != "":
+		return m.Map["name"]
+	default:
+		return strings.Join(m.Seq, ", ")
+	}
+}
+
 type StringOrMap struct {
 	One string
 	Map map[string]string
@@ -0,0 +1,132 @@
+// Package lint checks problem snapshots for structural problems before they are pushed to eolymp.
+//
+// Linter works on atlaspb.Snapshot and does not depend on the format the problem was imported from, so it can be used
+// with output of any loader.
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+)
+
+// Severity of the finding.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "info":
+		*s = SeverityInfo
+	case "warning":
+		*s = SeverityWarning
+	case "error":
+		*s = SeverityError
+	default:
+		return fmt.Errorf("unknown severity %#v", string(text))
+	}
+
+	return nil
+}
+
+// Finding describes a single problem found in the snapshot.
+type Finding struct {
+	Rule     string   `json:"rule"`     // name of the rule which produced the finding
+	Severity Severity `json:"severity"` // how bad it is
+	Path     string   `json:"path"`     // location within the snapshot, e.g. tests[3].input
+	Message  string   `json:"message"`  // human-readable description
+}
+
+func (f Finding) String() string {
+	if f.Path == "" {
+		return fmt.Sprintf("%v: %v (%v)", f.Severity, f.Message, f.Rule)
+	}
+
+	return fmt.Sprintf("%v: %v: %v (%v)", f.Severity, f.Path, f.Message, f.Rule)
+}
+
+// Rule inspects snapshot and returns findings.
+type Rule struct {
+	Name  string
+	Check func(snap *atlaspb.Snapshot) []Finding
+}
+
+// DefaultRules is the list of rules used by Lint when no rules are given explicitly.
+var DefaultRules = []Rule{
+	{Name: "score-total", Check: checkScoreTotal},
+	{Name: "testset-dependencies", Check: checkTestsetDependencies},
+	{Name: "test-testset", Check: checkTestTestset},
+	{Name: "generator-script", Check: checkGeneratorScript},
+	{Name: "solution-script", Check: checkSolutionScript},
+	{Name: "sample-testset", Check: checkSampleTestset},
+	{Name: "statement-title", Check: checkStatementTitle},
+	{Name: "duplicate-locale", Check: checkDuplicateLocale},
+	{Name: "asset-url", Check: checkAssetURL},
+}
+
+// Lint runs rules against the snapshot and returns all findings in the order of rules. If no rules are given,
+// DefaultRules are used.
+func Lint(snap *atlaspb.Snapshot, rules ...Rule) []Finding {
+	if len(rules) == 0 {
+		rules = DefaultRules
+	}
+
+	var findings []Finding
+	for _, rule := range rules {
+		for _, finding := range rule.Check(snap) {
+			if finding.Rule == "" {
+				finding.Rule = rule.Name
+			}
+
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
+
+// HasErrors returns true if any of the findings has error severity.
+func HasErrors(findings []Finding) bool {
+	for _, finding := range findings {
+		if finding.Severity >= SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Max returns the highest severity among findings, or SeverityInfo if there are none.
+func Max(findings []Finding) Severity {
+	severity := SeverityInfo
+	for _, finding := range findings {
+		if finding.Severity > severity {
+			severity = finding.Severity
+		}
+	}
+
+	return severity
+}
@@ -0,0 +1,122 @@
+package lint
+
+import (
+	"testing"
+
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	executorpb "github.com/eolymp/go-sdk/eolymp/executor"
+	"github.com/google/go-cmp/cmp"
+)
+
+func validSnapshot() *atlaspb.Snapshot {
+	return &atlaspb.Snapshot{
+		Statements: []*atlaspb.Statement{{Locale: "en", Title: "A + B"}, {Locale: "uk", Title: "A + B"}},
+		Scripts:    []*atlaspb.Script{{Name: "gen"}, {Name: "solution"}},
+		Testsets: []*atlaspb.Testset{
+			{Id: "s0", Index: 0},
+			{Id: "s1", Index: 1, Dependencies: []uint32{0}},
+		},
+		Tests: []*atlaspb.Test{
+			{TestsetId: "s0", Index: 1, Example: true, Input: &atlaspb.Test_InputUrl{InputUrl: "https://example.com/1"}, Answer: &atlaspb.Test_AnswerUrl{AnswerUrl: "https://example.com/1.a"}},
+			{TestsetId: "s1", Index: 2, Score: 100, Input: &atlaspb.Test_InputGenerator{InputGenerator: &atlaspb.Test_Generator{ScriptName: "gen"}}, Answer: &atlaspb.Test_AnswerGenerator{AnswerGenerator: &atlaspb.Test_Generator{ScriptName: "solution"}}},
+		},
+	}
+}
+
+func TestLint(t *testing.T) {
+	tt := []struct {
+		name   string
+		modify func(snap *atlaspb.Snapshot)
+		want   []Finding
+	}{
+		{
+			name:   "valid snapshot",
+			modify: func(snap *atlaspb.Snapshot) {},
+		},
+		{
+			name:   "scores do not add up",
+			modify: func(snap *atlaspb.Snapshot) { snap.Tests[1].Score = 90 },
+			want:   []Finding{{Rule: "score-total", Severity: SeverityError, Path: "tests", Message: "test scores add up to 90 instead of 100"}},
+		},
+		{
+			name:   "missing dependency",
+			modify: func(snap *atlaspb.Snapshot) { snap.Testsets[1].Dependencies = []uint32{5} },
+			want:   []Finding{{Rule: "testset-dependencies", Severity: SeverityError, Path: "testsets[1].dependencies[0]", Message: "testset 1 depends on testset 5 which does not exist"}},
+		},
+		{
+			name:   "cyclic dependency",
+			modify: func(snap *atlaspb.Snapshot) { snap.Testsets[0].Dependencies = []uint32{1} },
+			want: []Finding{
+				{Rule: "testset-dependencies", Severity: SeverityError, Path: "testsets[0].dependencies", Message: "testset 0 is part of a dependency cycle"},
+				{Rule: "testset-dependencies", Severity: SeverityError, Path: "testsets[1].dependencies", Message: "testset 1 is part of a dependency cycle"},
+			},
+		},
+		{
+			name: "dependency on cycle",
+			modify: func(snap *atlaspb.Snapshot) {
+				snap.Testsets[0].Dependencies = []uint32{1}
+				snap.Testsets[1].Dependencies = []uint32{1}
+				snap.Testsets = append(snap.Testsets, &atlaspb.Testset{Id: "s2", Index: 2, Dependencies: []uint32{1}})
+			},
+			want: []Finding{{Rule: "testset-dependencies", Severity: SeverityError, Path: "testsets[1].dependencies", Message: "testset 1 is part of a dependency cycle"}},
+		},
+		{
+			name:   "unknown generator",
+			modify: func(snap *atlaspb.Snapshot) { snap.Scripts = snap.Scripts[1:] },
+			want:   []Finding{{Rule: "generator-script", Severity: SeverityError, Path: "tests[1].input_generator", Message: "test 2 is generated by script \"gen\" which does not exist"}},
+		},
+		{
+			name:   "no solution script",
+			modify: func(snap *atlaspb.Snapshot) { snap.Scripts = snap.Scripts[:1] },
+			want:   []Finding{{Rule: "solution-script", Severity: SeverityError, Path: "scripts", Message: "1 test answer(s) must be generated by \"solution\" script, but there is no such script"}},
+		},
+		{
+			name: "no sample testset",
+			modify: func(snap *atlaspb.Snapshot) {
+				snap.Testsets = snap.Testsets[1:]
+				snap.Testsets[0].Dependencies = nil
+				snap.Tests = snap.Tests[1:]
+			},
+			want: []Finding{{Rule: "sample-testset", Severity: SeverityWarning, Path: "testsets", Message: "there is no sample testset with index 0 and no examples"}},
+		},
+		{
+			name:   "statement without title",
+			modify: func(snap *atlaspb.Snapshot) { snap.Statements[1].Title = "" },
+			want:   []Finding{{Rule: "statement-title", Severity: SeverityError, Path: "statements[1].title", Message: "statement in locale \"uk\" has no title"}},
+		},
+		{
+			name:   "duplicate locale",
+			modify: func(snap *atlaspb.Snapshot) { snap.Statements[1].Locale = "en" },
+			want:   []Finding{{Rule: "duplicate-locale", Severity: SeverityError, Path: "statements[1].locale", Message: "there is more than one statement in locale \"en\""}},
+		},
+		{
+			name: "empty asset links",
+			modify: func(snap *atlaspb.Snapshot) {
+				snap.Tests[0].Input = &atlaspb.Test_InputUrl{}
+				snap.Attachments = []*atlaspb.Attachment{{Name: "grader.cpp"}}
+				snap.Checker = &atlaspb.Checker{Files: []*executorpb.File{{Path: "testlib.h"}}}
+			},
+			want: []Finding{
+				{Rule: "asset-url", Severity: SeverityError, Path: "tests[0].input_url", Message: "test 1 has empty input link"},
+				{Rule: "asset-url", Severity: SeverityError, Path: "attachments[0].link", Message: "attachment \"grader.cpp\" has no link"},
+				{Rule: "asset-url", Severity: SeverityError, Path: "checker.files[0]", Message: "file \"testlib.h\" has no link"},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			snap := validSnapshot()
+			tc.modify(snap)
+
+			got := Lint(snap)
+			if !cmp.Equal(tc.want, got) {
+				t.Errorf("Findings do not match:\n%s", cmp.Diff(tc.want, got))
+			}
+
+			if want, got := len(tc.want) > 0 && tc.want[0].Severity == SeverityError, HasErrors(got); want != got {
+				t.Errorf("HasErrors: want %v, got %v", want, got)
+			}
+		})
+	}
+}
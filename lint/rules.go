@@ -0,0 +1,324 @@
+package lint
+
+import (
+	"fmt"
+	"math"
+
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	executorpb "github.com/eolymp/go-sdk/eolymp/executor"
+)
+
+// scoreTolerance allows small rounding errors when adding up float scores
+const scoreTolerance = 0.001
+
+// checkScoreTotal makes sure scores of active tests add up to 100.
+func checkScoreTotal(snap *atlaspb.Snapshot) []Finding {
+	if len(snap.GetTests()) == 0 {
+		return []Finding{{Severity: SeverityWarning, Path: "tests", Message: "problem has no tests"}}
+	}
+
+	var total float64
+	for _, test := range snap.GetTests() {
+		if test.GetInactive() {
+			continue
+		}
+
+		total += float64(test.GetScore())
+	}
+
+	if math.Abs(total-100) > scoreTolerance {
+		return []Finding{{Severity: SeverityError, Path: "tests", Message: fmt.Sprintf("test scores add up to %v instead of 100", total)}}
+	}
+
+	return nil
+}
+
+// checkTestsetDependencies makes sure testset dependencies point to existing testsets and do not form cycles.
+func checkTestsetDependencies(snap *atlaspb.Snapshot) (findings []Finding) {
+	deps := map[uint32][]uint32{}
+	for i, testset := range snap.GetTestsets() {
+		if _, ok := deps[testset.GetIndex()]; ok {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Path:     fmt.Sprintf("testsets[%d].index", i),
+				Message:  fmt.Sprintf("testset index %d is used more than once", testset.GetIndex()),
+			})
+		}
+
+		deps[testset.GetIndex()] = testset.GetDependencies()
+	}
+
+	for i, testset := range snap.GetTestsets() {
+		for j, dep := range testset.GetDependencies() {
+			if _, ok := deps[dep]; !ok {
+				findings = append(findings, Finding{
+					Severity: SeverityError,
+					Path:     fmt.Sprintf("testsets[%d].dependencies[%d]", i, j),
+					Message:  fmt.Sprintf("testset %d depends on testset %d which does not exist", testset.GetIndex(), dep),
+				})
+			}
+		}
+	}
+
+	// detect cycles using depth-first search with three colors
+	const (
+		white = iota
+		grey
+		black
+	)
+
+	color := map[uint32]int{}
+	cyclic := map[uint32]bool{}
+
+	var stack []uint32
+	var visit func(index uint32)
+	visit = func(index uint32) {
+		color[index] = grey
+		stack = append(stack, index)
+
+		for _, dep := range deps[index] {
+			if _, ok := deps[dep]; !ok {
+				continue
+			}
+
+			switch color[dep] {
+			case grey:
+				// every testset on the stack from dep up to the current one is part of the cycle
+				for k := len(stack) - 1; k >= 0; k-- {
+					cyclic[stack[k]] = true
+					if stack[k] == dep {
+						break
+					}
+				}
+			case white:
+				visit(dep)
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		color[index] = black
+	}
+
+	for _, testset := range snap.GetTestsets() {
+		if color[testset.GetIndex()] == white {
+			visit(testset.GetIndex())
+		}
+	}
+
+	for i, testset := range snap.GetTestsets() {
+		index := testset.GetIndex()
+		if !cyclic[index] {
+			continue
+		}
+
+		delete(cyclic, index)
+		findings = append(findings, Finding{
+			Severity: SeverityError,
+			Path:     fmt.Sprintf("testsets[%d].dependencies", i),
+			Message:  fmt.Sprintf("testset %d is part of a dependency cycle", index),
+		})
+	}
+
+	return
+}
+
+// checkTestTestset makes sure every test belongs to an existing testset.
+func checkTestTestset(snap *atlaspb.Snapshot) (findings []Finding) {
+	ids := map[string]bool{}
+	for _, testset := range snap.GetTestsets() {
+		ids[testset.GetId()] = true
+	}
+
+	for i, test := range snap.GetTests() {
+		if !ids[test.GetTestsetId()] {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Path:     fmt.Sprintf("tests[%d].testset_id", i),
+				Message:  fmt.Sprintf("test %d refers to testset %#v which does not exist", test.GetIndex(), test.GetTestsetId()),
+			})
+		}
+	}
+
+	return
+}
+
+// checkGeneratorScript makes sure generators refer to existing scripts. Answer generators using "solution" script
+// are covered by checkSolutionScript.
+func checkGeneratorScript(snap *atlaspb.Snapshot) (findings []Finding) {
+	scripts := scriptNames(snap)
+
+	for i, test := range snap.GetTests() {
+		if gen := test.GetInputGenerator(); gen != nil && !scripts[gen.GetScriptName()] {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Path:     fmt.Sprintf("tests[%d].input_generator", i),
+				Message:  fmt.Sprintf("test %d is generated by script %#v which does not exist", test.GetIndex(), gen.GetScriptName()),
+			})
+		}
+
+		if gen := test.GetAnswerGenerator(); gen != nil && !isSolution(gen) && !scripts[gen.GetScriptName()] {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Path:     fmt.Sprintf("tests[%d].answer_generator", i),
+				Message:  fmt.Sprintf("answer for test %d is generated by script %#v which does not exist", test.GetIndex(), gen.GetScriptName()),
+			})
+		}
+	}
+
+	return
+}
+
+// checkSolutionScript makes sure there is a "solution" script if any answer has to be generated by it.
+func checkSolutionScript(snap *atlaspb.Snapshot) []Finding {
+	if scriptNames(snap)["solution"] {
+		return nil
+	}
+
+	var count int
+	for _, test := range snap.GetTests() {
+		if gen := test.GetAnswerGenerator(); gen != nil && isSolution(gen) {
+			count++
+		}
+	}
+
+	if count == 0 {
+		return nil
+	}
+
+	return []Finding{{
+		Severity: SeverityError,
+		Path:     "scripts",
+		Message:  fmt.Sprintf("%d test answer(s) must be generated by \"solution\" script, but there is no such script", count),
+	}}
+}
+
+// checkSampleTestset makes sure there is a testset 0 with examples.
+func checkSampleTestset(snap *atlaspb.Snapshot) []Finding {
+	for _, testset := range snap.GetTestsets() {
+		if testset.GetIndex() == 0 {
+			return nil
+		}
+	}
+
+	for _, test := range snap.GetTests() {
+		if test.GetExample() {
+			return []Finding{{Severity: SeverityWarning, Path: "testsets", Message: "there is no sample testset with index 0, examples are placed in other testsets"}}
+		}
+	}
+
+	return []Finding{{Severity: SeverityWarning, Path: "testsets", Message: "there is no sample testset with index 0 and no examples"}}
+}
+
+// checkStatementTitle makes sure every statement has a title.
+func checkStatementTitle(snap *atlaspb.Snapshot) (findings []Finding) {
+	for i, statement := range snap.GetStatements() {
+		if statement.GetTitle() == "" {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Path:     fmt.Sprintf("statements[%d].title", i),
+				Message:  fmt.Sprintf("statement in locale %#v has no title", statement.GetLocale()),
+			})
+		}
+	}
+
+	return
+}
+
+// checkDuplicateLocale makes sure there is at most one statement and one editorial per locale.
+func checkDuplicateLocale(snap *atlaspb.Snapshot) (findings []Finding) {
+	statements := map[string]bool{}
+	for i, statement := range snap.GetStatements() {
+		if statements[statement.GetLocale()] {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Path:     fmt.Sprintf("statements[%d].locale", i),
+				Message:  fmt.Sprintf("there is more than one statement in locale %#v", statement.GetLocale()),
+			})
+		}
+
+		statements[statement.GetLocale()] = true
+	}
+
+	editorials := map[string]bool{}
+	for i, editorial := range snap.GetEditorials() {
+		if editorials[editorial.GetLocale()] {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Path:     fmt.Sprintf("editorials[%d].locale", i),
+				Message:  fmt.Sprintf("there is more than one editorial in locale %#v", editorial.GetLocale()),
+			})
+		}
+
+		editorials[editorial.GetLocale()] = true
+	}
+
+	return
+}
+
+// checkAssetURL makes sure all referenced assets have a link.
+func checkAssetURL(snap *atlaspb.Snapshot) (findings []Finding) {
+	empty := func(path, message string) {
+		findings = append(findings, Finding{Severity: SeverityError, Path: path, Message: message})
+	}
+
+	files := func(path string, files []*executorpb.File) {
+		for i, file := range files {
+			if file.GetSourceUrl() == "" {
+				empty(fmt.Sprintf("%s.files[%d]", path, i), fmt.Sprintf("file %#v has no link", file.GetPath()))
+			}
+		}
+	}
+
+	for i, test := range snap.GetTests() {
+		switch input := test.GetInput().(type) {
+		case nil:
+			empty(fmt.Sprintf("tests[%d].input", i), fmt.Sprintf("test %d has no input", test.GetIndex()))
+		case *atlaspb.Test_InputUrl:
+			if input.InputUrl == "" {
+				empty(fmt.Sprintf("tests[%d].input_url", i), fmt.Sprintf("test %d has empty input link", test.GetIndex()))
+			}
+		}
+
+		switch answer := test.GetAnswer().(type) {
+		case nil:
+			empty(fmt.Sprintf("tests[%d].answer", i), fmt.Sprintf("test %d has no answer", test.GetIndex()))
+		case *atlaspb.Test_AnswerUrl:
+			if answer.AnswerUrl == "" {
+				empty(fmt.Sprintf("tests[%d].answer_url", i), fmt.Sprintf("test %d has empty answer link", test.GetIndex()))
+			}
+		}
+	}
+
+	for i, attachment := range snap.GetAttachments() {
+		if attachment.GetLink() == "" {
+			empty(fmt.Sprintf("attachments[%d].link", i), fmt.Sprintf("attachment %#v has no link", attachment.GetName()))
+		}
+	}
+
+	files("checker", snap.GetChecker().GetFiles())
+	files("validator", snap.GetValidator().GetFiles())
+	files("interactor", snap.GetInteractor().GetFiles())
+
+	for i, script := range snap.GetScripts() {
+		files(fmt.Sprintf("scripts[%d]", i), script.GetFiles())
+	}
+
+	for i, template := range snap.GetTemplates() {
+		files(fmt.Sprintf("templates[%d]", i), template.GetFiles())
+	}
+
+	return
+}
+
+func scriptNames(snap *atlaspb.Snapshot) map[string]bool {
+	names := map[string]bool{}
+	for _, script := range snap.GetScripts() {
+		names[script.GetName()] = true
+	}
+
+	return names
+}
+
+func isSolution(gen *atlaspb.Test_Generator) bool {
+	return gen.GetScriptName() == "" || gen.GetScriptName() == "solution"
+}
@@ -0,0 +1,120 @@
+// Package locales maps language names and codes used by problem formats to Eolymp locales.
+//
+// Eolymp locale is a lower case ISO 639-1 code (e.g. "uk"), languages which do not have one keep their ISO 639-2
+// code. Parse understands English names ("ukrainian"), native names ("українська"), ISO 639-1 and ISO 639-2 codes
+// ("uk", "ukr") and BCP 47 tags ("uk-UA", "pt_BR"), region and script are dropped.
+package locales
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// aliases are names which are used by Polygon or commonly used, but do not match CLDR names
+var aliases = map[string]string{
+	"slovene":              "sl",
+	"farsi":                "fa",
+	"castilian":            "es",
+	"flemish":              "nl",
+	"kirghiz":              "ky",
+	"moldavian":            "ro",
+	"chinese simplified":   "zh",
+	"simplified chinese":   "zh",
+	"traditional chinese":  "zh",
+	"chinese traditional":  "zh",
+	"brazilian portuguese": "pt",
+	"ukranian":             "uk",
+	"belorussian":          "be",
+}
+
+var (
+	once  sync.Once
+	names map[string]string // normalized name to locale
+)
+
+// Parse converts language name or code to Eolymp locale.
+func Parse(lang string) (string, error) {
+	key := normalize(lang)
+	if key == "" {
+		return "", fmt.Errorf("language is empty")
+	}
+
+	if locale, ok := aliases[key]; ok {
+		return locale, nil
+	}
+
+	once.Do(index)
+
+	if locale, ok := names[key]; ok {
+		return locale, nil
+	}
+
+	tag, err := language.Parse(strings.ReplaceAll(key, " ", "-"))
+	if err != nil {
+		return "", fmt.Errorf("unknown language %#v", lang)
+	}
+
+	base, confidence := tag.Base()
+	if confidence == language.No || base.String() == "und" {
+		return "", fmt.Errorf("unknown language %#v", lang)
+	}
+
+	return base.String(), nil
+}
+
+// Name returns English name of the locale, e.g. Ukrainian for uk.
+func Name(locale string) string {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return ""
+	}
+
+	return display.English.Languages().Name(tag)
+}
+
+// index builds lookup of English and native language names for all ISO 639-1 languages
+func index() {
+	names = map[string]string{}
+
+	for a := 'a'; a <= 'z'; a++ {
+		for b := 'a'; b <= 'z'; b++ {
+			code := string([]rune{a, b})
+
+			base, err := language.ParseBase(code)
+			if err != nil {
+				continue
+			}
+
+			tag := language.Make(code)
+			if canonical, _ := tag.Base(); canonical != base { // deprecated codes, e.g. iw for he
+				continue
+			}
+
+			name := display.English.Languages().Name(tag)
+			if name == "" {
+				continue
+			}
+
+			names[normalize(name)] = code
+			names[base.ISO3()] = code
+
+			if native := display.Self.Name(tag); native != "" {
+				if _, ok := names[normalize(native)]; !ok {
+					names[normalize(native)] = code
+				}
+			}
+		}
+	}
+}
+
+// normalize converts name to lower case and replaces separators with spaces
+func normalize(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.NewReplacer("_", " ", "-", " ", "(", " ", ")", " ").Replace(name)
+
+	return strings.Join(strings.Fields(name), " ")
+}
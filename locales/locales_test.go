@@ -0,0 +1,46 @@
+package locales
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := map[string]string{
+		"ukrainian":  "uk",
+		"English":    "en",
+		"georgian":   "ka",
+		"romanian":   "ro",
+		"chinese":    "zh",
+		"slovene":    "sl",
+		"українська": "uk",
+		"Deutsch":    "de",
+		"sv":         "sv",
+		"swe":        "sv",
+		"ger":        "de",
+		"pt-BR":      "pt",
+		"zh_Hant_TW": "zh",
+		"EN-us":      "en",
+	}
+
+	for lang, want := range tests {
+		got, err := Parse(lang)
+		if err != nil {
+			t.Errorf("Parse(%#v) has failed: %v", lang, err)
+			continue
+		}
+
+		if got != want {
+			t.Errorf("Parse(%#v) = %#v, want %#v", lang, got, want)
+		}
+	}
+
+	for _, lang := range []string{"", "klingon", "xx", "problem"} {
+		if got, err := Parse(lang); err == nil {
+			t.Errorf("Parse(%#v) must fail, got %#v", lang, got)
+		}
+	}
+}
+
+func TestName(t *testing.T) {
+	if got := Name("uk"); got != "Ukrainian" {
+		t.Errorf("Name(uk) = %#v, want Ukrainian", got)
+	}
+}
@@ -0,0 +1,423 @@
+package polygon
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/eolymp/go-problems/connector"
+	"golang.org/x/sync/errgroup"
+)
+
+// Mode defines how problems are fetched through Polygon API.
+type Mode string
+
+const (
+	ModePackage Mode = "package" // download a built package, the default
+	ModeAPI     Mode = "api"     // assemble problem from API calls, no package is required
+)
+
+// UseMode sets how problems are fetched for polygon:// links. It can be changed per link using mode query parameter:
+// polygon://key:secret@/?problemId=123&mode=api.
+func UseMode(mode Mode) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.mode = mode
+	}
+}
+
+// solutionTags maps solution tags returned by API to the ones used in problem.xml
+var solutionTags = map[string]string{
+	"MA": "main",
+	"OK": "accepted",
+	"RJ": "rejected",
+	"TL": "time-limit-exceeded",
+	"TO": "time-limit-exceeded-or-accepted",
+	"TM": "time-limit-exceeded-or-memory-limit-exceeded",
+	"WA": "wrong-answer",
+	"PE": "presentation-error",
+	"ML": "memory-limit-exceeded",
+	"RE": "failed",
+}
+
+// importMode returns import mode for the link
+func (p *ProblemLoader) importMode(query url.Values) (Mode, error) {
+	if !query.Has("mode") {
+		return p.mode, nil
+	}
+
+	switch mode := Mode(query.Get("mode")); mode {
+	case ModePackage, ModeAPI:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid problem origin: mode %#v is not supported", mode)
+	}
+}
+
+// assemble fetches problem through API and writes it into the path as if it was an unpacked standard package, so it
+// can be read the same way. Generated tests are not downloaded, they are imported with generator scripts, inputs of
+// manual tests are downloaded one by one. Statement resources (e.g. images) can't be downloaded through API, they
+// are reported as warnings.
+func (p *ProblemLoader) assemble(ctx context.Context, path string, poly *Client, problem int, provenance *connector.Provenance) ([]connector.Warning, error) {
+	var warnings []connector.Warning
+
+	spec := &Specification{}
+
+	problems, err := poly.ListProblems(ctx, ListProblemsInput{ID: problem})
+	if err != nil {
+		return nil, fmt.Errorf("unable to find problem: %w", err)
+	}
+
+	for _, item := range problems {
+		if item.ID == problem {
+			spec.ShortName = item.Name
+			spec.Revision = item.Revision
+		}
+	}
+
+	provenance.ProblemID = fmt.Sprint(problem)
+
+	info, err := poly.GetProblemInfo(ctx, GetProblemInfoInput{ProblemID: problem})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read problem info: %w", err)
+	}
+
+	// writes are made from multiple goroutines, files are written into different paths
+	eg, gctx := errgroup.WithContext(ctx)
+	eg.SetLimit(5)
+
+	defer func() { _ = eg.Wait() }() // don't leave downloads running when assembly fails
+
+	save := func(name string, fetch func(context.Context) ([]byte, error)) {
+		eg.Go(func() error {
+			data, err := fetch(gctx)
+			if err != nil {
+				return fmt.Errorf("unable to download %#v: %w", name, err)
+			}
+
+			if err := os.MkdirAll(filepath.Join(path, filepath.Dir(name)), 0777); err != nil {
+				return err
+			}
+
+			return os.WriteFile(filepath.Join(path, name), data, 0644)
+		})
+	}
+
+	// statements
+	statements, err := poly.ListStatements(ctx, ListStatementsInput{ProblemID: problem})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read statements: %w", err)
+	}
+
+	var languages []string
+	for lang := range statements {
+		languages = append(languages, lang)
+	}
+
+	slices.Sort(languages)
+
+	for _, lang := range languages {
+		statement := statements[lang]
+		dir := "statements/" + lang
+
+		props, err := json.Marshal(ProblemProperties{
+			Language:    lang,
+			Name:        statement.Name,
+			Legend:      statement.Legend,
+			Input:       statement.Input,
+			Interaction: statement.Interaction,
+			Output:      statement.Output,
+			Notes:       statement.Notes,
+			Scoring:     statement.Scoring,
+			Solution:    statement.Tutorial,
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		save(dir+"/problem-properties.json", constant(props))
+
+		spec.Names = append(spec.Names, SpecificationName{Language: lang, Value: statement.Name})
+		spec.Statements = append(spec.Statements, SpecificationStatement{
+			Charset:  statement.Encoding,
+			Language: lang,
+			Path:     dir + "/problem.tex",
+			Type:     "application/x-tex",
+		})
+
+		if statement.Tutorial != "" {
+			save(dir+"/tutorial.tex", constant([]byte(statement.Tutorial)))
+			spec.Tutorials = append(spec.Tutorials, SpecificationTutorial{
+				Charset:  statement.Encoding,
+				Language: lang,
+				Path:     dir + "/tutorial.tex",
+				Type:     "application/x-tex",
+			})
+		}
+	}
+
+	resources, err := poly.ListStatementResources(ctx, ListStatementResourcesInput{ProblemID: problem})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read statement resources: %w", err)
+	}
+
+	for _, resource := range resources {
+		p.log.Printf("Statement resource %#v can not be downloaded through API", resource.Name)
+		warnings = append(warnings, connector.Warning{
+			Kind:    "missing-resource",
+			Path:    "statements/" + resource.Name,
+			Message: "statement resource can not be downloaded through API",
+		})
+	}
+
+	// files, checker, validator, interactor and generators
+	files, err := poly.ListFiles(ctx, ListFilesInput{ProblemID: problem})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read files: %w", err)
+	}
+
+	for _, file := range files.ResourceFiles {
+		resource := SpecificationResource{Path: "files/" + file.Name}
+		if props := file.ResourceAdvancedProperties; props != nil {
+			resource.ForTypes = props.ForTypes
+			for _, asset := range props.Assets {
+				resource.Assets = append(resource.Assets, SpecificationGraderAsset{Name: strings.ToLower(asset)})
+			}
+		}
+
+		spec.Resources = append(spec.Resources, resource)
+		save(resource.Path, p.viewFile(poly, problem, "resource", file.Name))
+	}
+
+	checker, err := poly.GetChecker(ctx, GetCheckerInput{ProblemID: problem})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read checker: %w", err)
+	}
+
+	validator, err := poly.GetValidator(ctx, GetValidatorInput{ProblemID: problem})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read validator: %w", err)
+	}
+
+	interactor, err := poly.GetInteractor(ctx, GetInteractorInput{ProblemID: problem})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read interactor: %w", err)
+	}
+
+	if strings.HasPrefix(checker, "std::") {
+		spec.Checker = SpecificationChecker{Name: checker, Type: "testlib"}
+	}
+
+	for _, file := range files.SourceFiles {
+		source := SpecificationSource{Path: "files/" + file.Name, Type: file.SourceType}
+
+		switch file.Name {
+		case checker:
+			spec.Checker = SpecificationChecker{Type: "testlib", Sources: []SpecificationSource{source}}
+		case validator:
+			spec.Validator = append(spec.Validator, SpecificationValidator{Type: "testlib", Sources: []SpecificationSource{source}})
+		case interactor:
+			spec.Interactor.Sources = append(spec.Interactor.Sources, source)
+		default:
+			spec.Executables = append(spec.Executables, SpecificationExecutable{Source: source})
+		}
+
+		save(source.Path, p.viewFile(poly, problem, "source", file.Name))
+	}
+
+	if info.Interactive && len(spec.Interactor.Sources) == 0 {
+		return nil, fmt.Errorf("interactor %#v is not found among source files", interactor)
+	}
+
+	// solutions
+	solutions, err := poly.ListSolutions(ctx, ListSolutionsInput{ProblemID: problem})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read solutions: %w", err)
+	}
+
+	for _, solution := range solutions {
+		tag, ok := solutionTags[solution.Tag]
+		if !ok {
+			p.log.Errorf("Skipping solution %#v because tag %#v is not mapped", solution.Name, solution.Tag)
+			continue
+		}
+
+		spec.Solutions = append(spec.Solutions, SpecificationSolution{
+			Tag:    tag,
+			Source: SpecificationSource{Path: "solutions/" + solution.Name, Type: solution.SourceType},
+		})
+
+		save("solutions/"+solution.Name, func(ctx context.Context) ([]byte, error) {
+			return poly.ViewSolution(ctx, ViewSolutionInput{ProblemID: problem, Name: solution.Name})
+		})
+	}
+
+	// tests
+	testset, err := p.assembleTestset(ctx, poly, problem, info, spec, save)
+	if err != nil {
+		return nil, err
+	}
+
+	spec.Judging = SpecificationJudging{Testsets: []SpecificationTestset{*testset}}
+
+	// tags
+	tags, err := poly.ListTags(ctx, ListTagsInput{ProblemID: problem})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read tags: %w", err)
+	}
+
+	for _, tag := range tags {
+		spec.Tags = append(spec.Tags, SpecificationTag{Value: tag})
+	}
+
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	data, err := xml.MarshalIndent(struct {
+		XMLName xml.Name `xml:"problem"`
+		*Specification
+	}{Specification: spec}, "", "  ")
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to compose problem.xml: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(path, "problem.xml"), data, 0644); err != nil {
+		return nil, fmt.Errorf("unable to write problem.xml: %w", err)
+	}
+
+	return warnings, nil
+}
+
+// assembleTestset reads tests and groups of the main testset, inputs of manual tests and examples are saved using save.
+// Polygon API has no method to list testsets, so other testsets (e.g. pretests) are not assembled.
+func (p *ProblemLoader) assembleTestset(ctx context.Context, poly *Client, problem int, info *ProblemInfo, spec *Specification, save func(string, func(context.Context) ([]byte, error))) (*SpecificationTestset, error) {
+	testset := &SpecificationTestset{
+		Name:              "tests",
+		TimeLimit:         info.TimeLimit,
+		MemoryLimit:       info.MemoryLimit * 1024 * 1024,
+		InputPathPattern:  "tests/%02d",
+		AnswerPathPattern: "tests/%02d.a",
+	}
+
+	tests, err := poly.ListTests(ctx, ListTestsInput{ProblemID: problem, Testset: testset.Name, NoInputs: true})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read tests: %w", err)
+	}
+
+	slices.SortFunc(tests, func(a, b Test) int { return a.Index - b.Index })
+
+	for i, test := range tests {
+		if test.Index != i+1 {
+			return nil, fmt.Errorf("tests are not numbered sequentially, test %v is followed by %v", i, test.Index)
+		}
+	}
+
+	for _, test := range tests {
+		in := GetTestFileInput{ProblemID: problem, Testset: testset.Name, TestIndex: test.Index}
+
+		// input is fetched once even if it's used for the test and several examples
+		input := memo(func(ctx context.Context) ([]byte, error) { return poly.GetTestInput(ctx, in) })
+		answer := memo(func(ctx context.Context) ([]byte, error) { return poly.GetTestAnswer(ctx, in) })
+
+		method := "generated"
+		if test.Manual {
+			method = "manual"
+			save(fmt.Sprintf(testset.InputPathPattern, test.Index), input)
+		}
+
+		testset.Tests = append(testset.Tests, SpecificationTest{
+			Method:  method,
+			Group:   test.Group,
+			Command: test.ScriptLine,
+			Sample:  test.UseInStatements,
+			Points:  float32(test.Points),
+		})
+
+		if !test.UseInStatements {
+			continue
+		}
+
+		// examples are stored alongside each statement
+		if test.InputForStatement != "" {
+			input = constant([]byte(test.InputForStatement))
+		}
+
+		if test.OutputForStatement != "" {
+			answer = constant([]byte(test.OutputForStatement))
+		}
+
+		for _, statement := range spec.Statements {
+			dir := filepath.Dir(statement.Path)
+			save(fmt.Sprintf("%v/example.%02d", dir, test.Index), input)
+			save(fmt.Sprintf("%v/example.%02d.a", dir, test.Index), answer)
+		}
+	}
+
+	testset.TestCount = len(testset.Tests)
+
+	groups, err := poly.ListTestGroups(ctx, ListTestGroupsInput{ProblemID: problem, Testset: testset.Name})
+	if err != nil && !slices.ContainsFunc(tests, func(t Test) bool { return t.Group != "" }) {
+		// groups are not enabled
+		return testset, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to read test groups: %w", err)
+	}
+
+	for _, group := range groups {
+		item := SpecificationGroup{
+			Name:           group.Name,
+			PointsPolicy:   policy(group.PointsPolicy),
+			FeedbackPolicy: policy(group.FeedbackPolicy),
+		}
+
+		for _, dep := range group.Dependencies {
+			item.Dependencies = append(item.Dependencies, SpecificationDependency{Group: dep})
+		}
+
+		testset.Groups = append(testset.Groups, item)
+	}
+
+	return testset, nil
+}
+
+// viewFile returns function which downloads the file
+func (p *ProblemLoader) viewFile(poly *Client, problem int, kind, name string) func(context.Context) ([]byte, error) {
+	return func(ctx context.Context) ([]byte, error) {
+		return poly.ViewFile(ctx, ViewFileInput{ProblemID: problem, Type: kind, Name: name})
+	}
+}
+
+// policy converts group policy returned by API (e.g. COMPLETE_GROUP) to the form used in problem.xml (complete-group)
+func policy(value string) string {
+	return strings.ReplaceAll(strings.ToLower(value), "_", "-")
+}
+
+// constant returns function which returns the data, it's used to save files which are already downloaded
+func constant(data []byte) func(context.Context) ([]byte, error) {
+	return func(context.Context) ([]byte, error) { return data, nil }
+}
+
+// memo returns function which calls fetch once and returns the same result to every caller
+func memo(fetch func(context.Context) ([]byte, error)) func(context.Context) ([]byte, error) {
+	var (
+		once sync.Once
+		data []byte
+		err  error
+	)
+
+	return func(ctx context.Context) ([]byte, error) {
+		once.Do(func() { data, err = fetch(ctx) })
+		return data, err
+	}
+}
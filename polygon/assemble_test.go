@@ -0,0 +1,147 @@
+package polygon
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/eolymp/go-problems/connector"
+	. "github.com/eolymp/go-problems/connector/testing"
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	executorpb "github.com/eolymp/go-sdk/eolymp/executor"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProblemLoader_Import_api(t *testing.T) {
+	ctx := context.Background()
+	stub, client := newPolygonStub(t)
+
+	stub.results["problems.list"] = []Problem{{ID: 1, Name: "a-plus-b", Revision: 5}}
+	stub.results["problem.info"] = ProblemInfo{InputFile: "stdin", OutputFile: "stdout", TimeLimit: 1000, MemoryLimit: 256}
+	stub.results["problem.statements"] = map[string]Statement{
+		"english": {Name: "A+B", Legend: "Sum two numbers", Input: "Two numbers", Output: "Their sum", Tutorial: "Just add them"},
+	}
+	stub.results["problem.statementResources"] = []File{{Name: "pic.png"}}
+	stub.results["problem.files"] = Files{SourceFiles: []File{
+		{Name: "check.cpp", SourceType: "cpp.g++17"},
+		{Name: "val.cpp", SourceType: "cpp.g++17"},
+		{Name: "gen.cpp", SourceType: "cpp.g++17"},
+	}}
+	stub.results["problem.checker"] = "check.cpp"
+	stub.results["problem.validator"] = "val.cpp"
+	stub.results["problem.interactor"] = ""
+	stub.results["problem.solutions"] = []Solution{
+		{Name: "main.cpp", SourceType: "cpp.g++17", Tag: "MA"},
+		{Name: "wa.cpp", SourceType: "cpp.g++17", Tag: "WA"},
+	}
+	stub.results["problem.tests"] = []Test{
+		{Index: 2, ScriptLine: "gen 10 20"},
+		{Index: 1, Manual: true, UseInStatements: true},
+	}
+	stub.results["problem.viewTags"] = []string{"binary search", "eolymp_tl=2000"}
+	stub.raw["problem.viewFile"] = "int main() {}"
+	stub.raw["problem.viewSolution"] = "int main() { return 0; }"
+	stub.raw["problem.testInput"] = "1 2\n"
+	stub.raw["problem.testAnswer"] = "3\n"
+
+	loader := NewProblemLoader(MockUploader(), MockLogger(t), UsePolygonOptions(UseBaseURL(client.base)))
+
+	link := url.URL{Scheme: "polygon", User: url.UserPassword("key", "secret"), Path: "/", RawQuery: "problemId=1&mode=api"}
+
+	result, err := loader.Import(ctx, link.String())
+	if err != nil {
+		t.Fatal("Problem import has failed:", err)
+	}
+
+	snap := result.Snapshot
+
+	if got := snap.GetChecker(); got.GetType() != executorpb.Checker_PROGRAM || got.GetSource() != "int main() {}" {
+		t.Errorf("Checker must be a program, got %v", got)
+	}
+
+	if snap.GetValidator().GetSource() != "int main() {}" {
+		t.Errorf("Validator must be imported, got %v", snap.GetValidator())
+	}
+
+	if got := snap.GetStatements(); len(got) != 1 || got[0].GetTitle() != "A+B" || got[0].GetLocale() != "en" {
+		t.Errorf("Statement must be imported, got %v", got)
+	}
+
+	if got := snap.GetEditorials(); len(got) != 1 || got[0].GetContent().GetLatex() != "Just add them" {
+		t.Errorf("Editorial must be imported, got %v", got)
+	}
+
+	var kinds []atlaspb.Solution_Type
+	for _, solution := range snap.GetSolutions() {
+		kinds = append(kinds, solution.GetType())
+	}
+
+	if want := []atlaspb.Solution_Type{atlaspb.Solution_CORRECT, atlaspb.Solution_WRONG_ANSWER}; !cmp.Equal(want, kinds) {
+		t.Errorf("Solutions do not match:\n%s", cmp.Diff(want, kinds))
+	}
+
+	var scripts []string
+	for _, script := range snap.GetScripts() {
+		scripts = append(scripts, script.GetName())
+	}
+
+	if want := []string{"gen", "solution"}; !cmp.Equal(want, scripts) {
+		t.Errorf("Scripts do not match:\n%s", cmp.Diff(want, scripts))
+	}
+
+	tests := snap.GetTests()
+	if len(tests) != 2 {
+		t.Fatalf("Problem must have 2 tests, got %v", len(tests))
+	}
+
+	if tests[0].GetInputUrl() == "" || !tests[0].GetExample() || tests[0].GetExampleInputUrl() == "" || tests[0].GetExampleAnswerUrl() == "" {
+		t.Errorf("Manual test must be downloaded and used as an example, got %v", tests[0])
+	}
+
+	if got := tests[1].GetInputGenerator(); got.GetScriptName() != "gen" || !cmp.Equal([]string{"10", "20"}, got.GetArguments()) {
+		t.Errorf("Generated test must use the generator, got %v", tests[1])
+	}
+
+	if got := snap.GetTestsets()[0].GetCpuLimit(); got != 2000 {
+		t.Errorf("Time limit must be taken from eolymp_tl tag, got %v", got)
+	}
+
+	if got := snap.GetTestsets()[0].GetMemoryLimit(); got != 256*1024*1024 {
+		t.Errorf("Memory limit must be converted to bytes, got %v", got)
+	}
+
+	if got := snap.GetProblem().GetTopics(); len(got) != 1 {
+		t.Errorf("Tags must be mapped to topics, got %v", got)
+	}
+
+	if p := result.Provenance; p.ProblemID != "1" || p.Revision != "5" || p.Name != "a-plus-b" || p.PackageID != "" || p.ArchiveSHA256 != "" {
+		t.Errorf("Provenance does not match, got %+v", p)
+	}
+
+	want := []connector.Warning{{Kind: "missing-resource", Path: "statements/pic.png", Message: "statement resource can not be downloaded through API"}}
+	if !cmp.Equal(want, result.Warnings) {
+		t.Errorf("Warnings do not match:\n%s", cmp.Diff(want, result.Warnings))
+	}
+
+	for _, call := range stub.calls {
+		if call.Has("packageId") {
+			t.Error("Package must not be downloaded in API mode")
+		}
+	}
+}
+
+func TestProblemLoader_importMode(t *testing.T) {
+	loader := NewProblemLoader(MockUploader(), MockLogger(t), UseMode(ModeAPI))
+
+	if mode, err := loader.importMode(url.Values{}); err != nil || mode != ModeAPI {
+		t.Errorf("Loader mode must be used by default, got %v (%v)", mode, err)
+	}
+
+	if mode, err := loader.importMode(url.Values{"mode": {"package"}}); err != nil || mode != ModePackage {
+		t.Errorf("Link must override loader mode, got %v (%v)", mode, err)
+	}
+
+	if _, err := loader.importMode(url.Values{"mode": {"ftp"}}); err == nil {
+		t.Error("Unknown mode must be rejected")
+	}
+}
@@ -1,12 +1,17 @@
 package polygon
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
+	"mime/multipart"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -15,19 +20,29 @@ type httpClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// Client for Polygon API, it's safe for concurrent use. Requests are throttled by the rate limiter (see UseRateLimit)
+// and retried when Polygon responds with 429 status or asks to slow down (see UseRetry). Read methods are also retried
+// on 5xx status, write methods are not, since the change may have been applied before the failure.
 type Client struct {
-	key    string
-	secret string
-	base   string
-	cli    httpClient
+	key     string
+	secret  string
+	base    string
+	cli     httpClient
+	limit   *limiter
+	retries int
+	backoff time.Duration
+	timeout time.Duration
 }
 
 func New(key, secret string, opts ...func(*Client)) *Client {
 	cli := &Client{
-		key:    key,
-		secret: secret,
-		base:   "https://polygon.codeforces.com/api/",
-		cli:    http.DefaultClient,
+		key:     key,
+		secret:  secret,
+		base:    "https://polygon.codeforces.com/api/",
+		cli:     http.DefaultClient,
+		limit:   &limiter{},
+		retries: 3,
+		backoff: time.Second,
 	}
 
 	for _, opt := range opts {
@@ -37,12 +52,8 @@ func New(key, secret string, opts ...func(*Client)) *Client {
 	return cli
 }
 
-func (c *Client) request(ctx context.Context, method string, params map[string]string) (*http.Response, error) {
-	base, err := url.Parse(c.base)
-	if err != nil {
-		return nil, fmt.Errorf("base URL %#v is corrupted: %w", c.base, err)
-	}
-
+// sign adds time, API key and signature to the parameters, content of the files is signed as a regular parameter
+func (c *Client) sign(method string, params map[string]string, files map[string][]byte) url.Values {
 	query := url.Values{}
 	for k, v := range params {
 		query.Set(k, v)
@@ -50,26 +61,239 @@ func (c *Client) request(ctx context.Context, method string, params map[string]s
 
 	query.Set("time", fmt.Sprint(time.Now().Unix()))
 	query.Set("apiKey", c.key)
-	query.Set("apiSig", Signature(method, c.secret, query))
+
+	signed := url.Values{}
+	for k, v := range query {
+		signed[k] = v
+	}
+
+	for k, v := range files {
+		signed.Set(k, string(v))
+	}
+
+	query.Set("apiSig", Signature(method, c.secret, signed))
+
+	return query
+}
+
+func (c *Client) endpoint(method string) (string, error) {
+	base, err := url.Parse(c.base)
+	if err != nil {
+		return "", fmt.Errorf("base URL %#v is corrupted: %w", c.base, err)
+	}
 
 	base.Path = strings.TrimSuffix(base.Path, "/") + "/" + url.PathEscape(method)
 
-	req, err := http.NewRequest(http.MethodGet, base.String()+"?"+query.Encode(), nil)
+	return base.String(), nil
+}
+
+func (c *Client) request(ctx context.Context, method string, params map[string]string) (*http.Response, error) {
+	endpoint, err := c.endpoint(method)
 	if err != nil {
-		return nil, fmt.Errorf("unable to compose HTTP request: %w", err)
+		return nil, err
 	}
 
-	resp, err := c.cli.Do(req.WithContext(ctx))
+	return c.do(ctx, method, true, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+c.sign(method, params, nil).Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to compose HTTP request: %w", err)
+		}
+
+		return req, nil
+	})
+}
+
+// send makes POST request with multipart body, files are sent as file parts
+func (c *Client) send(ctx context.Context, method string, params map[string]string, files map[string][]byte) (*http.Response, error) {
+	endpoint, err := c.endpoint(method)
 	if err != nil {
 		return nil, err
 	}
 
+	return c.do(ctx, method, false, func(ctx context.Context) (*http.Request, error) {
+		body := &bytes.Buffer{}
+		form := multipart.NewWriter(body)
+
+		for k, v := range c.sign(method, params, files) {
+			if err := form.WriteField(k, v[0]); err != nil {
+				return nil, fmt.Errorf("unable to compose HTTP request: %w", err)
+			}
+		}
+
+		for k, v := range files {
+			part, err := form.CreateFormFile(k, k)
+			if err != nil {
+				return nil, fmt.Errorf("unable to compose HTTP request: %w", err)
+			}
+
+			if _, err := part.Write(v); err != nil {
+				return nil, fmt.Errorf("unable to compose HTTP request: %w", err)
+			}
+		}
+
+		if err := form.Close(); err != nil {
+			return nil, fmt.Errorf("unable to compose HTTP request: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+		if err != nil {
+			return nil, fmt.Errorf("unable to compose HTTP request: %w", err)
+		}
+
+		req.Header.Set("Content-Type", form.FormDataContentType())
+
+		return req, nil
+	})
+}
+
+// do sends request, the request is composed for each attempt, so it's signed with the current time. Throttled
+// requests are retried with exponential backoff, failed (5xx) requests are retried only if they are idempotent. API
+// failures are returned as *Error.
+func (c *Client) do(ctx context.Context, method string, idempotent bool, compose func(context.Context) (*http.Request, error)) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, wait, err := c.attempt(ctx, method, idempotent, compose)
+		if err == nil {
+			return resp, nil
+		}
+
+		if wait < 0 || attempt >= c.retries {
+			return nil, err
+		}
+
+		// exponential backoff with jitter, unless server says how long to wait
+		if wait == 0 {
+			wait = c.backoff << attempt
+			wait += rand.N(wait/2 + 1)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(wait):
+		}
+	}
+}
+
+// attempt makes a single request, the second value is negative if request must not be retried, otherwise it is a
+// delay requested by the server (zero if not specified)
+func (c *Client) attempt(ctx context.Context, method string, idempotent bool, compose func(context.Context) (*http.Request, error)) (*http.Response, time.Duration, error) {
+	if err := c.limit.wait(ctx); err != nil {
+		return nil, -1, err
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if c.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+	}
+
+	req, err := compose(ctx)
+	if err != nil {
+		cancel()
+		return nil, -1, err
+	}
+
+	resp, err := c.cli.Do(req)
+	if err != nil {
+		cancel()
+		return nil, -1, err
+	}
+
+	// the timeout covers reading the body, so the context is released when the body is closed
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+
+	wait := time.Duration(-1)
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 && idempotent {
+		wait = retryAfter(resp.Header.Get("Retry-After"))
+	}
+
 	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		_ = resp.Body.Close()
+
+		// Polygon puts its error into the body, status text is used when there is none
+		fail := &Error{Method: method, Status: resp.StatusCode, Comment: http.StatusText(resp.StatusCode)}
+
+		envelop := &Envelop{}
+		if err := json.Unmarshal(data, envelop); err == nil && envelop.Status == "FAILED" {
+			fail.Comment = envelop.Comment
+		}
+
+		if wait < 0 && errors.Is(fail, ErrRateLimit) {
+			wait = 0
+		}
+
+		return nil, wait, fail
+	}
+
+	// API responds with an envelope when requests are too frequent, it's read ahead to find out if request should be
+	// retried, the body is replaced with the buffered copy
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		data, err := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
-		return nil, fmt.Errorf("response status code is not OK: %v", resp.Status)
+
+		if err != nil {
+			return nil, -1, err
+		}
+
+		envelop := &Envelop{}
+		if err := json.Unmarshal(data, envelop); err == nil && envelop.Status == "FAILED" && throttled(envelop.Comment) {
+			return nil, 0, &Error{Method: method, Status: resp.StatusCode, Comment: envelop.Comment}
+		}
+
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+	}
+
+	return resp, 0, nil
+}
+
+// throttled returns true if error comment says API is called too often
+func throttled(comment string) bool {
+	comment = strings.ToLower(comment)
+	return strings.Contains(comment, "too many requests") || strings.Contains(comment, "too frequent")
+}
+
+// retryAfter parses Retry-After header given in seconds, zero means the delay is not specified
+func retryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(header))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// raw calls method which returns plain text instead of JSON, errors are still returned as JSON envelopes
+func (c *Client) raw(ctx context.Context, method string, params map[string]string) ([]byte, error) {
+	resp, err := c.request(ctx, method, params)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		envelop := &Envelop{method: method, code: resp.StatusCode}
+		if err := json.Unmarshal(data, envelop); err == nil && envelop.Status != "" && envelop.Status != "OK" {
+			return nil, envelop.Err()
+		}
 	}
 
-	return resp, nil
+	return data, nil
 }
 
 func (c *Client) call(ctx context.Context, method string, params map[string]string) (*Envelop, error) {
@@ -80,7 +304,7 @@ func (c *Client) call(ctx context.Context, method string, params map[string]stri
 
 	defer resp.Body.Close()
 
-	envelop := &Envelop{}
+	envelop := &Envelop{method: method, code: resp.StatusCode}
 
 	if err := json.NewDecoder(resp.Body).Decode(envelop); err != nil {
 		return nil, err
@@ -89,6 +313,24 @@ func (c *Client) call(ctx context.Context, method string, params map[string]stri
 	return envelop, nil
 }
 
+// post calls write method, the result is ignored
+func (c *Client) post(ctx context.Context, method string, params map[string]string, files map[string][]byte) (*Envelop, error) {
+	resp, err := c.send(ctx, method, params, files)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	envelop := &Envelop{method: method, code: resp.StatusCode}
+
+	if err := json.NewDecoder(resp.Body).Decode(envelop); err != nil {
+		return nil, err
+	}
+
+	return envelop, envelop.Err()
+}
+
 func (c *Client) ListPackages(ctx context.Context, in ListPackagesInput) ([]Package, error) {
 	env, err := c.call(ctx, "problem.packages", map[string]string{"problemId": fmt.Sprint(in.ProblemID)})
 	if err != nil {
@@ -115,5 +357,575 @@ func (c *Client) DownloadPackage(ctx context.Context, in DownloadPackageInput) (
 		return nil, err
 	}
 
+	// package is a zip archive, failures are returned as JSON envelopes
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		defer resp.Body.Close()
+
+		envelop := &Envelop{method: "problem.package", code: resp.StatusCode}
+		if err := json.NewDecoder(resp.Body).Decode(envelop); err != nil {
+			return nil, err
+		}
+
+		if err := envelop.Err(); err != nil {
+			return nil, err
+		}
+
+		return nil, errors.New("package is not an archive")
+	}
+
 	return resp.Body, nil
 }
+
+func (c *Client) GetProblemInfo(ctx context.Context, in GetProblemInfoInput) (*ProblemInfo, error) {
+	env, err := c.call(ctx, "problem.info", map[string]string{"problemId": fmt.Sprint(in.ProblemID)})
+	if err != nil {
+		return nil, err
+	}
+
+	info := &ProblemInfo{}
+
+	if err := env.Unmarshal(info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// ListStatements returns statements of the problem by language (e.g. english, russian).
+func (c *Client) ListStatements(ctx context.Context, in ListStatementsInput) (map[string]Statement, error) {
+	env, err := c.call(ctx, "problem.statements", map[string]string{"problemId": fmt.Sprint(in.ProblemID)})
+	if err != nil {
+		return nil, err
+	}
+
+	var statements map[string]Statement
+
+	if err := env.Unmarshal(&statements); err != nil {
+		return nil, err
+	}
+
+	return statements, nil
+}
+
+func (c *Client) ListStatementResources(ctx context.Context, in ListStatementResourcesInput) ([]File, error) {
+	env, err := c.call(ctx, "problem.statementResources", map[string]string{"problemId": fmt.Sprint(in.ProblemID)})
+	if err != nil {
+		return nil, err
+	}
+
+	var files []File
+
+	if err := env.Unmarshal(&files); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// GetChecker returns name of the checker file.
+func (c *Client) GetChecker(ctx context.Context, in GetCheckerInput) (string, error) {
+	return c.name(ctx, "problem.checker", in.ProblemID)
+}
+
+// GetValidator returns name of the validator file.
+func (c *Client) GetValidator(ctx context.Context, in GetValidatorInput) (string, error) {
+	return c.name(ctx, "problem.validator", in.ProblemID)
+}
+
+// GetInteractor returns name of the interactor file, it's empty if problem is not interactive.
+func (c *Client) GetInteractor(ctx context.Context, in GetInteractorInput) (string, error) {
+	return c.name(ctx, "problem.interactor", in.ProblemID)
+}
+
+func (c *Client) name(ctx context.Context, method string, problem int) (string, error) {
+	env, err := c.call(ctx, method, map[string]string{"problemId": fmt.Sprint(problem)})
+	if err != nil {
+		return "", err
+	}
+
+	var name string
+
+	if err := env.Unmarshal(&name); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+func (c *Client) ListFiles(ctx context.Context, in ListFilesInput) (*Files, error) {
+	env, err := c.call(ctx, "problem.files", map[string]string{"problemId": fmt.Sprint(in.ProblemID)})
+	if err != nil {
+		return nil, err
+	}
+
+	files := &Files{}
+
+	if err := env.Unmarshal(files); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+func (c *Client) ListSolutions(ctx context.Context, in ListSolutionsInput) ([]Solution, error) {
+	env, err := c.call(ctx, "problem.solutions", map[string]string{"problemId": fmt.Sprint(in.ProblemID)})
+	if err != nil {
+		return nil, err
+	}
+
+	var solutions []Solution
+
+	if err := env.Unmarshal(&solutions); err != nil {
+		return nil, err
+	}
+
+	return solutions, nil
+}
+
+// ViewFile returns content of the resource, source or aux file.
+func (c *Client) ViewFile(ctx context.Context, in ViewFileInput) ([]byte, error) {
+	return c.raw(ctx, "problem.viewFile", map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"type":      in.Type,
+		"name":      in.Name,
+	})
+}
+
+// ViewSolution returns source code of the solution.
+func (c *Client) ViewSolution(ctx context.Context, in ViewSolutionInput) ([]byte, error) {
+	return c.raw(ctx, "problem.viewSolution", map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"name":      in.Name,
+	})
+}
+
+// GetScript returns generation script of the testset.
+func (c *Client) GetScript(ctx context.Context, in GetScriptInput) (string, error) {
+	data, err := c.raw(ctx, "problem.script", map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"testset":   in.Testset,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// GetTestInput returns input of the test, generated tests are generated by Polygon.
+func (c *Client) GetTestInput(ctx context.Context, in GetTestFileInput) ([]byte, error) {
+	return c.testFile(ctx, "problem.testInput", in)
+}
+
+// GetTestAnswer returns answer of the test, it's produced by the main solution.
+func (c *Client) GetTestAnswer(ctx context.Context, in GetTestFileInput) ([]byte, error) {
+	return c.testFile(ctx, "problem.testAnswer", in)
+}
+
+func (c *Client) testFile(ctx context.Context, method string, in GetTestFileInput) ([]byte, error) {
+	return c.raw(ctx, method, map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"testset":   in.Testset,
+		"testIndex": fmt.Sprint(in.TestIndex),
+	})
+}
+
+func (c *Client) ListTests(ctx context.Context, in ListTestsInput) ([]Test, error) {
+	params := map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"testset":   in.Testset,
+	}
+
+	if in.NoInputs {
+		params["noInputs"] = "true"
+	}
+
+	env, err := c.call(ctx, "problem.tests", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var tests []Test
+
+	if err := env.Unmarshal(&tests); err != nil {
+		return nil, err
+	}
+
+	return tests, nil
+}
+
+// ListTestGroups returns groups of the testset, or a single group if Group is set.
+func (c *Client) ListTestGroups(ctx context.Context, in ListTestGroupsInput) ([]TestGroup, error) {
+	params := map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"testset":   in.Testset,
+	}
+
+	if in.Group != "" {
+		params["group"] = in.Group
+	}
+
+	env, err := c.call(ctx, "problem.testGroup", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []TestGroup
+
+	if err := env.Unmarshal(&groups); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func (c *Client) ListTags(ctx context.Context, in ListTagsInput) ([]string, error) {
+	env, err := c.call(ctx, "problem.viewTags", map[string]string{"problemId": fmt.Sprint(in.ProblemID)})
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+
+	if err := env.Unmarshal(&tags); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (c *Client) GetGeneralDescription(ctx context.Context, in GetGeneralDescriptionInput) (string, error) {
+	env, err := c.call(ctx, "problem.viewGeneralDescription", map[string]string{"problemId": fmt.Sprint(in.ProblemID)})
+	if err != nil {
+		return "", err
+	}
+
+	var description string
+
+	if err := env.Unmarshal(&description); err != nil {
+		return "", err
+	}
+
+	return description, nil
+}
+
+// ListProblems returns problems available to the user, filtered by input.
+func (c *Client) ListProblems(ctx context.Context, in ListProblemsInput) ([]Problem, error) {
+	params := map[string]string{}
+
+	if in.ShowDeleted {
+		params["showDeleted"] = "true"
+	}
+
+	if in.ID != 0 {
+		params["id"] = fmt.Sprint(in.ID)
+	}
+
+	if in.Name != "" {
+		params["name"] = in.Name
+	}
+
+	if in.Owner != "" {
+		params["owner"] = in.Owner
+	}
+
+	env, err := c.call(ctx, "problems.list", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []Problem
+
+	if err := env.Unmarshal(&problems); err != nil {
+		return nil, err
+	}
+
+	return problems, nil
+}
+
+// ListContestProblems returns problems of the contest by problem index (A, B, C...).
+func (c *Client) ListContestProblems(ctx context.Context, in ListContestProblemsInput) (map[string]Problem, error) {
+	env, err := c.call(ctx, "contest.problems", map[string]string{"contestId": fmt.Sprint(in.ContestID)})
+	if err != nil {
+		return nil, err
+	}
+
+	var problems map[string]Problem
+
+	if err := env.Unmarshal(&problems); err != nil {
+		return nil, err
+	}
+
+	return problems, nil
+}
+
+func (c *Client) CreateProblem(ctx context.Context, in CreateProblemInput) (*Problem, error) {
+	env, err := c.post(ctx, "problem.create", map[string]string{"name": in.Name}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	problem := &Problem{}
+
+	if err := env.Unmarshal(problem); err != nil {
+		return nil, err
+	}
+
+	return problem, nil
+}
+
+// UpdateProblemInfo updates general information of the problem, only non-empty fields are updated.
+func (c *Client) UpdateProblemInfo(ctx context.Context, in UpdateProblemInfoInput) error {
+	params := map[string]string{"problemId": fmt.Sprint(in.ProblemID)}
+
+	if in.InputFile != "" {
+		params["inputFile"] = in.InputFile
+	}
+
+	if in.OutputFile != "" {
+		params["outputFile"] = in.OutputFile
+	}
+
+	if in.Interactive != nil {
+		params["interactive"] = fmt.Sprint(*in.Interactive)
+	}
+
+	if in.TimeLimit != 0 {
+		params["timeLimit"] = fmt.Sprint(in.TimeLimit)
+	}
+
+	if in.MemoryLimit != 0 {
+		params["memoryLimit"] = fmt.Sprint(in.MemoryLimit)
+	}
+
+	_, err := c.post(ctx, "problem.updateInfo", params, nil)
+	return err
+}
+
+// SaveStatement creates or updates statement in the language, only non-empty fields are updated.
+func (c *Client) SaveStatement(ctx context.Context, in SaveStatementInput) error {
+	params := map[string]string{"problemId": fmt.Sprint(in.ProblemID), "lang": in.Lang}
+
+	for name, value := range map[string]string{
+		"encoding":    in.Encoding,
+		"name":        in.Name,
+		"legend":      in.Legend,
+		"input":       in.Input,
+		"output":      in.Output,
+		"scoring":     in.Scoring,
+		"interaction": in.Interaction,
+		"notes":       in.Notes,
+		"tutorial":    in.Tutorial,
+	} {
+		if value != "" {
+			params[name] = value
+		}
+	}
+
+	_, err := c.post(ctx, "problem.saveStatement", params, nil)
+	return err
+}
+
+func (c *Client) SaveStatementResource(ctx context.Context, in SaveStatementResourceInput) error {
+	params := map[string]string{
+		"problemId":     fmt.Sprint(in.ProblemID),
+		"checkExisting": fmt.Sprint(in.CheckExisting),
+		"name":          in.Name,
+	}
+
+	_, err := c.post(ctx, "problem.saveStatementResource", params, map[string][]byte{"file": in.File})
+	return err
+}
+
+func (c *Client) SaveFile(ctx context.Context, in SaveFileInput) error {
+	params := map[string]string{
+		"problemId":     fmt.Sprint(in.ProblemID),
+		"checkExisting": fmt.Sprint(in.CheckExisting),
+		"type":          in.Type,
+		"name":          in.Name,
+	}
+
+	if in.SourceType != "" {
+		params["sourceType"] = in.SourceType
+	}
+
+	if in.ForTypes != "" {
+		params["forTypes"] = in.ForTypes
+	}
+
+	if len(in.Stages) > 0 {
+		params["stages"] = strings.Join(in.Stages, ";")
+	}
+
+	if len(in.Assets) > 0 {
+		params["assets"] = strings.Join(in.Assets, ";")
+	}
+
+	_, err := c.post(ctx, "problem.saveFile", params, map[string][]byte{"file": in.File})
+	return err
+}
+
+func (c *Client) SaveSolution(ctx context.Context, in SaveSolutionInput) error {
+	params := map[string]string{
+		"problemId":     fmt.Sprint(in.ProblemID),
+		"checkExisting": fmt.Sprint(in.CheckExisting),
+		"name":          in.Name,
+		"tag":           in.Tag,
+	}
+
+	if in.SourceType != "" {
+		params["sourceType"] = in.SourceType
+	}
+
+	_, err := c.post(ctx, "problem.saveSolution", params, map[string][]byte{"file": in.File})
+	return err
+}
+
+func (c *Client) SaveTest(ctx context.Context, in SaveTestInput) error {
+	params := map[string]string{
+		"problemId":     fmt.Sprint(in.ProblemID),
+		"checkExisting": fmt.Sprint(in.CheckExisting),
+		"testset":       in.Testset,
+		"testIndex":     fmt.Sprint(in.TestIndex),
+	}
+
+	if in.TestGroup != "" {
+		params["testGroup"] = in.TestGroup
+	}
+
+	if in.TestPoints != nil {
+		params["testPoints"] = strconv.FormatFloat(*in.TestPoints, 'f', -1, 64)
+	}
+
+	if in.TestDescription != "" {
+		params["testDescription"] = in.TestDescription
+	}
+
+	if in.TestUseInStatements {
+		params["testUseInStatements"] = "true"
+	}
+
+	if in.TestInputForStatements != "" {
+		params["testInputForStatements"] = in.TestInputForStatements
+	}
+
+	if in.TestOutputForStatements != "" {
+		params["testOutputForStatements"] = in.TestOutputForStatements
+	}
+
+	if in.VerifyInputOutputForStatements != nil {
+		params["verifyInputOutputForStatements"] = fmt.Sprint(*in.VerifyInputOutputForStatements)
+	}
+
+	_, err := c.post(ctx, "problem.saveTest", params, map[string][]byte{"testInput": in.TestInput})
+	return err
+}
+
+// SetChecker sets checker, it must be one of the source files or a standard checker (e.g. std::wcmp.cpp).
+func (c *Client) SetChecker(ctx context.Context, in SetCheckerInput) error {
+	_, err := c.post(ctx, "problem.setChecker", map[string]string{"problemId": fmt.Sprint(in.ProblemID), "checker": in.Checker}, nil)
+	return err
+}
+
+func (c *Client) SetValidator(ctx context.Context, in SetValidatorInput) error {
+	_, err := c.post(ctx, "problem.setValidator", map[string]string{"problemId": fmt.Sprint(in.ProblemID), "validator": in.Validator}, nil)
+	return err
+}
+
+func (c *Client) SetInteractor(ctx context.Context, in SetInteractorInput) error {
+	_, err := c.post(ctx, "problem.setInteractor", map[string]string{"problemId": fmt.Sprint(in.ProblemID), "interactor": in.Interactor}, nil)
+	return err
+}
+
+func (c *Client) SaveScript(ctx context.Context, in SaveScriptInput) error {
+	params := map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"testset":   in.Testset,
+		"source":    in.Source,
+	}
+
+	_, err := c.post(ctx, "problem.saveScript", params, nil)
+	return err
+}
+
+func (c *Client) EnableGroups(ctx context.Context, in EnableGroupsInput) error {
+	params := map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"testset":   in.Testset,
+		"enable":    fmt.Sprint(in.Enable),
+	}
+
+	_, err := c.post(ctx, "problem.enableGroups", params, nil)
+	return err
+}
+
+func (c *Client) EnablePoints(ctx context.Context, in EnablePointsInput) error {
+	params := map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"enable":    fmt.Sprint(in.Enable),
+	}
+
+	_, err := c.post(ctx, "problem.enablePoints", params, nil)
+	return err
+}
+
+func (c *Client) SaveTestGroup(ctx context.Context, in SaveTestGroupInput) error {
+	params := map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"testset":   in.Testset,
+		"group":     in.Group,
+	}
+
+	if in.PointsPolicy != "" {
+		params["pointsPolicy"] = in.PointsPolicy
+	}
+
+	if in.FeedbackPolicy != "" {
+		params["feedbackPolicy"] = in.FeedbackPolicy
+	}
+
+	if in.Dependencies != nil {
+		params["dependencies"] = strings.Join(in.Dependencies, ",")
+	}
+
+	_, err := c.post(ctx, "problem.saveTestGroup", params, nil)
+	return err
+}
+
+// SaveTags replaces tags of the problem.
+func (c *Client) SaveTags(ctx context.Context, in SaveTagsInput) error {
+	params := map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"tags":      strings.Join(in.Tags, ","),
+	}
+
+	_, err := c.post(ctx, "problem.saveTags", params, nil)
+	return err
+}
+
+func (c *Client) CommitChanges(ctx context.Context, in CommitChangesInput) error {
+	params := map[string]string{
+		"problemId":    fmt.Sprint(in.ProblemID),
+		"minorChanges": fmt.Sprint(in.MinorChanges),
+	}
+
+	if in.Message != "" {
+		params["message"] = in.Message
+	}
+
+	_, err := c.post(ctx, "problem.commitChanges", params, nil)
+	return err
+}
+
+// BuildPackage starts package build, use ListPackages to track its state.
+func (c *Client) BuildPackage(ctx context.Context, in BuildPackageInput) error {
+	params := map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"full":      fmt.Sprint(in.Full),
+		"verify":    fmt.Sprint(in.Verify),
+	}
+
+	_, err := c.post(ctx, "problem.buildPackage", params, nil)
+	return err
+}
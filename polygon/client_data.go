@@ -18,3 +18,298 @@ type Package struct {
 	Comment             string `json:"comment"`             // comment for the package
 	Type                string `json:"type"`                // type of the package: standard/linux/windows
 }
+
+type GetProblemInfoInput struct {
+	ProblemID int
+}
+
+type ProblemInfo struct {
+	InputFile   string `json:"inputFile"`   // name of the input file or stdin
+	OutputFile  string `json:"outputFile"`  // name of the output file or stdout
+	Interactive bool   `json:"interactive"` // whether problem is interactive
+	TimeLimit   int    `json:"timeLimit"`   // time limit in milliseconds
+	MemoryLimit int    `json:"memoryLimit"` // memory limit in megabytes
+}
+
+type ListStatementsInput struct {
+	ProblemID int
+}
+
+type Statement struct {
+	Encoding    string `json:"encoding"`    // statement encoding
+	Name        string `json:"name"`        // problem name in statement language
+	Legend      string `json:"legend"`      // problem legend
+	Input       string `json:"input"`       // input format
+	Output      string `json:"output"`      // output format
+	Scoring     string `json:"scoring"`     // scoring
+	Interaction string `json:"interaction"` // interaction protocol, interactive problems only
+	Notes       string `json:"notes"`       // statement notes
+	Tutorial    string `json:"tutorial"`    // problem tutorial
+}
+
+type ListStatementResourcesInput struct {
+	ProblemID int
+}
+
+type File struct {
+	Name                       string                      `json:"name"`                                 // file name
+	ModificationTimeSeconds    int                         `json:"modificationTimeSeconds"`              // modification time in unix format
+	Length                     int                         `json:"length"`                               // file length
+	SourceType                 string                      `json:"sourceType,omitempty"`                 // source type, source files only
+	ResourceAdvancedProperties *ResourceAdvancedProperties `json:"resourceAdvancedProperties,omitempty"` // resource files only
+}
+
+type ResourceAdvancedProperties struct {
+	ForTypes string   `json:"forTypes"` // semicolon separated file types the resource is used with, e.g. cpp.*
+	Main     bool     `json:"main"`     // not used at the moment
+	Stages   []string `json:"stages"`   // COMPILE and/or RUN
+	Assets   []string `json:"assets"`   // VALIDATOR, INTERACTOR, CHECKER and/or SOLUTION
+}
+
+type GetCheckerInput struct {
+	ProblemID int
+}
+
+type GetValidatorInput struct {
+	ProblemID int
+}
+
+type GetInteractorInput struct {
+	ProblemID int
+}
+
+type ListFilesInput struct {
+	ProblemID int
+}
+
+type Files struct {
+	ResourceFiles []File `json:"resourceFiles"`
+	SourceFiles   []File `json:"sourceFiles"`
+	AuxFiles      []File `json:"auxFiles"`
+}
+
+type ListSolutionsInput struct {
+	ProblemID int
+}
+
+type Solution struct {
+	Name                    string `json:"name"`                    // solution name
+	ModificationTimeSeconds int    `json:"modificationTimeSeconds"` // modification time in unix format
+	Length                  int    `json:"length"`                  // solution length
+	SourceType              string `json:"sourceType"`              // source type
+	Tag                     string `json:"tag"`                     // MA (main), OK, RJ, TL, TO, WA, PE, ML or RE
+}
+
+type ViewFileInput struct {
+	ProblemID int
+	Type      string // resource, source or aux
+	Name      string
+}
+
+type ViewSolutionInput struct {
+	ProblemID int
+	Name      string
+}
+
+type GetScriptInput struct {
+	ProblemID int
+	Testset   string
+}
+
+type GetTestFileInput struct {
+	ProblemID int
+	Testset   string
+	TestIndex int
+}
+
+type ListTestsInput struct {
+	ProblemID int
+	Testset   string
+	NoInputs  bool // do not return inputs of manual tests
+}
+
+type Test struct {
+	Index                          int     `json:"index"`                                    // test index
+	Manual                         bool    `json:"manual"`                                   // whether test is manual or generated
+	Input                          string  `json:"input,omitempty"`                          // test input, manual tests only
+	Description                    string  `json:"description,omitempty"`                    // test description
+	UseInStatements                bool    `json:"useInStatements"`                          // whether test is an example
+	ScriptLine                     string  `json:"scriptLine,omitempty"`                     // generation command, generated tests only
+	Group                          string  `json:"group,omitempty"`                          // test group, if groups are enabled
+	Points                         float64 `json:"points,omitempty"`                         // test points, if points are enabled
+	InputForStatement              string  `json:"inputForStatement,omitempty"`              // input shown in statements
+	OutputForStatement             string  `json:"outputForStatement,omitempty"`             // output shown in statements
+	VerifyInputOutputForStatements bool    `json:"verifyInputOutputForStatements,omitempty"` // whether statement input and output are verified
+}
+
+type ListTestGroupsInput struct {
+	ProblemID int
+	Testset   string
+	Group     string // optional, return only this group
+}
+
+type TestGroup struct {
+	Name           string   `json:"name"`           // group name
+	PointsPolicy   string   `json:"pointsPolicy"`   // COMPLETE_GROUP or EACH_TEST
+	FeedbackPolicy string   `json:"feedbackPolicy"` // NONE, POINTS, ICPC or COMPLETE
+	Dependencies   []string `json:"dependencies"`   // names of groups this group depends on
+}
+
+type ListTagsInput struct {
+	ProblemID int
+}
+
+type GetGeneralDescriptionInput struct {
+	ProblemID int
+}
+
+type ListProblemsInput struct {
+	ShowDeleted bool   // include deleted problems
+	ID          int    // optional, filter by problem ID
+	Name        string // optional, filter by problem name
+	Owner       string // optional, filter by problem owner
+}
+
+type Problem struct {
+	ID            int    `json:"id"`                      // problem ID
+	Owner         string `json:"owner"`                   // owner handle
+	Name          string `json:"name"`                    // problem name
+	Deleted       bool   `json:"deleted"`                 // whether problem is deleted
+	Favourite     bool   `json:"favourite"`               // whether problem is in user's favourites
+	AccessType    string `json:"accessType"`              // READ, WRITE or OWNER
+	Revision      int    `json:"revision"`                // current revision
+	LatestPackage int    `json:"latestPackage,omitempty"` // revision of the latest package
+	Modified      bool   `json:"modified"`                // whether problem has uncommitted changes
+}
+
+type ListContestProblemsInput struct {
+	ContestID int
+}
+
+type CreateProblemInput struct {
+	Name string
+}
+
+type UpdateProblemInfoInput struct {
+	ProblemID   int
+	InputFile   string // optional
+	OutputFile  string // optional
+	Interactive *bool  // optional
+	TimeLimit   int    // optional, in milliseconds
+	MemoryLimit int    // optional, in megabytes
+}
+
+type SaveStatementInput struct {
+	ProblemID   int
+	Lang        string // statement language, e.g. english
+	Encoding    string // optional, e.g. UTF-8
+	Name        string
+	Legend      string
+	Input       string
+	Output      string
+	Scoring     string
+	Interaction string
+	Notes       string
+	Tutorial    string
+}
+
+type SaveStatementResourceInput struct {
+	ProblemID     int
+	CheckExisting bool // fail if file already exists
+	Name          string
+	File          []byte
+}
+
+type SaveFileInput struct {
+	ProblemID     int
+	CheckExisting bool   // fail if file already exists
+	Type          string // resource, source or aux
+	Name          string
+	File          []byte
+	SourceType    string   // optional, source files only
+	ForTypes      string   // optional, resource files only, e.g. cpp.*
+	Stages        []string // optional, resource files only, COMPILE and/or RUN
+	Assets        []string // optional, resource files only, VALIDATOR, INTERACTOR, CHECKER and/or SOLUTION
+}
+
+type SaveSolutionInput struct {
+	ProblemID     int
+	CheckExisting bool // fail if solution already exists
+	Name          string
+	File          []byte
+	SourceType    string // optional
+	Tag           string // MA (main), OK, RJ, TL, TO, WA, PE, ML or RE
+}
+
+type SaveTestInput struct {
+	ProblemID                      int
+	CheckExisting                  bool // fail if test already exists
+	Testset                        string
+	TestIndex                      int
+	TestInput                      []byte
+	TestGroup                      string   // optional, groups must be enabled
+	TestPoints                     *float64 // optional, points must be enabled
+	TestDescription                string   // optional
+	TestUseInStatements            bool     // test is an example
+	TestInputForStatements         string   // optional, input shown in statements
+	TestOutputForStatements        string   // optional, output shown in statements
+	VerifyInputOutputForStatements *bool    // optional
+}
+
+type SetCheckerInput struct {
+	ProblemID int
+	Checker   string
+}
+
+type SetValidatorInput struct {
+	ProblemID int
+	Validator string
+}
+
+type SetInteractorInput struct {
+	ProblemID  int
+	Interactor string
+}
+
+type SaveScriptInput struct {
+	ProblemID int
+	Testset   string
+	Source    string
+}
+
+type EnableGroupsInput struct {
+	ProblemID int
+	Testset   string
+	Enable    bool
+}
+
+type EnablePointsInput struct {
+	ProblemID int
+	Enable    bool
+}
+
+type SaveTestGroupInput struct {
+	ProblemID      int
+	Testset        string
+	Group          string
+	PointsPolicy   string   // optional, COMPLETE_GROUP or EACH_TEST
+	FeedbackPolicy string   // optional, NONE, POINTS, ICPC or COMPLETE
+	Dependencies   []string // optional, names of groups this group depends on
+}
+
+type SaveTagsInput struct {
+	ProblemID int
+	Tags      []string
+}
+
+type CommitChangesInput struct {
+	ProblemID    int
+	MinorChanges bool // do not send notifications
+	Message      string
+}
+
+type BuildPackageInput struct {
+	ProblemID int
+	Full      bool // build full package (with generated tests and answers)
+	Verify    bool // run solutions and check their tags
+}
@@ -3,18 +3,29 @@ package polygon
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 )
 
 type Envelop struct {
 	Status  string           `json:"status"`
 	Comment string           `json:"comment"`
 	Result  *json.RawMessage `json:"result"`
+
+	method string // API method the envelope is returned by
+	code   int    // HTTP status code
 }
 
-func (e *Envelop) Unmarshal(v any) error {
+// Err returns an error if API request has failed, the error is *Error.
+func (e *Envelop) Err() error {
 	if e.Status != "OK" {
-		return fmt.Errorf("API request failed: %v", e.Comment)
+		return &Error{Method: e.method, Status: e.code, Comment: e.Comment}
+	}
+
+	return nil
+}
+
+func (e *Envelop) Unmarshal(v any) error {
+	if err := e.Err(); err != nil {
+		return err
 	}
 
 	if e.Result == nil {
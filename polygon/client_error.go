@@ -0,0 +1,64 @@
+package polygon
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Classes of Polygon API errors, use errors.Is to check which class the error belongs to.
+var (
+	ErrAuth       = errors.New("authentication failed") // API key is unknown, signature or time is incorrect
+	ErrPermission = errors.New("permission denied")     // API key owner has no access to the problem or contest
+	ErrNotFound   = errors.New("not found")             // problem, package, file or method does not exist
+	ErrRateLimit  = errors.New("rate limited")          // requests are too frequent
+	ErrServer     = errors.New("server error")          // Polygon has failed to handle the request
+)
+
+// Error is returned when Polygon API responds with a failure.
+type Error struct {
+	Method  string // API method, e.g. problem.info
+	Status  int    // HTTP status code, failures are often returned with 200 or 400
+	Comment string // Polygon comment, or HTTP status text if response has no comment
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("API request %v failed (HTTP %v): %v", e.Method, e.Status, e.Comment)
+}
+
+// Unwrap returns class of the error, or nil if the error is not classified.
+func (e *Error) Unwrap() error {
+	comment := strings.ToLower(e.Comment)
+
+	switch {
+	case e.Status == http.StatusTooManyRequests || throttled(comment):
+		return ErrRateLimit
+	case e.Status >= 500:
+		return ErrServer
+	case e.Status == http.StatusUnauthorized || containsAny(comment, "signature", "apikey", "api key", "incorrect time", "authenticat"):
+		return ErrAuth
+	case e.Status == http.StatusForbidden || containsAny(comment, "access", "permission", "not allowed", "forbidden"):
+		return ErrPermission
+	case e.Status == http.StatusNotFound || containsAny(comment, "not found", "unknown method", "does not exist", "doesn't exist"):
+		return ErrNotFound
+	default:
+		return nil
+	}
+}
+
+// Temporary returns true if request may succeed when it's repeated later.
+func (e *Error) Temporary() bool {
+	class := e.Unwrap()
+	return class == ErrRateLimit || class == ErrServer
+}
+
+func containsAny(text string, words ...string) bool {
+	for _, word := range words {
+		if strings.Contains(text, word) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,5 +1,9 @@
 package polygon
 
+import (
+	"time"
+)
+
 func UseBaseURL(base string) func(*Client) {
 	return func(cli *Client) {
 		cli.base = base
@@ -11,3 +15,30 @@ func UseHTTPClient(hc httpClient) func(*Client) {
 		cli.cli = hc
 	}
 }
+
+// UseRateLimit limits number of requests per second, requests are spread evenly. Zero disables the limit, requests
+// are not limited by default.
+func UseRateLimit(rps float64) func(*Client) {
+	return func(cli *Client) {
+		cli.limit = &limiter{}
+		if rps > 0 {
+			cli.limit.interval = time.Duration(float64(time.Second) / rps)
+		}
+	}
+}
+
+// UseRetry sets how many times throttled and failed (5xx) requests are retried, and the delay before the first retry,
+// it doubles with each attempt. Failed write requests are not retried. By default, requests are retried 3 times starting with 1 second delay.
+func UseRetry(retries int, backoff time.Duration) func(*Client) {
+	return func(cli *Client) {
+		cli.retries = retries
+		cli.backoff = backoff
+	}
+}
+
+// UseTimeout limits duration of each API call including reading the response, there is no limit by default.
+func UseTimeout(timeout time.Duration) func(*Client) {
+	return func(cli *Client) {
+		cli.timeout = timeout
+	}
+}
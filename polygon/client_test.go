@@ -0,0 +1,767 @@
+package polygon
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// polygonStub is an httptest stand-in for Polygon API, it checks request signature and returns canned results by
+// method name
+type polygonStub struct {
+	t       *testing.T
+	secret  string
+	results map[string]any    // method to result (or func() any producing it), wrapped into OK envelope
+	raw     map[string]string // method to plain text response
+	fail    map[string]string // method to comment of the FAILED envelope
+	mu      sync.Mutex
+	calls   []url.Values
+	methods []string // method of each call
+}
+
+func newPolygonStub(t *testing.T) (*polygonStub, *Client) {
+	stub := &polygonStub{t: t, secret: "secret", results: map[string]any{}, raw: map[string]string{}, fail: map[string]string{}}
+
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+
+	return stub, New("key", stub.secret, UseBaseURL(server.URL+"/api/"))
+}
+
+func (s *polygonStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := strings.TrimPrefix(r.URL.Path, "/api/")
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+		s.t.Errorf("%v: unable to parse form: %v", method, err)
+	}
+
+	params := url.Values{}
+	for k, v := range r.Form {
+		params[k] = v
+	}
+
+	if r.MultipartForm != nil {
+		for k, files := range r.MultipartForm.File {
+			f, _ := files[0].Open()
+			data, _ := io.ReadAll(f)
+			_ = f.Close()
+
+			params.Set(k, string(data))
+		}
+	}
+
+	s.mu.Lock()
+	s.calls = append(s.calls, params)
+	s.methods = append(s.methods, method)
+	s.mu.Unlock()
+
+	if params.Get("apiKey") != "key" {
+		s.t.Errorf("%v: API key is missing", method)
+	}
+
+	sig := params.Get("apiSig")
+	params.Del("apiSig")
+
+	if len(sig) < 6 || sig != signature(sig[:6], method, s.secret, params) {
+		s.t.Errorf("%v: signature %#v is not valid", method, sig)
+	}
+
+	if comment, ok := s.fail[method]; ok {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"status": "FAILED", "comment": comment})
+		return
+	}
+
+	if text, ok := s.raw[method]; ok {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(text))
+		return
+	}
+
+	result, ok := s.results[method]
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"status": "FAILED", "comment": method + ": not found"})
+		return
+	}
+
+	if fn, ok := result.(func() any); ok {
+		result = fn()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"status": "OK", "result": result})
+}
+
+// last returns parameters of the last call
+func (s *polygonStub) last() url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.calls) == 0 {
+		s.t.Fatal("no calls were made")
+	}
+
+	return s.calls[len(s.calls)-1]
+}
+
+func signature(salt, method, secret string, params url.Values) string {
+	hash := sha512.Sum512([]byte(salt + "/" + method + "?" + canonical(params) + "#" + secret))
+	return salt + fmt.Sprintf("%x", hash)
+}
+
+func TestClient_read(t *testing.T) {
+	ctx := context.Background()
+	stub, client := newPolygonStub(t)
+
+	stub.results["problem.info"] = ProblemInfo{InputFile: "stdin", OutputFile: "stdout", TimeLimit: 1000, MemoryLimit: 256}
+	stub.results["problem.statements"] = map[string]Statement{"english": {Name: "A+B", Legend: "Sum two numbers"}}
+	stub.results["problem.statementResources"] = []File{{Name: "pic.png", Length: 10}}
+	stub.results["problem.checker"] = "check.cpp"
+	stub.results["problem.validator"] = "val.cpp"
+	stub.results["problem.interactor"] = ""
+	stub.results["problem.files"] = Files{SourceFiles: []File{{Name: "gen.cpp", SourceType: "cpp.g++17"}}}
+	stub.results["problem.solutions"] = []Solution{{Name: "main.cpp", SourceType: "cpp.g++17", Tag: "MA"}}
+	stub.results["problem.tests"] = []Test{{Index: 1, Manual: true, Input: "1 2\n", UseInStatements: true}, {Index: 2, ScriptLine: "gen 10"}}
+	stub.results["problem.testGroup"] = []TestGroup{{Name: "1", PointsPolicy: "COMPLETE_GROUP", FeedbackPolicy: "ICPC", Dependencies: []string{"0"}}}
+	stub.results["problem.viewTags"] = []string{"math", "easy"}
+	stub.results["problem.viewGeneralDescription"] = "Sum of two numbers"
+	stub.results["problems.list"] = []Problem{{ID: 1, Name: "a-plus-b", AccessType: "OWNER", Revision: 3}}
+	stub.results["contest.problems"] = map[string]Problem{"A": {ID: 1, Name: "a-plus-b"}}
+	stub.raw["problem.script"] = "gen 10 > 2\n"
+	stub.raw["problem.viewFile"] = "int main() {}"
+	stub.raw["problem.viewSolution"] = "int main() { return 0; }"
+	stub.raw["problem.testInput"] = "1 2\n"
+	stub.raw["problem.testAnswer"] = "3\n"
+
+	t.Run("info", func(t *testing.T) {
+		got, err := client.GetProblemInfo(ctx, GetProblemInfoInput{ProblemID: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := (&ProblemInfo{InputFile: "stdin", OutputFile: "stdout", TimeLimit: 1000, MemoryLimit: 256}); !cmp.Equal(want, got) {
+			t.Errorf("problem info does not match:\n%s", cmp.Diff(want, got))
+		}
+
+		if id := stub.last().Get("problemId"); id != "1" {
+			t.Errorf("problemId must be 1, got %#v", id)
+		}
+	})
+
+	t.Run("statements", func(t *testing.T) {
+		got, err := client.ListStatements(ctx, ListStatementsInput{ProblemID: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got["english"].Name != "A+B" {
+			t.Errorf("unexpected statements: %+v", got)
+		}
+
+		resources, err := client.ListStatementResources(ctx, ListStatementResourcesInput{ProblemID: 1})
+		if err != nil || len(resources) != 1 || resources[0].Name != "pic.png" {
+			t.Errorf("unexpected statement resources: %+v (%v)", resources, err)
+		}
+	})
+
+	t.Run("assets", func(t *testing.T) {
+		checker, err := client.GetChecker(ctx, GetCheckerInput{ProblemID: 1})
+		if err != nil || checker != "check.cpp" {
+			t.Errorf("unexpected checker: %#v (%v)", checker, err)
+		}
+
+		validator, err := client.GetValidator(ctx, GetValidatorInput{ProblemID: 1})
+		if err != nil || validator != "val.cpp" {
+			t.Errorf("unexpected validator: %#v (%v)", validator, err)
+		}
+
+		interactor, err := client.GetInteractor(ctx, GetInteractorInput{ProblemID: 1})
+		if err != nil || interactor != "" {
+			t.Errorf("unexpected interactor: %#v (%v)", interactor, err)
+		}
+
+		files, err := client.ListFiles(ctx, ListFilesInput{ProblemID: 1})
+		if err != nil || len(files.SourceFiles) != 1 || files.SourceFiles[0].SourceType != "cpp.g++17" {
+			t.Errorf("unexpected files: %+v (%v)", files, err)
+		}
+
+		solutions, err := client.ListSolutions(ctx, ListSolutionsInput{ProblemID: 1})
+		if err != nil || len(solutions) != 1 || solutions[0].Tag != "MA" {
+			t.Errorf("unexpected solutions: %+v (%v)", solutions, err)
+		}
+
+		file, err := client.ViewFile(ctx, ViewFileInput{ProblemID: 1, Type: "source", Name: "gen.cpp"})
+		if err != nil || string(file) != "int main() {}" {
+			t.Errorf("unexpected file: %#v (%v)", string(file), err)
+		}
+
+		if q := stub.last(); q.Get("type") != "source" || q.Get("name") != "gen.cpp" {
+			t.Errorf("file type and name must be passed, got %v", q)
+		}
+
+		source, err := client.ViewSolution(ctx, ViewSolutionInput{ProblemID: 1, Name: "main.cpp"})
+		if err != nil || string(source) != "int main() { return 0; }" {
+			t.Errorf("unexpected solution: %#v (%v)", string(source), err)
+		}
+	})
+
+	t.Run("tests", func(t *testing.T) {
+		script, err := client.GetScript(ctx, GetScriptInput{ProblemID: 1, Testset: "tests"})
+		if err != nil || script != "gen 10 > 2\n" {
+			t.Errorf("unexpected script: %#v (%v)", script, err)
+		}
+
+		if testset := stub.last().Get("testset"); testset != "tests" {
+			t.Errorf("testset must be passed, got %#v", testset)
+		}
+
+		tests, err := client.ListTests(ctx, ListTestsInput{ProblemID: 1, Testset: "tests", NoInputs: true})
+		if err != nil || len(tests) != 2 || tests[1].ScriptLine != "gen 10" {
+			t.Errorf("unexpected tests: %+v (%v)", tests, err)
+		}
+
+		if v := stub.last().Get("noInputs"); v != "true" {
+			t.Errorf("noInputs must be passed, got %#v", v)
+		}
+
+		input, err := client.GetTestInput(ctx, GetTestFileInput{ProblemID: 1, Testset: "tests", TestIndex: 1})
+		if err != nil || string(input) != "1 2\n" {
+			t.Errorf("unexpected test input: %#v (%v)", string(input), err)
+		}
+
+		if v := stub.last().Get("testIndex"); v != "1" {
+			t.Errorf("testIndex must be passed, got %#v", v)
+		}
+
+		answer, err := client.GetTestAnswer(ctx, GetTestFileInput{ProblemID: 1, Testset: "tests", TestIndex: 1})
+		if err != nil || string(answer) != "3\n" {
+			t.Errorf("unexpected test answer: %#v (%v)", string(answer), err)
+		}
+
+		groups, err := client.ListTestGroups(ctx, ListTestGroupsInput{ProblemID: 1, Testset: "tests"})
+		if err != nil || len(groups) != 1 || groups[0].Dependencies[0] != "0" {
+			t.Errorf("unexpected groups: %+v (%v)", groups, err)
+		}
+
+		if stub.last().Has("group") {
+			t.Error("empty group must not be passed")
+		}
+	})
+
+	t.Run("general", func(t *testing.T) {
+		tags, err := client.ListTags(ctx, ListTagsInput{ProblemID: 1})
+		if err != nil || !cmp.Equal([]string{"math", "easy"}, tags) {
+			t.Errorf("unexpected tags: %v (%v)", tags, err)
+		}
+
+		description, err := client.GetGeneralDescription(ctx, GetGeneralDescriptionInput{ProblemID: 1})
+		if err != nil || description != "Sum of two numbers" {
+			t.Errorf("unexpected description: %#v (%v)", description, err)
+		}
+	})
+
+	t.Run("problems", func(t *testing.T) {
+		problems, err := client.ListProblems(ctx, ListProblemsInput{Owner: "tourist"})
+		if err != nil || len(problems) != 1 || problems[0].AccessType != "OWNER" {
+			t.Errorf("unexpected problems: %+v (%v)", problems, err)
+		}
+
+		if owner := stub.last().Get("owner"); owner != "tourist" {
+			t.Errorf("owner must be passed, got %#v", owner)
+		}
+
+		contest, err := client.ListContestProblems(ctx, ListContestProblemsInput{ContestID: 7})
+		if err != nil || contest["A"].Name != "a-plus-b" {
+			t.Errorf("unexpected contest problems: %+v (%v)", contest, err)
+		}
+
+		if id := stub.last().Get("contestId"); id != "7" {
+			t.Errorf("contestId must be 7, got %#v", id)
+		}
+	})
+}
+
+func TestClient_failed(t *testing.T) {
+	_, client := newPolygonStub(t)
+
+	if _, err := client.GetProblemInfo(context.Background(), GetProblemInfoInput{ProblemID: 1}); err == nil {
+		t.Error("failed envelope must be returned as an error")
+	}
+
+	if _, err := client.GetScript(context.Background(), GetScriptInput{ProblemID: 1, Testset: "tests"}); err == nil {
+		t.Error("failed envelope must be returned as an error for plain text methods too")
+	}
+}
+
+func TestClient_concurrent(t *testing.T) {
+	stub, client := newPolygonStub(t)
+	stub.results["problem.info"] = ProblemInfo{TimeLimit: 1000}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if _, err := client.GetProblemInfo(context.Background(), GetProblemInfoInput{ProblemID: i}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(stub.calls) != 20 {
+		t.Errorf("20 calls must be made, got %v", len(stub.calls))
+	}
+}
+
+func TestClient_retry(t *testing.T) {
+	tests := map[string]func(w http.ResponseWriter){
+		"server error": func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusBadGateway)
+		},
+		"too many requests status": func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		},
+		"too many requests envelope": func(w http.ResponseWriter) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"FAILED","comment":"Too many requests, try again later"}`))
+		},
+		"too many requests envelope with bad request status": func(w http.ResponseWriter) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"FAILED","comment":"Too many requests"}`))
+		},
+	}
+
+	for name, fail := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls atomic.Int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) <= 2 {
+					fail(w)
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"status":"OK","result":{"timeLimit":1000}}`))
+			}))
+
+			t.Cleanup(server.Close)
+
+			client := New("key", "secret", UseBaseURL(server.URL), UseRetry(2, time.Millisecond))
+
+			info, err := client.GetProblemInfo(context.Background(), GetProblemInfoInput{ProblemID: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if info.TimeLimit != 1000 || calls.Load() != 3 {
+				t.Errorf("request must succeed on the third attempt, got %v calls", calls.Load())
+			}
+		})
+	}
+}
+
+func TestClient_retry_exhausted(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	t.Cleanup(server.Close)
+
+	client := New("key", "secret", UseBaseURL(server.URL), UseRetry(3, time.Millisecond))
+
+	if _, err := client.GetProblemInfo(context.Background(), GetProblemInfoInput{ProblemID: 1}); err == nil {
+		t.Error("request must fail when retries are exhausted")
+	}
+
+	if calls.Load() != 4 {
+		t.Errorf("request must be made 4 times, got %v", calls.Load())
+	}
+}
+
+func TestClient_retry_write(t *testing.T) {
+	tests := map[string]struct {
+		status int
+		calls  int32
+	}{
+		"server error":      {status: http.StatusBadGateway, calls: 1},
+		"too many requests": {status: http.StatusTooManyRequests, calls: 2},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls atomic.Int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) == 1 {
+					w.WriteHeader(tc.status)
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"status":"OK"}`))
+			}))
+
+			t.Cleanup(server.Close)
+
+			client := New("key", "secret", UseBaseURL(server.URL), UseRetry(3, time.Millisecond))
+
+			err := client.CommitChanges(context.Background(), CommitChangesInput{ProblemID: 1})
+			if tc.calls == 1 && err == nil {
+				t.Error("write request must fail without retry")
+			}
+
+			if tc.calls > 1 && err != nil {
+				t.Errorf("throttled write request must be retried, got %v", err)
+			}
+
+			if calls.Load() != tc.calls {
+				t.Errorf("request must be made %v times, got %v", tc.calls, calls.Load())
+			}
+		})
+	}
+}
+
+func TestClient_retry_permanent(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status":"FAILED","comment":"problemId: Problem not found"}`))
+	}))
+
+	t.Cleanup(server.Close)
+
+	client := New("key", "secret", UseBaseURL(server.URL), UseRetry(3, time.Millisecond))
+
+	_, err := client.GetProblemInfo(context.Background(), GetProblemInfoInput{ProblemID: 1})
+	if err == nil || !strings.Contains(err.Error(), "Problem not found") {
+		t.Errorf("request must fail with the comment from the envelope, got %v", err)
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("failed request must not be retried, got %v calls", calls.Load())
+	}
+}
+
+func TestClient_errors(t *testing.T) {
+	tests := map[string]struct {
+		status  int
+		body    string
+		comment string
+		class   error
+	}{
+		"signature": {
+			status:  http.StatusBadRequest,
+			body:    `{"status":"FAILED","comment":"apiSig: Incorrect signature"}`,
+			comment: "apiSig: Incorrect signature",
+			class:   ErrAuth,
+		},
+		"permission": {
+			status:  http.StatusOK,
+			body:    `{"status":"FAILED","comment":"problemId: You don't have WRITE access to the problem"}`,
+			comment: "problemId: You don't have WRITE access to the problem",
+			class:   ErrPermission,
+		},
+		"not found": {
+			status:  http.StatusBadRequest,
+			body:    `{"status":"FAILED","comment":"problemId: Problem not found"}`,
+			comment: "problemId: Problem not found",
+			class:   ErrNotFound,
+		},
+		"rate limit": {
+			status:  http.StatusTooManyRequests,
+			comment: "Too Many Requests",
+			class:   ErrRateLimit,
+		},
+		"server": {
+			status:  http.StatusBadGateway,
+			body:    `<html>Bad Gateway</html>`,
+			comment: "Bad Gateway",
+			class:   ErrServer,
+		},
+		"unknown": {
+			status:  http.StatusBadRequest,
+			body:    `{"status":"FAILED","comment":"name: Name is too long"}`,
+			comment: "name: Name is too long",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+
+			t.Cleanup(server.Close)
+
+			client := New("key", "secret", UseBaseURL(server.URL), UseRetry(0, time.Millisecond))
+
+			err := client.SaveTags(context.Background(), SaveTagsInput{ProblemID: 1})
+
+			var perr *Error
+			if !errors.As(err, &perr) {
+				t.Fatalf("request must fail with *Error, got %#v", err)
+			}
+
+			if want := (&Error{Method: "problem.saveTags", Status: tc.status, Comment: tc.comment}); !cmp.Equal(want, perr) {
+				t.Errorf("error does not match:\n%s", cmp.Diff(want, perr))
+			}
+
+			for _, class := range []error{ErrAuth, ErrPermission, ErrNotFound, ErrRateLimit, ErrServer} {
+				if got := errors.Is(err, class); got != (class == tc.class) {
+					t.Errorf("errors.Is(err, %v) must be %v", class, !got)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_DownloadPackage_failed(t *testing.T) {
+	_, client := newPolygonStub(t)
+	_, err := client.DownloadPackage(context.Background(), DownloadPackageInput{ProblemID: 1, PackageID: 2, Type: "linux"})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("failure envelope must be returned as an error, got %v", err)
+	}
+}
+
+func TestClient_rateLimit(t *testing.T) {
+	stub, client := newPolygonStub(t)
+	stub.results["problem.info"] = ProblemInfo{}
+
+	client = New("key", stub.secret, UseBaseURL(client.base), UseRateLimit(50))
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if _, err := client.GetProblemInfo(context.Background(), GetProblemInfoInput{ProblemID: i}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// 6 requests at 50 rps take at least 5 intervals of 20ms
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("requests must be throttled, 6 requests took %v", elapsed)
+	}
+}
+
+func TestClient_timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+
+	t.Cleanup(server.Close)
+
+	client := New("key", "secret", UseBaseURL(server.URL), UseTimeout(50*time.Millisecond))
+
+	start := time.Now()
+
+	if _, err := client.GetProblemInfo(context.Background(), GetProblemInfoInput{ProblemID: 1}); err == nil {
+		t.Error("request must time out")
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("request must be cancelled after timeout, it took %v", elapsed)
+	}
+}
+
+func TestClient_write(t *testing.T) {
+	ctx := context.Background()
+	stub, client := newPolygonStub(t)
+
+	for _, method := range []string{
+		"problem.updateInfo", "problem.saveStatement", "problem.saveStatementResource", "problem.saveFile",
+		"problem.saveSolution", "problem.saveTest", "problem.setChecker", "problem.setValidator",
+		"problem.setInteractor", "problem.saveScript", "problem.enableGroups", "problem.enablePoints",
+		"problem.saveTestGroup", "problem.saveTags", "problem.commitChanges", "problem.buildPackage",
+	} {
+		stub.results[method] = nil
+	}
+
+	stub.results["problem.create"] = Problem{ID: 42, Name: "a-plus-b", AccessType: "OWNER"}
+
+	interactive := false
+	points := 12.5
+
+	tests := []struct {
+		name string
+		call func() error
+		want map[string]string
+	}{
+		{
+			name: "create",
+			call: func() error {
+				problem, err := client.CreateProblem(ctx, CreateProblemInput{Name: "a-plus-b"})
+				if err == nil && problem.ID != 42 {
+					return fmt.Errorf("unexpected problem %+v", problem)
+				}
+
+				return err
+			},
+			want: map[string]string{"name": "a-plus-b"},
+		},
+		{
+			name: "update info",
+			call: func() error {
+				return client.UpdateProblemInfo(ctx, UpdateProblemInfoInput{ProblemID: 42, Interactive: &interactive, TimeLimit: 2000})
+			},
+			want: map[string]string{"problemId": "42", "interactive": "false", "timeLimit": "2000"},
+		},
+		{
+			name: "statement",
+			call: func() error {
+				return client.SaveStatement(ctx, SaveStatementInput{ProblemID: 42, Lang: "english", Name: "A+B", Legend: "Find $a+b$ & print it\n"})
+			},
+			want: map[string]string{"lang": "english", "name": "A+B", "legend": "Find $a+b$ & print it\n"},
+		},
+		{
+			name: "statement resource",
+			call: func() error {
+				return client.SaveStatementResource(ctx, SaveStatementResourceInput{ProblemID: 42, Name: "pic.png", File: []byte{0x89, 'P', 'N', 'G'}})
+			},
+			want: map[string]string{"name": "pic.png", "checkExisting": "false", "file": "\x89PNG"},
+		},
+		{
+			name: "file",
+			call: func() error {
+				return client.SaveFile(ctx, SaveFileInput{ProblemID: 42, Type: "resource", Name: "testlib.h", File: []byte("// testlib"), ForTypes: "cpp.*", Stages: []string{"COMPILE"}, Assets: []string{"VALIDATOR", "CHECKER"}})
+			},
+			want: map[string]string{"type": "resource", "name": "testlib.h", "file": "// testlib", "forTypes": "cpp.*", "stages": "COMPILE", "assets": "VALIDATOR;CHECKER"},
+		},
+		{
+			name: "solution",
+			call: func() error {
+				return client.SaveSolution(ctx, SaveSolutionInput{ProblemID: 42, Name: "main.cpp", File: []byte("int main() {}"), SourceType: "cpp.g++17", Tag: "MA"})
+			},
+			want: map[string]string{"name": "main.cpp", "file": "int main() {}", "sourceType": "cpp.g++17", "tag": "MA"},
+		},
+		{
+			name: "test",
+			call: func() error {
+				return client.SaveTest(ctx, SaveTestInput{ProblemID: 42, Testset: "tests", TestIndex: 3, TestInput: []byte("1 2\n"), TestGroup: "1", TestPoints: &points, TestUseInStatements: true})
+			},
+			want: map[string]string{"testset": "tests", "testIndex": "3", "testInput": "1 2\n", "testGroup": "1", "testPoints": "12.5", "testUseInStatements": "true"},
+		},
+		{
+			name: "checker",
+			call: func() error { return client.SetChecker(ctx, SetCheckerInput{ProblemID: 42, Checker: "std::wcmp.cpp"}) },
+			want: map[string]string{"checker": "std::wcmp.cpp"},
+		},
+		{
+			name: "validator",
+			call: func() error { return client.SetValidator(ctx, SetValidatorInput{ProblemID: 42, Validator: "val.cpp"}) },
+			want: map[string]string{"validator": "val.cpp"},
+		},
+		{
+			name: "interactor",
+			call: func() error {
+				return client.SetInteractor(ctx, SetInteractorInput{ProblemID: 42, Interactor: "interactor.cpp"})
+			},
+			want: map[string]string{"interactor": "interactor.cpp"},
+		},
+		{
+			name: "script",
+			call: func() error {
+				return client.SaveScript(ctx, SaveScriptInput{ProblemID: 42, Testset: "tests", Source: "gen 1 > 2\ngen 2 > 3\n"})
+			},
+			want: map[string]string{"testset": "tests", "source": "gen 1 > 2\ngen 2 > 3\n"},
+		},
+		{
+			name: "groups",
+			call: func() error {
+				return client.EnableGroups(ctx, EnableGroupsInput{ProblemID: 42, Testset: "tests", Enable: true})
+			},
+			want: map[string]string{"testset": "tests", "enable": "true"},
+		},
+		{
+			name: "points",
+			call: func() error { return client.EnablePoints(ctx, EnablePointsInput{ProblemID: 42, Enable: true}) },
+			want: map[string]string{"enable": "true"},
+		},
+		{
+			name: "test group",
+			call: func() error {
+				return client.SaveTestGroup(ctx, SaveTestGroupInput{ProblemID: 42, Testset: "tests", Group: "2", PointsPolicy: "COMPLETE_GROUP", Dependencies: []string{"0", "1"}})
+			},
+			want: map[string]string{"group": "2", "pointsPolicy": "COMPLETE_GROUP", "dependencies": "0,1"},
+		},
+		{
+			name: "tags",
+			call: func() error {
+				return client.SaveTags(ctx, SaveTagsInput{ProblemID: 42, Tags: []string{"math", "easy"}})
+			},
+			want: map[string]string{"tags": "math,easy"},
+		},
+		{
+			name: "commit",
+			call: func() error {
+				return client.CommitChanges(ctx, CommitChangesInput{ProblemID: 42, MinorChanges: true, Message: "import from eolymp"})
+			},
+			want: map[string]string{"minorChanges": "true", "message": "import from eolymp"},
+		},
+		{
+			name: "build",
+			call: func() error { return client.BuildPackage(ctx, BuildPackageInput{ProblemID: 42, Full: true}) },
+			want: map[string]string{"full": "true", "verify": "false"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.call(); err != nil {
+				t.Fatal(err)
+			}
+
+			got := stub.last()
+			for name, want := range test.want {
+				if v := got.Get(name); v != want {
+					t.Errorf("parameter %v must be %#v, got %#v", name, want, v)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_write_failed(t *testing.T) {
+	_, client := newPolygonStub(t)
+
+	if err := client.CommitChanges(context.Background(), CommitChangesInput{ProblemID: 42}); err == nil {
+		t.Error("failed envelope must be returned as an error")
+	}
+}
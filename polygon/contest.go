@@ -0,0 +1,242 @@
+package polygon
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/eolymp/go-problems/connector"
+	"github.com/eolymp/go-problems/locales"
+	"github.com/google/uuid"
+)
+
+// Contest is a Polygon contest imported problem by problem.
+type Contest struct {
+	Name      string           // contest name in the main language, empty if unknown (API does not provide it)
+	Languages []string         // locales of the contest statements
+	Problems  []ContestProblem // problems in order of their indexes
+}
+
+// ContestProblem is a problem of the contest.
+type ContestProblem struct {
+	Index  string            // problem index in the contest, e.g. A or B1
+	Result *connector.Result // imported snapshot, its provenance and warnings
+}
+
+// ImportContest imports all problems of the Polygon contest, each problem is imported the same way as by Import.
+//
+// The link is either a polygon:// link with contestId query parameter, e.g. polygon://api-key:api-secret@/?contestId=123,
+// or a link to a local contest package (file:///path/to/contest.zip) or a directory it's unpacked into. Other query
+// parameters of polygon:// link (mode, types, build etc.) apply to each problem.
+//
+// Polygon API does not expose contest name and statements, so for contests imported through API the name is empty
+// and languages are collected from the problem statements.
+func (p *ProblemLoader) ImportContest(ctx context.Context, link string) (*Contest, error) {
+	origin, err := url.Parse(link)
+	if err != nil {
+		return nil, fmt.Errorf("invalid contest origin: %w", err)
+	}
+
+	switch origin.Scheme {
+	case "polygon":
+		return p.contestByID(ctx, origin)
+	case "file":
+		return p.contestByPath(ctx, origin)
+	default:
+		return nil, fmt.Errorf("invalid contest origin: schema %#v is not supported", origin.Scheme)
+	}
+}
+
+// contestByID imports contest problems through API
+func (p *ProblemLoader) contestByID(ctx context.Context, origin *url.URL) (*Contest, error) {
+	cid, err := strconv.Atoi(origin.Query().Get("contestId"))
+	if err != nil {
+		return nil, errors.New("invalid contest origin: query parameter contestId must be a valid integer")
+	}
+
+	secret, _ := origin.User.Password()
+	poly := New(origin.User.Username(), secret, p.client...)
+
+	problems, err := poly.ListContestProblems(ctx, ListContestProblemsInput{ContestID: cid})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list contest problems: %w", err)
+	}
+
+	var indexes []string
+	for index, problem := range problems {
+		if !problem.Deleted {
+			indexes = append(indexes, index)
+		}
+	}
+
+	slices.SortFunc(indexes, compareIndex)
+
+	contest := &Contest{}
+
+	for _, index := range indexes {
+		problem := problems[index]
+
+		query := origin.Query()
+		query.Del("contestId")
+		query.Set("problemId", strconv.Itoa(problem.ID))
+
+		link := *origin
+		link.RawQuery = query.Encode()
+
+		p.log.Printf("Importing problem %v (%v)", index, problem.Name)
+
+		result, err := p.Import(ctx, link.String())
+		if err != nil {
+			return nil, fmt.Errorf("unable to import problem %v: %w", index, err)
+		}
+
+		for _, statement := range result.Snapshot.GetStatements() {
+			if !slices.Contains(contest.Languages, statement.GetLocale()) {
+				contest.Languages = append(contest.Languages, statement.GetLocale())
+			}
+		}
+
+		contest.Problems = append(contest.Problems, ContestProblem{Index: index, Result: result})
+	}
+
+	return contest, nil
+}
+
+// contestByPath imports problems from the contest package, the package is unpacked unless it's a directory
+func (p *ProblemLoader) contestByPath(ctx context.Context, origin *url.URL) (*Contest, error) {
+	root := origin.Path
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open contest package: %w", err)
+	}
+
+	if !info.IsDir() {
+		workspace := filepath.Join(os.TempDir(), uuid.New().String())
+		if err := os.Mkdir(workspace, 0777); err != nil {
+			return nil, fmt.Errorf("unable to create workspace: %w", err)
+		}
+
+		defer p.cleanup(workspace)
+
+		if err := unzip(root, workspace); err != nil {
+			return nil, fmt.Errorf("unable to unpack contest package: %w", err)
+		}
+
+		root = workspace
+	}
+
+	file, err := os.Open(filepath.Join(root, "contest.xml"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to open contest.xml: %w", err)
+	}
+
+	defer file.Close()
+
+	spec := &ContestSpecification{}
+	if err := xml.NewDecoder(file).Decode(spec); err != nil {
+		return nil, fmt.Errorf("unable to decode contest.xml: %w", err)
+	}
+
+	contest := &Contest{}
+
+	for _, name := range spec.Names {
+		if contest.Name == "" || name.Main {
+			contest.Name = name.Value
+		}
+	}
+
+	languages := make([]string, 0, len(spec.Names)+len(spec.Statements))
+	for _, name := range spec.Names {
+		languages = append(languages, name.Language)
+	}
+
+	for _, statement := range spec.Statements {
+		languages = append(languages, statement.Language)
+	}
+
+	for _, language := range languages {
+		locale, err := locales.Parse(language)
+		if err != nil {
+			p.log.Printf("Contest language %#v is not supported", language)
+			continue
+		}
+
+		if !slices.Contains(contest.Languages, locale) {
+			contest.Languages = append(contest.Languages, locale)
+		}
+	}
+
+	for _, problem := range spec.Problems {
+		dir, ok := contestProblemPath(root, problem)
+		if !ok {
+			return nil, fmt.Errorf("unable to find problem %v in the contest package", problem.Index)
+		}
+
+		// each problem gets its own origin, so provenance tells problems of the same package apart
+		link := *origin
+		link.Fragment = problem.Index
+
+		provenance := connector.NewProvenance("polygon", link.String())
+		provenance.Source = contest.Name
+
+		p.log.Printf("Importing problem %v", problem.Index)
+
+		result, err := p.read(ctx, dir, provenance)
+		if err != nil {
+			return nil, fmt.Errorf("unable to import problem %v: %w", problem.Index, err)
+		}
+
+		contest.Problems = append(contest.Problems, ContestProblem{Index: problem.Index, Result: result})
+	}
+
+	return contest, nil
+}
+
+// contestProblemPath returns directory of the problem in the contest package, problems are put into directories named
+// by their index or short name
+func contestProblemPath(root string, problem ContestSpecificationProblem) (string, bool) {
+	names := []string{strings.ToLower(problem.Index), problem.Index}
+	if link, err := url.Parse(problem.URL); err == nil && link.Path != "" {
+		names = append(names, path.Base(link.Path))
+	}
+
+	for _, name := range names {
+		if name == "" || name != filepath.Base(name) {
+			continue
+		}
+
+		dir := filepath.Join(root, "problems", name)
+		if fileExists(filepath.Join(dir, "problem.xml")) {
+			return dir, true
+		}
+	}
+
+	return "", false
+}
+
+// compareIndex orders problem indexes naturally: A, B, ..., Z, then A1 before A2 and A2 before A10
+func compareIndex(a, b string) int {
+	al, bl := strings.TrimRight(a, "0123456789"), strings.TrimRight(b, "0123456789")
+
+	if len(al) != len(bl) {
+		return len(al) - len(bl)
+	}
+
+	if c := strings.Compare(al, bl); c != 0 {
+		return c
+	}
+
+	an, _ := strconv.Atoi(a[len(al):])
+	bn, _ := strconv.Atoi(b[len(bl):])
+
+	return an - bn
+}
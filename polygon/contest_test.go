@@ -0,0 +1,152 @@
+package polygon
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	. "github.com/eolymp/go-problems/connector/testing"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProblemLoader_ImportContest_api(t *testing.T) {
+	ctx := context.Background()
+	stub, client := newPolygonStub(t)
+
+	stub.results["contest.problems"] = map[string]Problem{
+		"B":  {ID: 2, Name: "b"},
+		"A":  {ID: 1, Name: "a"},
+		"C":  {ID: 3, Name: "c", Deleted: true},
+		"A1": {ID: 4, Name: "a1"},
+	}
+	stub.results["problems.list"] = []Problem{{ID: 1, Name: "a", Revision: 5}, {ID: 2, Name: "b", Revision: 3}, {ID: 4, Name: "a1", Revision: 1}}
+	stub.results["problem.info"] = ProblemInfo{InputFile: "stdin", OutputFile: "stdout", TimeLimit: 1000, MemoryLimit: 256}
+	stub.results["problem.statements"] = map[string]Statement{"english": {Name: "A+B"}, "ukrainian": {Name: "A+B"}}
+	stub.results["problem.statementResources"] = []File{}
+	stub.results["problem.files"] = Files{SourceFiles: []File{{Name: "check.cpp", SourceType: "cpp.g++17"}}}
+	stub.results["problem.checker"] = "check.cpp"
+	stub.results["problem.validator"] = ""
+	stub.results["problem.interactor"] = ""
+	stub.results["problem.solutions"] = []Solution{}
+	stub.results["problem.tests"] = []Test{{Index: 1, Manual: true}}
+	stub.results["problem.viewTags"] = []string{}
+	stub.raw["problem.viewFile"] = "int main() {}"
+	stub.raw["problem.testInput"] = "1 2\n"
+	stub.raw["problem.testAnswer"] = "3\n"
+
+	loader := NewProblemLoader(MockUploader(), MockLogger(t), UsePolygonOptions(UseBaseURL(client.base)))
+
+	link := url.URL{Scheme: "polygon", User: url.UserPassword("key", "secret"), Path: "/", RawQuery: "contestId=7&mode=api"}
+
+	contest, err := loader.ImportContest(ctx, link.String())
+	if err != nil {
+		t.Fatal("Contest import has failed:", err)
+	}
+
+	if call := stub.calls[0]; stub.methods[0] != "contest.problems" || call.Get("contestId") != "7" {
+		t.Errorf("Contest problems must be listed first, got %v %v", stub.methods[0], call)
+	}
+
+	var got []string
+	for _, problem := range contest.Problems {
+		got = append(got, problem.Index+":"+problem.Result.Provenance.ProblemID)
+	}
+
+	if want := []string{"A:1", "A1:4", "B:2"}; !cmp.Equal(want, got) {
+		t.Errorf("Problems do not match:\n%s", cmp.Diff(want, got))
+	}
+
+	languages := slices.Clone(contest.Languages)
+	slices.Sort(languages)
+
+	if want := []string{"en", "uk"}; !cmp.Equal(want, languages) {
+		t.Errorf("Languages do not match:\n%s", cmp.Diff(want, languages))
+	}
+
+	if contest.Name != "" {
+		t.Errorf("Contest name is not available through API, got %#v", contest.Name)
+	}
+}
+
+func TestProblemLoader_ImportContest_package(t *testing.T) {
+	ctx := context.Background()
+	loader := NewProblemLoader(MockUploader(), MockLogger(t))
+
+	root := t.TempDir()
+
+	// problems are found by index or by short name from the problem link
+	for dir, src := range map[string]string{"a": ".testdata/02-statements", "custom-limit": ".testdata/08-custom-limit"} {
+		if err := os.CopyFS(filepath.Join(root, "problems", dir), os.DirFS(src)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	spec := `<?xml version="1.0" encoding="utf-8" standalone="no"?>
+<contest url="https://polygon.codeforces.com/c/7/owner/round">
+  <names>
+    <name language="ukrainian" value="Раунд"/>
+    <name language="english" main="true" value="Round"/>
+  </names>
+  <statements>
+    <statement language="english" path="statements/english/statements.pdf" type="application/pdf"/>
+  </statements>
+  <problems>
+    <problem index="A" url="https://polygon.codeforces.com/p/owner/sum"/>
+    <problem index="B" url="https://polygon.codeforces.com/p/owner/custom-limit"/>
+  </problems>
+</contest>`
+
+	if err := os.WriteFile(filepath.Join(root, "contest.xml"), []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "contest.zip")
+	if err := zipDir(root, archive); err != nil {
+		t.Fatal(err)
+	}
+
+	link := url.URL{Scheme: "file", Path: archive}
+
+	contest, err := loader.ImportContest(ctx, link.String())
+	if err != nil {
+		t.Fatal("Contest import has failed:", err)
+	}
+
+	if contest.Name != "Round" {
+		t.Errorf("Contest name must be taken in the main language, got %#v", contest.Name)
+	}
+
+	if want := []string{"uk", "en"}; !cmp.Equal(want, contest.Languages) {
+		t.Errorf("Languages do not match:\n%s", cmp.Diff(want, contest.Languages))
+	}
+
+	var got []string
+	for _, problem := range contest.Problems {
+		got = append(got, problem.Index+":"+problem.Result.Provenance.Name+":"+problem.Result.Provenance.Source+":"+problem.Result.Provenance.Origin)
+	}
+
+	want := []string{
+		"A:train-14-02-21-0:Round:file://" + archive + "#A",
+		"B:" + contest.Problems[1].Result.Provenance.Name + ":Round:file://" + archive + "#B",
+	}
+
+	if !cmp.Equal(want, got) {
+		t.Errorf("Problems do not match:\n%s", cmp.Diff(want, got))
+	}
+
+	if len(contest.Problems[1].Result.Snapshot.GetTests()) == 0 {
+		t.Error("Problem B must have tests")
+	}
+}
+
+func TestCompareIndex(t *testing.T) {
+	got := []string{"B", "A10", "AA", "A2", "A", "Z", "A1"}
+	slices.SortFunc(got, compareIndex)
+
+	if want := []string{"A", "A1", "A2", "A10", "B", "Z", "AA"}; !cmp.Equal(want, got) {
+		t.Errorf("Indexes do not match:\n%s", cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,715 @@
+package polygon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/eolymp/go-problems/bundle"
+	"github.com/eolymp/go-problems/connector"
+	"github.com/eolymp/go-problems/locales"
+	"github.com/eolymp/go-problems/runtimes"
+	"github.com/eolymp/go-problems/topics"
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	executorpb "github.com/eolymp/go-sdk/eolymp/executor"
+)
+
+// sectionFinder finds statement sections created by ProblemLoader.statements, e.g. \InputFile
+var sectionFinder = regexp.MustCompile(`(?m)^[ \t]*\\(InputFile|Interaction|OutputFile|Note|Scoring)\b[ \t]*\n?`)
+
+// solutionTypes maps solution types to Polygon tags, it's the reverse of ProblemLoader.solutions
+var solutionTypes = map[atlaspb.Solution_Type]string{
+	atlaspb.Solution_CORRECT:             "OK",
+	atlaspb.Solution_INCORRECT:           "RJ",
+	atlaspb.Solution_WRONG_ANSWER:        "WA",
+	atlaspb.Solution_TIMEOUT:             "TL",
+	atlaspb.Solution_TIMEOUT_OR_ACCEPTED: "TO",
+	atlaspb.Solution_OVERFLOW:            "ML",
+	atlaspb.Solution_FAILURE:             "RE",
+	atlaspb.Solution_DONT_RUN:            "TM",
+}
+
+// ProblemExporter recreates snapshots in Polygon problems through API, it's the reverse of ProblemLoader.
+//
+// Statements (with images), editorials, checker, validator, interactor, solutions, generators, tests, groups and tags
+// are uploaded. Answers are not uploaded, Polygon generates them using the main solution. Changes are made one by one,
+// since Polygon does not handle concurrent changes of the same problem well, and committed at the end.
+type ProblemExporter struct {
+	poly     *Client
+	log      connector.Logger
+	registry *runtimes.Registry
+	topics   *topics.Mapper
+	fetch    bundle.Fetcher
+}
+
+// UseExportRuntimes sets registry used to map Eolymp runtimes to Polygon source types, runtimes.Default() is used by
+// default.
+func UseExportRuntimes(registry *runtimes.Registry) func(*ProblemExporter) {
+	return func(e *ProblemExporter) {
+		e.registry = registry
+	}
+}
+
+// UseExportTopics sets mapping used to convert Eolymp topics to Polygon tags, topics.Default() is used by default.
+func UseExportTopics(mapper *topics.Mapper) func(*ProblemExporter) {
+	return func(e *ProblemExporter) {
+		e.topics = mapper
+	}
+}
+
+// UseExportFetcher sets how test inputs, images and extra files referenced from the snapshot are downloaded, they are
+// downloaded over HTTP by default.
+func UseExportFetcher(fetch bundle.Fetcher) func(*ProblemExporter) {
+	return func(e *ProblemExporter) {
+		e.fetch = fetch
+	}
+}
+
+func NewProblemExporter(poly *Client, log connector.Logger, opts ...func(*ProblemExporter)) *ProblemExporter {
+	e := &ProblemExporter{
+		poly:     poly,
+		log:      log,
+		registry: runtimes.Default(),
+		topics:   topics.Default(),
+		fetch:    bundle.HTTPFetcher{},
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Export uploads snapshot into the Polygon problem and commits the changes. Parts of the snapshot which can't be
+// represented in Polygon are skipped and reported as warnings.
+func (e *ProblemExporter) Export(ctx context.Context, problem int, snap *atlaspb.Snapshot) ([]connector.Warning, error) {
+	x := &export{ProblemExporter: e, problem: problem, images: map[string]string{}, resources: map[string]*resource{}}
+
+	steps := []struct {
+		name string
+		fn   func(context.Context, *atlaspb.Snapshot) error
+	}{
+		{"problem info", x.info},
+		{"statements", x.statements},
+		{"checker", x.checker},
+		{"validator", x.validator},
+		{"interactor", x.interactor},
+		{"solutions", x.solutions},
+		{"generators", x.generators},
+		{"resource files", x.files},
+		{"tests", x.tests},
+		{"tags", x.tags},
+	}
+
+	for _, step := range steps {
+		if err := step.fn(ctx, snap); err != nil {
+			return x.warnings, fmt.Errorf("unable to export %v: %w", step.name, err)
+		}
+	}
+
+	if err := e.poly.CommitChanges(ctx, CommitChangesInput{ProblemID: problem, Message: "Exported from Eolymp"}); err != nil {
+		return x.warnings, fmt.Errorf("unable to commit changes: %w", err)
+	}
+
+	e.log.Printf("Problem %v is exported", problem)
+
+	return x.warnings, nil
+}
+
+// resource is an extra file shared by checker, validator, interactor or solution
+type resource struct {
+	link   string
+	assets []string
+}
+
+// export holds state of a single export
+type export struct {
+	*ProblemExporter
+	problem   int
+	warnings  []connector.Warning
+	images    map[string]string    // image link to statement resource name
+	resources map[string]*resource // resource file name to its link and assets
+}
+
+func (x *export) warn(kind, path, format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+
+	x.log.Errorf("%v: %v", path, message)
+	x.warnings = append(x.warnings, connector.Warning{Kind: kind, Path: path, Message: message})
+}
+
+// download reads file referenced from the snapshot
+func (x *export) download(ctx context.Context, link string) ([]byte, error) {
+	body, err := x.fetch.Fetch(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+
+	defer body.Close()
+
+	return io.ReadAll(body)
+}
+
+// source returns Polygon source type and file extension for the runtime
+func (x *export) source(runtime string) (string, string, bool) {
+	kind, ok := x.registry.Reverse("polygon", runtime)
+	if !ok {
+		return "", "", false
+	}
+
+	lang, _ := x.registry.Language(runtimes.ParseRuntime(runtime).Language)
+
+	return kind, lang.Extension(), true
+}
+
+// program saves source file and its extra files, it returns name of the saved file
+func (x *export) program(ctx context.Context, name, runtime, source string, files []*executorpb.File, asset string) (string, error) {
+	kind, ext, ok := x.source(runtime)
+	if !ok {
+		return "", fmt.Errorf("runtime %#v is not mapped to Polygon source type", runtime)
+	}
+
+	if ext != "" {
+		name += "." + ext
+	}
+
+	if err := x.poly.SaveFile(ctx, SaveFileInput{ProblemID: x.problem, Type: "source", Name: name, File: []byte(source), SourceType: kind}); err != nil {
+		return "", err
+	}
+
+	x.log.Printf("Source file %#v is saved as %v", name, kind)
+
+	for _, file := range files {
+		x.resource(file, asset)
+	}
+
+	return name, nil
+}
+
+// resource remembers extra file, they are uploaded once with all the assets they are used by
+func (x *export) resource(file *executorpb.File, asset string) {
+	name := path.Base(file.GetPath())
+	if name == "testlib.h" { // provided by Polygon
+		return
+	}
+
+	res, ok := x.resources[name]
+	if !ok {
+		res = &resource{link: file.GetSourceUrl()}
+		x.resources[name] = res
+	}
+
+	if asset != "" && !slices.Contains(res.assets, asset) {
+		res.assets = append(res.assets, asset)
+	}
+}
+
+func (x *export) info(ctx context.Context, snap *atlaspb.Snapshot) error {
+	var timeLimit, memoryLimit uint64
+	var differ bool
+	for i, testset := range snap.GetTestsets() {
+		limit := uint64(testset.GetCpuLimit())
+		if limit == 0 {
+			limit = uint64(testset.GetTimeLimit())
+		}
+
+		differ = differ || i > 0 && (limit != timeLimit || testset.GetMemoryLimit() != memoryLimit)
+		timeLimit = max(timeLimit, limit)
+		memoryLimit = max(memoryLimit, testset.GetMemoryLimit())
+	}
+
+	if differ {
+		x.warn("limits-differ", "", "testsets have different limits, Polygon supports only one, the highest is used")
+	}
+
+	interactive := snap.GetInteractor().GetType() == executorpb.Interactor_PROGRAM
+
+	return x.poly.UpdateProblemInfo(ctx, UpdateProblemInfoInput{
+		ProblemID:   x.problem,
+		Interactive: &interactive,
+		TimeLimit:   int(timeLimit),
+		MemoryLimit: int(memoryLimit / 1024 / 1024),
+	})
+}
+
+func (x *export) statements(ctx context.Context, snap *atlaspb.Snapshot) error {
+	tutorials := map[string]string{}
+	for _, editorial := range snap.GetEditorials() {
+		latex := editorial.GetContent().GetLatex()
+		if latex == "" {
+			x.warn("unsupported-content", "editorials/"+editorial.GetLocale(), "only LaTeX editorials can be exported")
+			continue
+		}
+
+		tutorials[editorial.GetLocale()] = latex
+	}
+
+	for _, statement := range snap.GetStatements() {
+		where := "statements/" + statement.GetLocale()
+
+		latex := statement.GetContent().GetLatex()
+		if latex == "" {
+			x.warn("unsupported-content", where, "only LaTeX statements can be exported")
+			continue
+		}
+
+		lang := strings.ToLower(locales.Name(statement.GetLocale()))
+		if lang == "" {
+			x.warn("unsupported-language", where, "locale %#v is not known", statement.GetLocale())
+			continue
+		}
+
+		sections := split(x.saveImages(ctx, where, latex))
+
+		err := x.poly.SaveStatement(ctx, SaveStatementInput{
+			ProblemID:   x.problem,
+			Lang:        lang,
+			Encoding:    "UTF-8",
+			Name:        statement.GetTitle(),
+			Legend:      sections[""],
+			Input:       sections["InputFile"],
+			Interaction: sections["Interaction"],
+			Output:      sections["OutputFile"],
+			Notes:       sections["Note"],
+			Scoring:     sections["Scoring"],
+			Tutorial:    x.saveImages(ctx, where, tutorials[statement.GetLocale()]),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		x.log.Printf("Statement in %v is saved", lang)
+	}
+
+	return nil
+}
+
+// split breaks statement into sections by their commands, text before the first command is returned as legend (with
+// empty key)
+func split(latex string) map[string]string {
+	sections := map[string]string{}
+
+	name, start := "", 0
+	for _, match := range sectionFinder.FindAllStringSubmatchIndex(latex, -1) {
+		sections[name] = strings.TrimSpace(latex[start:match[0]])
+		name, start = latex[match[2]:match[3]], match[1]
+	}
+
+	sections[name] = strings.TrimSpace(latex[start:])
+
+	return sections
+}
+
+// saveImages saves images referenced from the text as statement resources and replaces links with resource names
+func (x *export) saveImages(ctx context.Context, where, text string) string {
+	for _, image := range imageFinder.FindAllStringSubmatch(text, -1) {
+		full, prefix, link, suffix := image[0], image[1], image[2], image[3]
+
+		if !strings.HasPrefix(link, "http://") && !strings.HasPrefix(link, "https://") {
+			continue
+		}
+
+		name, ok := x.images[link]
+		if !ok {
+			data, err := x.download(ctx, link)
+			if err != nil {
+				x.warn("missing-resource", where, "unable to download image %#v: %v", link, err)
+				continue
+			}
+
+			name = imageName(link)
+			for _, used := range x.images {
+				if used == name {
+					name = fmt.Sprintf("%d-%v", len(x.images), name)
+					break
+				}
+			}
+
+			if err := x.poly.SaveStatementResource(ctx, SaveStatementResourceInput{ProblemID: x.problem, Name: name, File: data}); err != nil {
+				x.warn("missing-resource", where, "unable to save image %#v: %v", link, err)
+				continue
+			}
+
+			x.images[link] = name
+		}
+
+		text = strings.ReplaceAll(text, full, prefix+name+suffix)
+	}
+
+	return text
+}
+
+// imageName picks statement resource name from the image link
+func imageName(link string) string {
+	if u, err := url.Parse(link); err == nil {
+		if name := path.Base(u.Path); name != "/" && name != "." {
+			return name
+		}
+	}
+
+	return "image"
+}
+
+func (x *export) checker(ctx context.Context, snap *atlaspb.Snapshot) error {
+	checker := snap.GetChecker()
+
+	var name string
+	switch checker.GetType() {
+	case executorpb.Checker_NONE:
+		return nil
+	case executorpb.Checker_LINES:
+		name = "std::lcmp.cpp"
+	case executorpb.Checker_TOKENS:
+		switch precision := checker.GetPrecision(); {
+		case precision <= 0:
+			name = "std::wcmp.cpp"
+		case precision <= 4:
+			name = "std::rcmp4.cpp"
+		case precision <= 6:
+			name = "std::rcmp6.cpp"
+		default:
+			name = "std::rcmp9.cpp"
+		}
+
+		if p := checker.GetPrecision(); p != 0 && p != 4 && p != 6 && p != 9 {
+			x.warn("checker-approximated", "", "there is no standard checker with precision %v, %v is used", p, name)
+		}
+
+		if !checker.GetCaseSensitive() {
+			x.warn("checker-approximated", "", "there is no case-insensitive standard checker, %v is used", name)
+		}
+	case executorpb.Checker_PROGRAM:
+		source, err := x.program(ctx, "check", checker.GetRuntime(), checker.GetSource(), checker.GetFiles(), "CHECKER")
+		if err != nil {
+			return err
+		}
+
+		name = source
+	default:
+		return fmt.Errorf("checker type %v is not supported", checker.GetType())
+	}
+
+	return x.poly.SetChecker(ctx, SetCheckerInput{ProblemID: x.problem, Checker: name})
+}
+
+func (x *export) validator(ctx context.Context, snap *atlaspb.Snapshot) error {
+	validator := snap.GetValidator()
+	if validator.GetSource() == "" {
+		return nil
+	}
+
+	name, err := x.program(ctx, "validator", validator.GetRuntime(), validator.GetSource(), validator.GetFiles(), "VALIDATOR")
+	if err != nil {
+		return err
+	}
+
+	return x.poly.SetValidator(ctx, SetValidatorInput{ProblemID: x.problem, Validator: name})
+}
+
+func (x *export) interactor(ctx context.Context, snap *atlaspb.Snapshot) error {
+	interactor := snap.GetInteractor()
+	if interactor.GetType() != executorpb.Interactor_PROGRAM {
+		return nil
+	}
+
+	name, err := x.program(ctx, "interactor", interactor.GetRuntime(), interactor.GetSource(), interactor.GetFiles(), "INTERACTOR")
+	if err != nil {
+		return err
+	}
+
+	return x.poly.SetInteractor(ctx, SetInteractorInput{ProblemID: x.problem, Interactor: name})
+}
+
+// solutions saves solutions, the one used as "solution" script (or the first correct one) becomes the main solution
+func (x *export) solutions(ctx context.Context, snap *atlaspb.Snapshot) error {
+	main := -1
+	for _, script := range snap.GetScripts() {
+		if script.GetName() != "solution" {
+			continue
+		}
+
+		main = slices.IndexFunc(snap.GetSolutions(), func(s *atlaspb.Solution) bool {
+			return s.GetType() == atlaspb.Solution_CORRECT && s.GetSource() == script.GetSource()
+		})
+
+		for _, file := range script.GetFiles() {
+			x.resource(file, "SOLUTION")
+		}
+	}
+
+	if main < 0 {
+		main = slices.IndexFunc(snap.GetSolutions(), func(s *atlaspb.Solution) bool { return s.GetType() == atlaspb.Solution_CORRECT })
+	}
+
+	if main < 0 {
+		x.warn("missing-solution", "", "there is no correct solution, Polygon requires main solution to generate answers")
+	}
+
+	for i, solution := range snap.GetSolutions() {
+		name := solution.GetName()
+		where := "solutions/" + name
+
+		tag, ok := solutionTypes[solution.GetType()]
+		if i == main {
+			tag = "MA"
+		}
+
+		if !ok {
+			x.warn("unmapped-solution", where, "solution type %v has no Polygon tag", solution.GetType())
+			continue
+		}
+
+		if solution.GetSource() == "" {
+			x.warn("unmapped-solution", where, "solution source is not available")
+			continue
+		}
+
+		kind, ext, ok := x.source(solution.GetRuntime())
+		if !ok {
+			x.warn("unmapped-runtime", where, "runtime %#v is not mapped to Polygon source type", solution.GetRuntime())
+			continue
+		}
+
+		if path.Ext(name) == "" && ext != "" {
+			name += "." + ext
+		}
+
+		if err := x.poly.SaveSolution(ctx, SaveSolutionInput{ProblemID: x.problem, Name: name, File: []byte(solution.GetSource()), SourceType: kind, Tag: tag}); err != nil {
+			return err
+		}
+
+		x.log.Printf("Solution %#v is saved with tag %v", name, tag)
+	}
+
+	return nil
+}
+
+// generators saves scripts used to generate test inputs, "solution" script is saved as the main solution
+func (x *export) generators(ctx context.Context, snap *atlaspb.Snapshot) error {
+	for _, script := range snap.GetScripts() {
+		if script.GetName() == "solution" {
+			continue
+		}
+
+		if _, err := x.program(ctx, script.GetName(), script.GetRuntime(), script.GetSource(), script.GetFiles(), ""); err != nil {
+			return fmt.Errorf("script %#v: %w", script.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+// files uploads extra files of checker, validator, interactor and scripts as resource files
+func (x *export) files(ctx context.Context, snap *atlaspb.Snapshot) error {
+	var names []string
+	for name := range x.resources {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		res := x.resources[name]
+
+		data, err := x.download(ctx, res.link)
+		if err != nil {
+			x.warn("missing-resource", "files/"+name, "unable to download file: %v", err)
+			continue
+		}
+
+		in := SaveFileInput{ProblemID: x.problem, Type: "resource", Name: name, File: data}
+		if len(res.assets) > 0 {
+			in.Stages = []string{"COMPILE", "RUN"}
+			in.Assets = res.assets
+		}
+
+		if err := x.poly.SaveFile(ctx, in); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tests saves tests into "tests" testset, generated tests are saved as script lines. Testsets become groups named by
+// their index, so the problem is imported back with the same testsets.
+func (x *export) tests(ctx context.Context, snap *atlaspb.Snapshot) error {
+	testsets := slices.Clone(snap.GetTestsets())
+	slices.SortFunc(testsets, func(a, b *atlaspb.Testset) int { return int(a.GetIndex()) - int(b.GetIndex()) })
+
+	groups := len(testsets) > 1
+
+	points := slices.ContainsFunc(snap.GetTests(), func(t *atlaspb.Test) bool { return t.GetScore() != 0 })
+
+	if points {
+		if err := x.poly.EnablePoints(ctx, EnablePointsInput{ProblemID: x.problem, Enable: true}); err != nil {
+			return err
+		}
+	}
+
+	if groups {
+		if err := x.poly.EnableGroups(ctx, EnableGroupsInput{ProblemID: x.problem, Testset: "tests", Enable: true}); err != nil {
+			return err
+		}
+	}
+
+	var script []string
+
+	index := 0
+	for _, testset := range testsets {
+		group := ""
+		if groups {
+			group = fmt.Sprint(testset.GetIndex())
+		}
+
+		var tests []*atlaspb.Test
+		for _, test := range snap.GetTests() {
+			if test.GetTestsetId() == testset.GetId() {
+				tests = append(tests, test)
+			}
+		}
+
+		slices.SortFunc(tests, func(a, b *atlaspb.Test) int { return int(a.GetIndex()) - int(b.GetIndex()) })
+
+		for _, test := range tests {
+			where := fmt.Sprintf("tests/%v/%v", testset.GetIndex(), test.GetIndex())
+
+			if gen := test.GetInputGenerator(); gen != nil {
+				index++
+				script = append(script, fmt.Sprintf("%v > %v", strings.Join(append([]string{gen.GetScriptName()}, gen.GetArguments()...), " "), index))
+
+				// tests from the script can't have points or be examples, it's only possible for tests saved one by one
+				if test.GetExample() {
+					x.warn("generated-example", where, "generated test can't be marked as an example, mark it in Polygon")
+				}
+
+				if points && test.GetScore() != 0 {
+					x.warn("generated-points", where, "points of generated test can't be saved, set them in Polygon")
+				}
+
+				continue
+			}
+
+			if test.GetInputUrl() == "" {
+				x.warn("missing-test", where, "test input is not available")
+				continue
+			}
+
+			input, err := x.download(ctx, test.GetInputUrl())
+			if err != nil {
+				return fmt.Errorf("unable to download input of test %v: %w", where, err)
+			}
+
+			in := SaveTestInput{
+				ProblemID:           x.problem,
+				Testset:             "tests",
+				TestIndex:           index + 1,
+				TestInput:           input,
+				TestGroup:           group,
+				TestUseInStatements: test.GetExample(),
+			}
+
+			if points {
+				score := float64(test.GetScore())
+				in.TestPoints = &score
+			}
+
+			if test.GetExample() && test.GetExampleInputUrl() != "" {
+				data, err := x.download(ctx, test.GetExampleInputUrl())
+				if err != nil {
+					return fmt.Errorf("unable to download example input of test %v: %w", where, err)
+				}
+
+				in.TestInputForStatements = string(data)
+			}
+
+			if test.GetExample() && test.GetExampleAnswerUrl() != "" {
+				data, err := x.download(ctx, test.GetExampleAnswerUrl())
+				if err != nil {
+					return fmt.Errorf("unable to download example answer of test %v: %w", where, err)
+				}
+
+				in.TestOutputForStatements = string(data)
+			}
+
+			if err := x.poly.SaveTest(ctx, in); err != nil {
+				return err
+			}
+
+			index++
+		}
+	}
+
+	if len(script) > 0 {
+		if err := x.poly.SaveScript(ctx, SaveScriptInput{ProblemID: x.problem, Testset: "tests", Source: strings.Join(script, "\n") + "\n"}); err != nil {
+			return err
+		}
+	}
+
+	x.log.Printf("Saved %v tests, %v of them are generated", index, len(script))
+
+	if !groups {
+		return nil
+	}
+
+	for _, testset := range testsets {
+		in := SaveTestGroupInput{
+			ProblemID:      x.problem,
+			Testset:        "tests",
+			Group:          fmt.Sprint(testset.GetIndex()),
+			PointsPolicy:   "EACH_TEST",
+			FeedbackPolicy: "COMPLETE",
+			Dependencies:   []string{},
+		}
+
+		if testset.GetScoringMode() == atlaspb.ScoringMode_ALL {
+			in.PointsPolicy = "COMPLETE_GROUP"
+		}
+
+		if testset.GetFeedbackPolicy() != atlaspb.FeedbackPolicy_COMPLETE {
+			in.FeedbackPolicy = "ICPC"
+		}
+
+		for _, dep := range testset.GetDependencies() {
+			in.Dependencies = append(in.Dependencies, fmt.Sprint(dep))
+		}
+
+		if err := x.poly.SaveTestGroup(ctx, in); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tags saves topics as tags, the first tag of each topic is used
+func (x *export) tags(ctx context.Context, snap *atlaspb.Snapshot) error {
+	var tags []string
+	for _, id := range snap.GetProblem().GetTopics() {
+		topic, ok := x.topics.Topic(id)
+		if !ok || len(topic.Tags) == 0 {
+			x.warn("unmapped-topic", "", "topic %#v has no tag", id)
+			continue
+		}
+
+		tags = append(tags, topic.Tags[0])
+	}
+
+	// testsets scored by the worst test are imported from problems tagged block_min
+	if slices.ContainsFunc(snap.GetTestsets(), func(t *atlaspb.Testset) bool { return t.GetScoringMode() == atlaspb.ScoringMode_WORST }) {
+		tags = append(tags, "block_min")
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	return x.poly.SaveTags(ctx, SaveTagsInput{ProblemID: x.problem, Tags: tags})
+}
@@ -0,0 +1,210 @@
+package polygon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	. "github.com/eolymp/go-problems/connector/testing"
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	ecmpb "github.com/eolymp/go-sdk/eolymp/ecm"
+	executorpb "github.com/eolymp/go-sdk/eolymp/executor"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProblemExporter_Export(t *testing.T) {
+	ctx := context.Background()
+	stub, client := newPolygonStub(t)
+
+	for _, method := range []string{
+		"problem.updateInfo", "problem.saveStatement", "problem.saveStatementResource", "problem.saveFile",
+		"problem.setChecker", "problem.setValidator", "problem.saveSolution", "problem.enablePoints",
+		"problem.enableGroups", "problem.saveTest", "problem.saveScript", "problem.saveTestGroup", "problem.saveTags",
+		"problem.commitChanges",
+	} {
+		stub.results[method] = nil
+	}
+
+	assets := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sample.in":
+			_, _ = w.Write([]byte("1 2\n"))
+		case "/sample.out":
+			_, _ = w.Write([]byte("3\n"))
+		case "/big.in":
+			_, _ = w.Write([]byte("1000 2000\n"))
+		case "/pic.png":
+			_, _ = w.Write([]byte("PNG"))
+		case "/lib.h":
+			_, _ = w.Write([]byte("#pragma once\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	defer assets.Close()
+
+	solution := "int main() { return 0; }"
+
+	snap := &atlaspb.Snapshot{
+		Problem: &atlaspb.Problem{Topics: []string{"3hr591p5lh7a9c5k9bg8kpvctg"}},
+		Checker: &atlaspb.Checker{
+			Type:    executorpb.Checker_PROGRAM,
+			Runtime: "cpp:17-gnu10",
+			Source:  "// checker",
+			Files: []*executorpb.File{
+				{Path: "testlib.h", SourceUrl: assets.URL + "/testlib.h"},
+				{Path: "lib.h", SourceUrl: assets.URL + "/lib.h"},
+			},
+		},
+		Validator: &atlaspb.Validator{Runtime: "cpp:17-gnu10", Source: "// validator"},
+		Statements: []*atlaspb.Statement{{
+			Locale:  "uk",
+			Title:   "A+B",
+			Content: &ecmpb.Content{Value: &ecmpb.Content_Latex{Latex: "Add numbers \\includegraphics{" + assets.URL + "/pic.png}\n\n\\InputFile\n\nTwo numbers\n\n\\OutputFile\n\nTheir sum"}},
+		}},
+		Editorials: []*atlaspb.Editorial{{Locale: "uk", Content: &ecmpb.Content{Value: &ecmpb.Content_Latex{Latex: "Just add them"}}}},
+		Solutions: []*atlaspb.Solution{
+			{Name: "slow.py", Runtime: "python:3.12-python", Source: "print(3)", Type: atlaspb.Solution_TIMEOUT},
+			{Name: "main", Runtime: "cpp:17-gnu10", Source: solution, Type: atlaspb.Solution_CORRECT},
+			{Name: "wa.cpp", Runtime: "cpp:17-gnu10", Source: "// wa", Type: atlaspb.Solution_OVERFLOW_OR_ACCEPTED},
+		},
+		Scripts: []*atlaspb.Script{
+			{Name: "solution", Runtime: "cpp:17-gnu10", Source: solution},
+			{Name: "gen", Runtime: "cpp:17-gnu10", Source: "// gen"},
+		},
+		Testsets: []*atlaspb.Testset{
+			{Id: "b", Index: 1, CpuLimit: 2000, MemoryLimit: 268435456, ScoringMode: atlaspb.ScoringMode_ALL, FeedbackPolicy: atlaspb.FeedbackPolicy_ICPC, Dependencies: []uint32{0}},
+			{Id: "a", Index: 0, CpuLimit: 1000, MemoryLimit: 268435456, ScoringMode: atlaspb.ScoringMode_EACH},
+		},
+		Tests: []*atlaspb.Test{
+			{TestsetId: "b", Index: 2, Score: 60, Input: &atlaspb.Test_InputUrl{InputUrl: assets.URL + "/big.in"}},
+			{TestsetId: "b", Index: 1, Input: &atlaspb.Test_InputGenerator{InputGenerator: &atlaspb.Test_Generator{ScriptName: "gen", Arguments: []string{"10", "20"}}}},
+			{TestsetId: "a", Index: 1, Example: true, Input: &atlaspb.Test_InputUrl{InputUrl: assets.URL + "/sample.in"}, ExampleAnswerUrl: assets.URL + "/sample.out"},
+		},
+	}
+
+	exporter := NewProblemExporter(client, MockLogger(t))
+
+	warnings, err := exporter.Export(ctx, 7, snap)
+	if err != nil {
+		t.Fatal("Problem export has failed:", err)
+	}
+
+	calls := func(method string) (list []url.Values) {
+		for i, m := range stub.methods {
+			if m == method {
+				list = append(list, stub.calls[i])
+			}
+		}
+
+		return
+	}
+
+	if info := calls("problem.updateInfo"); len(info) != 1 || info[0].Get("timeLimit") != "2000" || info[0].Get("memoryLimit") != "256" || info[0].Get("interactive") != "false" {
+		t.Errorf("Problem info must have the highest limits, got %v", info)
+	}
+
+	statement := calls("problem.saveStatement")
+	if len(statement) != 1 {
+		t.Fatalf("Statement must be saved once, got %v", statement)
+	}
+
+	want := map[string]string{
+		"lang":     "ukrainian",
+		"name":     "A+B",
+		"legend":   "Add numbers \\includegraphics{pic.png}",
+		"input":    "Two numbers",
+		"output":   "Their sum",
+		"tutorial": "Just add them",
+	}
+
+	for name, value := range want {
+		if got := statement[0].Get(name); got != value {
+			t.Errorf("Statement %v must be %#v, got %#v", name, value, got)
+		}
+	}
+
+	if res := calls("problem.saveStatementResource"); len(res) != 1 || res[0].Get("name") != "pic.png" || res[0].Get("file") != "PNG" {
+		t.Errorf("Image must be saved as statement resource, got %v", res)
+	}
+
+	var files []string
+	for _, call := range calls("problem.saveFile") {
+		files = append(files, call.Get("type")+":"+call.Get("name")+":"+call.Get("sourceType")+call.Get("assets"))
+	}
+
+	if want := []string{"source:check.cpp:cpp.g++17", "source:validator.cpp:cpp.g++17", "source:gen.cpp:cpp.g++17", "resource:lib.h:CHECKER"}; !cmp.Equal(want, files) {
+		t.Errorf("Files do not match:\n%s", cmp.Diff(want, files))
+	}
+
+	if got := calls("problem.setChecker"); len(got) != 1 || got[0].Get("checker") != "check.cpp" {
+		t.Errorf("Checker must be set, got %v", got)
+	}
+
+	var solutions []string
+	for _, call := range calls("problem.saveSolution") {
+		solutions = append(solutions, call.Get("name")+":"+call.Get("sourceType")+":"+call.Get("tag"))
+	}
+
+	if want := []string{"slow.py:python.3:TL", "main.cpp:cpp.g++17:MA"}; !cmp.Equal(want, solutions) {
+		t.Errorf("Solutions do not match:\n%s", cmp.Diff(want, solutions))
+	}
+
+	var tests []string
+	for _, call := range calls("problem.saveTest") {
+		tests = append(tests, call.Get("testIndex")+":"+call.Get("testGroup")+":"+call.Get("testPoints")+":"+call.Get("testInput")+call.Get("testOutputForStatements"))
+	}
+
+	if want := []string{"1:0:0:1 2\n3\n", "3:1:60:1000 2000\n"}; !cmp.Equal(want, tests) {
+		t.Errorf("Tests do not match:\n%s", cmp.Diff(want, tests))
+	}
+
+	if script := calls("problem.saveScript"); len(script) != 1 || script[0].Get("source") != "gen 10 20 > 2\n" {
+		t.Errorf("Generated tests must be saved as script, got %v", script)
+	}
+
+	var groups []string
+	for _, call := range calls("problem.saveTestGroup") {
+		groups = append(groups, call.Get("group")+":"+call.Get("pointsPolicy")+":"+call.Get("feedbackPolicy")+":"+call.Get("dependencies"))
+	}
+
+	if want := []string{"0:EACH_TEST:COMPLETE:", "1:COMPLETE_GROUP:ICPC:0"}; !cmp.Equal(want, groups) {
+		t.Errorf("Groups do not match:\n%s", cmp.Diff(want, groups))
+	}
+
+	if tags := calls("problem.saveTags"); len(tags) != 1 || tags[0].Get("tags") != "binary search" {
+		t.Errorf("Topics must be saved as tags, got %v", tags)
+	}
+
+	if stub.methods[len(stub.methods)-1] != "problem.commitChanges" {
+		t.Errorf("Changes must be committed at the end, got %v", stub.methods)
+	}
+
+	var kinds []string
+	for _, w := range warnings {
+		kinds = append(kinds, w.Kind)
+	}
+
+	if want := []string{"limits-differ", "unmapped-solution"}; !cmp.Equal(want, kinds) {
+		t.Errorf("Warnings do not match:\n%s", cmp.Diff(want, kinds))
+	}
+}
+
+func TestSplit(t *testing.T) {
+	got := split("Legend\n\n\\InputFile\nInput\n\n\\Interaction\n\nProtocol\n\n\\OutputFile\n\nOutput\n\n\\Note\n\nNotes\n\n\\Scoring\n\nScore")
+	want := map[string]string{
+		"":            "Legend",
+		"InputFile":   "Input",
+		"Interaction": "Protocol",
+		"OutputFile":  "Output",
+		"Note":        "Notes",
+		"Scoring":     "Score",
+	}
+
+	if !cmp.Equal(want, got) {
+		t.Errorf("Sections do not match:\n%s", cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,48 @@
+package polygon
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// limiter spaces requests evenly, so there is at most one request per interval, zero interval disables the limit
+type limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time // time when the next request can be made
+}
+
+// wait blocks until a request can be made
+func (l *limiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+
+	if l.interval <= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -22,6 +22,9 @@ import (
 	"time"
 
 	"github.com/eolymp/go-problems/connector"
+	"github.com/eolymp/go-problems/locales"
+	"github.com/eolymp/go-problems/runtimes"
+	"github.com/eolymp/go-problems/topics"
 	assetpb "github.com/eolymp/go-sdk/eolymp/asset"
 	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
 	ecmpb "github.com/eolymp/go-sdk/eolymp/ecm"
@@ -32,16 +35,56 @@ import (
 
 var imageFinder = regexp.MustCompile("(\\\\includegraphics.*?{)(.+?)(})")
 
+// testsetNamespace is used to derive stable testset IDs from problem and group names, so importing the same package
+// twice produces identical snapshots.
+var testsetNamespace = uuid.MustParse("4f1c0a5e-6a52-4b8e-9d3c-2f0d8e7b1a64")
+
 type ProblemLoader struct {
-	upload *connector.MultipartUploader
-	log    connector.Logger
+	upload   *connector.MultipartUploader
+	log      connector.Logger
+	registry *runtimes.Registry
+	topics   *topics.Mapper
+	mode     Mode
+	policy   PackagePolicy
+	build    *BuildOptions
+	roles    map[string]TestsetRole
+	client   []func(*Client)
+}
+
+// UseRuntimes sets registry used to map Polygon languages to Eolymp runtimes, runtimes.Default() is used by default.
+func UseRuntimes(registry *runtimes.Registry) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.registry = registry
+	}
+}
+
+// UseTopics sets mapping of Polygon tags to Eolymp topics, topics.Default() is used by default.
+func UseTopics(mapper *topics.Mapper) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.topics = mapper
+	}
+}
+
+// UsePolygonOptions sets options of the Polygon API client used for polygon:// links.
+func UsePolygonOptions(opts ...func(*Client)) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.client = append(p.client, opts...)
+	}
 }
 
-func NewProblemLoader(upload connector.Uploader, log connector.Logger) *ProblemLoader {
-	return &ProblemLoader{
-		log:    log,
-		upload: connector.NewMultipartUploader(upload, log),
+func NewProblemLoader(upload connector.Uploader, log connector.Logger, opts ...func(*ProblemLoader)) *ProblemLoader {
+	p := &ProblemLoader{
+		log:      log,
+		upload:   connector.NewMultipartUploader(upload, log),
+		registry: runtimes.Default(),
+		topics:   topics.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(p)
 	}
+
+	return p
 }
 
 // Fetch downloads, parses and normalizes problem for it to be imported into the Eolymp database.
@@ -53,7 +96,26 @@ func NewProblemLoader(upload connector.Uploader, log connector.Logger) *ProblemL
 //   - host, path and port can be omitted
 //
 // An example of a link: polygon://api-key:api-secret@/?problemId=123
+//
+// Optional query parameters types, packageId, minRevision and latest select the package (see UsePackagePolicy), build
+// and verify make loader build a package when there is no ready package for the latest revision (see UsePackageBuild).
+// Standard packages have no generated tests, such tests are imported with generator scripts instead. Query parameter
+// mode=api makes loader assemble the problem from API calls, so no package is needed at all (see UseMode).
+//
+// Links to problem pages (https://polygon.codeforces.com/...) with username and password, and links to local problem
+// archives (file:///path/to/problem.zip) are supported as well.
 func (p *ProblemLoader) Fetch(ctx context.Context, link string) (*atlaspb.Snapshot, error) {
+	result, err := p.Import(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Snapshot, nil
+}
+
+// Import works like Fetch, but also returns provenance of the snapshot: Polygon problem ID, package ID, revision and
+// hash of the problem archive.
+func (p *ProblemLoader) Import(ctx context.Context, link string) (*connector.Result, error) {
 	// create workspace
 	path := filepath.Join(os.TempDir(), uuid.New().String())
 	if err := os.Mkdir(path, 0777); err != nil {
@@ -63,29 +125,62 @@ func (p *ProblemLoader) Fetch(ctx context.Context, link string) (*atlaspb.Snapsh
 	defer p.cleanup(path)
 
 	start := time.Now()
+	provenance := connector.NewProvenance("polygon", link)
 
 	p.log.Printf("Downloading problem archive")
 
 	// download and unpack
-	if err := p.download(ctx, path, link); err != nil {
+	warnings, err := p.download(ctx, path, link, provenance)
+	if err != nil {
 		return nil, fmt.Errorf("unable to download problem archive: %w", err)
 	}
 
 	p.log.Printf("Downloaded in %v", time.Since(start))
 
-	start = time.Now()
+	// problem assembled from API calls is written as a directory, there is no archive
+	if fileExists(filepath.Join(path, "problem.zip")) {
+		hash, err := connector.HashFile(filepath.Join(path, "problem.zip"))
+		if err != nil {
+			return nil, fmt.Errorf("unable to hash problem archive: %w", err)
+		}
 
-	if err := p.unpack(ctx, path); err != nil {
-		return nil, fmt.Errorf("unable to unpack problem archive: %w", err)
+		provenance.ArchiveSHA256 = hash
+
+		start = time.Now()
+
+		if err := p.unpack(ctx, path); err != nil {
+			return nil, fmt.Errorf("unable to unpack problem archive: %w", err)
+		}
+
+		p.log.Printf("Unpacked in %v", time.Since(start))
 	}
 
-	p.log.Printf("Unpacked in %v", time.Since(start))
+	result, err := p.read(ctx, path, provenance)
+	if err != nil {
+		return nil, err
+	}
 
-	return p.Snapshot(ctx, path)
+	result.Warnings = append(warnings, result.Warnings...)
+
+	return result, nil
 }
 
 // Snapshot reads problem specification from the unpacked problem archive and returns a snapshot of the problem.
 func (p *ProblemLoader) Snapshot(ctx context.Context, path string) (*atlaspb.Snapshot, error) {
+	result, err := p.ImportDir(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Snapshot, nil
+}
+
+// ImportDir works like Snapshot, but also returns provenance of the snapshot.
+func (p *ProblemLoader) ImportDir(ctx context.Context, path string) (*connector.Result, error) {
+	return p.read(ctx, path, connector.NewProvenance("polygon", (&url.URL{Scheme: "file", Path: path}).String()))
+}
+
+func (p *ProblemLoader) read(ctx context.Context, path string, provenance *connector.Provenance) (*connector.Result, error) {
 	file, err := os.Open(filepath.Join(path, "problem.xml"))
 	if err != nil {
 		return nil, fmt.Errorf("unable to open problem.xml: %w", err)
@@ -132,7 +227,7 @@ func (p *ProblemLoader) Snapshot(ctx context.Context, path string) (*atlaspb.Sna
 		return nil, fmt.Errorf("unable to read attachments (materials): %w", err)
 	}
 
-	testsets, tests, err := p.testing(ctx, path, spec)
+	testsets, tests, reports, err := p.testing(ctx, path, spec)
 	if err != nil {
 		return nil, fmt.Errorf("unable to read tests: %w", err)
 	}
@@ -164,8 +259,22 @@ func (p *ProblemLoader) Snapshot(ctx context.Context, path string) (*atlaspb.Sna
 		kind = atlaspb.Problem_OUTPUT
 	}
 
-	return &atlaspb.Snapshot{
-		Problem:     &atlaspb.Problem{Topics: TopicsFromTags(spec.Tags), Type: kind},
+	var tags []string
+	for _, tag := range spec.Tags {
+		if !strings.HasPrefix(tag.Value, "eolymp_") { // control tags, e.g. eolymp_tl=1000
+			tags = append(tags, tag.Value)
+		}
+	}
+
+	links, unmapped, fuzzy := p.topics.Map(tags)
+
+	provenance.Name = spec.ShortName
+	if provenance.Revision == "" && spec.Revision > 0 {
+		provenance.Revision = strconv.Itoa(spec.Revision)
+	}
+
+	snapshot := &atlaspb.Snapshot{
+		Problem:     &atlaspb.Problem{Topics: links, Type: kind},
 		Testing:     &atlaspb.TestingConfig{RunCount: runs, InteractiveFollowup: interactiveFollowup},
 		Checker:     checker,
 		Validator:   validator,
@@ -178,36 +287,177 @@ func (p *ProblemLoader) Snapshot(ctx context.Context, path string) (*atlaspb.Sna
 		Editorials:  editorials,
 		Solutions:   solutions,
 		Scripts:     scripts,
-	}, nil
+	}
+
+	warnings := append(p.warnings(spec), p.generators(tests, scripts)...)
+	warnings = append(warnings, reports...)
+	for _, tag := range unmapped {
+		p.log.Printf("Tag %#v does not match any topic", tag)
+		warnings = append(warnings, connector.Warning{Kind: "unmapped-tag", Message: fmt.Sprintf("tag %#v does not match any topic", tag)})
+	}
+
+	for _, match := range fuzzy {
+		p.log.Printf("Tag %#v is matched to similar tag %#v", match.Tag, match.Match)
+		warnings = append(warnings, connector.Warning{Kind: "fuzzy-tag", Message: fmt.Sprintf("tag %#v is matched to similar tag %#v", match.Tag, match.Match)})
+	}
+
+	return &connector.Result{Snapshot: snapshot, Provenance: provenance, Warnings: warnings}, nil
+}
+
+// runtime maps Polygon source type to Eolymp runtime
+func (p *ProblemLoader) runtime(source string) (string, bool) {
+	resolution, ok := p.registry.Resolve("polygon", source)
+	if !ok {
+		return "", false
+	}
+
+	return resolution.Runtime.ID, true
+}
+
+// warnings lists sources which are mapped to an older or incompatible runtime
+func (p *ProblemLoader) warnings(spec *Specification) (warnings []connector.Warning) {
+	sources := slices.Clone(spec.Checker.Sources)
+	for _, validator := range spec.Validator {
+		sources = append(sources, validator.Sources...)
+	}
+
+	sources = append(sources, spec.Interactor.Sources...)
+
+	for _, solution := range spec.Solutions {
+		sources = append(sources, solution.Source)
+	}
+
+	for _, script := range spec.Executables {
+		sources = append(sources, script.Source)
+	}
+
+	seen := map[string]bool{}
+	for _, source := range sources {
+		if seen[source.Path] {
+			continue
+		}
+
+		seen[source.Path] = true
+
+		resolution, ok := p.registry.Resolve("polygon", source.Type)
+		if !ok {
+			continue
+		}
+
+		message, ok := resolution.Warning()
+		if !ok {
+			continue
+		}
+
+		p.log.Printf("Source %#v: %v %v", source.Path, source.Type, message)
+
+		warnings = append(warnings, connector.Warning{
+			Kind:    "runtime-" + string(resolution.Status),
+			Path:    source.Path,
+			Message: source.Type + " " + message,
+		})
+	}
+
+	return warnings
 }
 
-// download problem archive and save it locally for parsing
-func (p *ProblemLoader) download(ctx context.Context, path string, link string) error {
+// generators lists tests generated by scripts which are missing, e.g. written in a language without runtime
+func (p *ProblemLoader) generators(tests []*atlaspb.Test, scripts []*atlaspb.Script) (warnings []connector.Warning) {
+	reported := map[string]bool{}
+	for _, test := range tests {
+		name := test.GetInputGenerator().GetScriptName()
+		if name == "" || reported[name] {
+			continue
+		}
+
+		if slices.ContainsFunc(scripts, func(s *atlaspb.Script) bool { return s.GetName() == name }) {
+			continue
+		}
+
+		reported[name] = true
+
+		p.log.Printf("Test %v is generated by script %#v which is not imported", test.GetIndex(), name)
+
+		warnings = append(warnings, connector.Warning{
+			Kind:    "missing-generator",
+			Message: fmt.Sprintf("tests are generated by script %#v which is not imported", name),
+		})
+	}
+
+	return warnings
+}
+
+// download problem archive and save it locally for parsing, problems fetched in API mode are written as a directory
+func (p *ProblemLoader) download(ctx context.Context, path string, link string, provenance *connector.Provenance) ([]connector.Warning, error) {
 	origin, err := url.Parse(link)
 	if err != nil {
-		return fmt.Errorf("invalid problem origin: %w", err)
+		return nil, fmt.Errorf("invalid problem origin: %w", err)
 	}
 
 	switch {
 	case origin.Scheme == "polygon":
 		pid, err := strconv.ParseInt(origin.Query().Get("problemId"), 10, 32)
 		if err != nil {
-			return errors.New("invalid problem origin: query parameter problemId must be a valid integer")
+			return nil, errors.New("invalid problem origin: query parameter problemId must be a valid integer")
+		}
+
+		mode, err := p.importMode(origin.Query())
+		if err != nil {
+			return nil, err
+		}
+
+		policy, err := p.packagePolicy(origin.Query())
+		if err != nil {
+			return nil, err
+		}
+
+		build, err := p.buildOptions(origin.Query())
+		if err != nil {
+			return nil, err
 		}
 
 		secret, _ := origin.User.Password()
-		poly := New(origin.User.Username(), secret)
+		poly := New(origin.User.Username(), secret, p.client...)
+
+		if mode == ModeAPI {
+			return p.assemble(ctx, path, poly, int(pid), provenance)
+		}
 
-		return p.downloadByID(ctx, path, poly, int(pid))
+		return nil, p.downloadByID(ctx, path, poly, int(pid), policy, build, provenance)
 	case origin.Scheme == "https" && origin.Hostname() == "polygon.codeforces.com" &&
 		origin.Port() == "":
 
-		return p.downloadByLink(ctx, path, origin)
+		return nil, p.downloadByLink(ctx, path, origin)
+	case origin.Scheme == "file":
+		return nil, p.downloadByPath(path, origin.Path)
 	default:
-		return fmt.Errorf("invalid problem origin: schema %#v is not supported", origin.Scheme)
+		return nil, fmt.Errorf("invalid problem origin: schema %#v is not supported", origin.Scheme)
 	}
 }
 
+// downloadByPath copies problem archive from the local filesystem
+func (p *ProblemLoader) downloadByPath(path string, archive string) error {
+	src, err := os.Open(archive)
+	if err != nil {
+		return fmt.Errorf("unable to open problem archive: %w", err)
+	}
+
+	defer src.Close()
+
+	dst, err := os.Create(filepath.Join(path, "problem.zip"))
+	if err != nil {
+		return fmt.Errorf("unable to create problem archieve: %w", err)
+	}
+
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		return fmt.Errorf("unable to copy problem archive: %w", err)
+	}
+
+	return nil
+}
+
 func (p *ProblemLoader) downloadByLink(ctx context.Context, path string, link *url.URL) error {
 	username := link.User.Username()
 	password, _ := link.User.Password()
@@ -268,12 +518,16 @@ func (p *ProblemLoader) downloadByLink(ctx context.Context, path string, link *u
 	return nil
 }
 
-func (p *ProblemLoader) downloadByID(ctx context.Context, path string, poly *Client, id int) error {
-	pack, err := p.pickPackage(ctx, poly, id)
+func (p *ProblemLoader) downloadByID(ctx context.Context, path string, poly *Client, id int, policy PackagePolicy, build *BuildOptions, provenance *connector.Provenance) error {
+	pack, err := p.pickPackage(ctx, poly, id, policy, build)
 	if err != nil {
 		return fmt.Errorf("unable to find package: %w", err)
 	}
 
+	provenance.ProblemID = strconv.Itoa(id)
+	provenance.PackageID = strconv.Itoa(pack.ID)
+	provenance.Revision = strconv.Itoa(pack.Revision)
+
 	src, err := poly.DownloadPackage(ctx, DownloadPackageInput{
 		ProblemID: id,
 		PackageID: pack.ID,
@@ -300,25 +554,14 @@ func (p *ProblemLoader) downloadByID(ctx context.Context, path string, poly *Cli
 	return nil
 }
 
-// pickPackage to download, it has to be in the right status, and it has to be windows, so we can use generated tests
-func (p *ProblemLoader) pickPackage(ctx context.Context, poly *Client, problem int) (*Package, error) {
-	packages, err := poly.ListPackages(ctx, ListPackagesInput{ProblemID: problem})
-	if err != nil {
-		return nil, err
-	}
-
-	for _, pack := range packages {
-		if pack.Type == "windows" && pack.State == "READY" {
-			return &pack, nil
-		}
-	}
-
-	return nil, errors.New("no suitable packages")
-}
-
 // unpack problem archive
 func (p *ProblemLoader) unpack(ctx context.Context, path string) error {
-	reader, err := zip.OpenReader(filepath.Join(path, "problem.zip"))
+	return unzip(filepath.Join(path, "problem.zip"), path)
+}
+
+// unzip extracts archive into the path
+func unzip(archive, path string) error {
+	reader, err := zip.OpenReader(archive)
 	if err != nil {
 		return err
 	}
@@ -409,7 +652,7 @@ func (p *ProblemLoader) checker(ctx context.Context, path string, spec *Specific
 		return &atlaspb.Checker{Type: executorpb.Checker_LINES}, nil
 	default:
 		for _, checker := range spec.Checker.Sources {
-			runtime, ok := RuntimeMapping[checker.Type]
+			runtime, ok := p.runtime(checker.Type)
 			if !ok {
 				continue
 			}
@@ -446,7 +689,7 @@ func (p *ProblemLoader) checker(ctx context.Context, path string, spec *Specific
 func (p *ProblemLoader) validator(ctx context.Context, path string, spec *Specification) (*atlaspb.Validator, error) {
 	for _, validator := range spec.Validator {
 		for _, source := range validator.Sources {
-			runtime, ok := RuntimeMapping[source.Type]
+			runtime, ok := p.runtime(source.Type)
 			if !ok {
 				continue
 			}
@@ -486,7 +729,7 @@ func (p *ProblemLoader) interactor(ctx context.Context, path string, spec *Speci
 	}
 
 	for _, source := range spec.Interactor.Sources {
-		runtime, ok := RuntimeMapping[source.Type]
+		runtime, ok := p.runtime(source.Type)
 		if !ok {
 			continue
 		}
@@ -526,7 +769,7 @@ func (p *ProblemLoader) statements(ctx context.Context, path string, spec *Speci
 			continue
 		}
 
-		locale, err := LocaleFromLanguage(statement.Language)
+		locale, err := locales.Parse(statement.Language)
 		if err != nil {
 			p.log.Printf("Skipping statement %#v because it has unsupported language: %v", statement.Path, err)
 			continue
@@ -587,7 +830,7 @@ func (p *ProblemLoader) editorials(ctx context.Context, path string, spec *Speci
 			continue
 		}
 
-		locale, err := LocaleFromLanguage(tutorial.Language)
+		locale, err := locales.Parse(tutorial.Language)
 		if err != nil {
 			p.log.Printf("Skipping tutorial %#v because it has unsupported language: %v", tutorial.Path, err)
 			continue
@@ -612,7 +855,7 @@ func (p *ProblemLoader) editorials(ctx context.Context, path string, spec *Speci
 
 func (p *ProblemLoader) solutions(ctx context.Context, path string, spec *Specification) (solutions []*atlaspb.Solution, err error) {
 	for _, solution := range spec.Solutions {
-		runtime, ok := RuntimeMapping[solution.Source.Type]
+		runtime, ok := p.runtime(solution.Source.Type)
 		if !ok {
 			p.log.Errorf("Skipping solution %#v because runtime %#v is not mapped", solution.Source.Path, solution.Source.Type)
 			continue
@@ -660,7 +903,7 @@ func (p *ProblemLoader) solutions(ctx context.Context, path string, spec *Specif
 
 func (p *ProblemLoader) scripts(ctx context.Context, path string, spec *Specification) (scripts []*atlaspb.Script, err error) {
 	for _, script := range spec.Executables {
-		runtime, ok := RuntimeMapping[script.Source.Type]
+		runtime, ok := p.runtime(script.Source.Type)
 		if !ok {
 			p.log.Errorf("Skipping script %#v because runtime %#v is not mapped", script.Source.Path, script.Source.Type)
 			continue
@@ -702,7 +945,7 @@ func (p *ProblemLoader) scripts(ctx context.Context, path string, spec *Specific
 			continue
 		}
 
-		runtime, ok := RuntimeMapping[solution.Source.Type]
+		runtime, ok := p.runtime(solution.Source.Type)
 		if !ok {
 			p.log.Errorf("Unable to create solution script because runtime %#v is not mapped", solution.Source.Type)
 			continue
@@ -742,9 +985,14 @@ func (p *ProblemLoader) scripts(ctx context.Context, path string, spec *Specific
 
 // todo: add grader to the templates
 func (p *ProblemLoader) templates(ctx context.Context, path string, spec *Specification) (templates []*atlaspb.Template, err error) {
-	for lang, runtimes := range TemplateMapping {
-		ext, ok := LanguageExtensions[lang]
-		if !ok {
+	langs := p.registry.Languages()
+	sort.Slice(langs, func(i, j int) bool { return langs[i].Name < langs[j].Name })
+
+	for _, language := range langs {
+		lang, ext := language.Name, language.Extension()
+
+		targets := p.registry.Templates(lang)
+		if len(targets) == 0 || ext == "" {
 			continue
 		}
 
@@ -787,7 +1035,7 @@ func (p *ProblemLoader) templates(ctx context.Context, path string, spec *Specif
 			continue
 		}
 
-		for _, runtime := range runtimes {
+		for _, runtime := range targets {
 			templates = append(templates, &atlaspb.Template{
 				Runtime: runtime,
 				Source:  string(source),
@@ -851,33 +1099,92 @@ func (p *ProblemLoader) attachments(ctx context.Context, path string, spec *Spec
 	return
 }
 
-func (p *ProblemLoader) testing(ctx context.Context, path string, spec *Specification) (testsets []*atlaspb.Testset, tests []*atlaspb.Test, err error) {
-	// don't bother if there are no tests
-	if len(spec.Judging.Testsets) < 0 {
-		return
+func (p *ProblemLoader) testing(ctx context.Context, path string, spec *Specification) (testsets []*atlaspb.Testset, tests []*atlaspb.Test, warnings []connector.Warning, err error) {
+	assignments, err := p.testsetRoles(spec)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
-	// pick testset called "tests" or first one
-	polyset := p.pickTestset(spec)
+	overrides := p.overrides(spec)
 
-	p.log.Printf("Importing testset %#v", polyset.Name)
+	// create a group to upload tests in parallel
+	eg, ctx := errgroup.WithContext(ctx)
 
-	// eolymp specific overrides
-	blockMin := false
-	timeLimit := polyset.TimeLimit
-	memLimit := polyset.MemoryLimit
+	// limit number of parallel uploads
+	eg.SetLimit(5)
+
+	// final testset goes first, so its groups get indexes starting from 0, other testsets follow
+	var scored []*atlaspb.Test
+	for _, a := range assignments {
+		var imported []*atlaspb.Testset
 
+		if a.role != TestsetSkip {
+			var offset uint32
+			if len(testsets) > 0 {
+				offset = testsets[len(testsets)-1].GetIndex() + 1
+			}
+
+			p.log.Printf("Importing testset %#v as %v", a.testset.Name, a.role)
+
+			var items []*atlaspb.Test
+			imported, items, err = p.testset(ctx, eg, path, spec, a.testset, a.role, offset, overrides)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+
+			testsets = append(testsets, imported...)
+			tests = append(tests, items...)
+
+			if a.role == TestsetFinal {
+				scored = append(scored, items...)
+			}
+		}
+
+		// single testset is the usual case, there is nothing to report
+		if len(spec.Judging.Testsets) > 1 {
+			report := testsetReport(a.testset.Name, a.role, imported)
+			p.log.Printf("%v", report.Message)
+			warnings = append(warnings, report)
+		}
+	}
+
+	// set points evenly if total is 0
+	var total float32
+	for _, test := range scored {
+		total += test.GetScore()
+	}
+
+	if total == 0 {
+		spread(scored, 100)
+	}
+
+	if err := eg.Wait(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return
+}
+
+// overrides are eolymp specific testing parameters set with problem tags
+type overrides struct {
+	blockMin  bool
+	timeLimit int // time limit in milliseconds, zero if not overridden
+	memLimit  int // memory limit in bytes, zero if not overridden
+}
+
+// overrides reads eolymp specific tags
+func (p *ProblemLoader) overrides(spec *Specification) (o overrides) {
 	for _, tag := range spec.Tags {
 		switch {
 		case tag.Value == "block_min" || tag.Value == "min_block":
 			p.log.Printf("Found block_min tag, switch to min scoring and first point dependency mode")
-			blockMin = true
+			o.blockMin = true
 		case strings.HasPrefix(tag.Value, "eolymp_tl="):
 			if val, err := strconv.Atoi(tag.Value[10:]); err != nil {
 				p.log.Errorf("Found eolymp_tl tag, but unable to parse it: %v", err)
 			} else {
 				p.log.Printf("Found eolymp_tl tag, overriding time limit to %v ms", val)
-				timeLimit = val
+				o.timeLimit = val
 			}
 
 		case strings.HasPrefix(tag.Value, "eolymp_ml="):
@@ -885,11 +1192,27 @@ func (p *ProblemLoader) testing(ctx context.Context, path string, spec *Specific
 				p.log.Errorf("Found eolymp_ml tag, but unable to parse it: %v", err)
 			} else {
 				p.log.Printf("Found eolymp_ml tag, overriding memory limit to %v bytes", val)
-				memLimit = val
+				o.memLimit = val
 			}
 		}
 	}
 
+	return
+}
+
+// testset imports Polygon testset, its groups become eolymp testsets with indexes starting from offset. Uploads are
+// started in the errgroup, caller must wait for it. Tests of pretests have no score and are never used as examples.
+func (p *ProblemLoader) testset(ctx context.Context, eg *errgroup.Group, path string, spec *Specification, polyset SpecificationTestset, role TestsetRole, offset uint32, overrides overrides) (testsets []*atlaspb.Testset, tests []*atlaspb.Test, err error) {
+	timeLimit := polyset.TimeLimit
+	if overrides.timeLimit != 0 {
+		timeLimit = overrides.timeLimit
+	}
+
+	memLimit := polyset.MemoryLimit
+	if overrides.memLimit != 0 {
+		memLimit = overrides.memLimit
+	}
+
 	groupByName := map[string]SpecificationGroup{}
 	for _, group := range polyset.Groups {
 		groupByName[group.Name] = group
@@ -898,11 +1221,32 @@ func (p *ProblemLoader) testing(ctx context.Context, path string, spec *Specific
 	testsetIndexByGroup := p.mapGroupToIndex(polyset)
 	testsetByGroup := map[string]*atlaspb.Testset{}
 
+	// iterate groups in the order of their indexes, so testsets always come out in the same order
+	var groups []string
+	for name := range testsetIndexByGroup {
+		groups = append(groups, name)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if a, b := testsetIndexByGroup[groups[i]], testsetIndexByGroup[groups[j]]; a != b {
+			return a < b
+		}
+
+		return groups[i] < groups[j]
+	})
+
+	// final testset keeps indexes of its groups, others are packed right after the previous testsets
+	final := role == TestsetFinal
+	if !final && len(groups) > 0 {
+		offset -= testsetIndexByGroup[groups[0]]
+	}
+
 	// read testsets
-	for name, index := range testsetIndexByGroup {
+	for _, name := range groups {
+		index := testsetIndexByGroup[name]
 		testset := &atlaspb.Testset{
-			Id:             uuid.New().String(),
-			Index:          index,
+			Id:             p.testsetID(spec, path, polyset, name),
+			Index:          offset + index,
 			CpuLimit:       uint32(timeLimit),
 			MemoryLimit:    uint64(memLimit),
 			FileSizeLimit:  536870912,
@@ -924,7 +1268,7 @@ func (p *ProblemLoader) testing(ctx context.Context, path string, spec *Specific
 				testset.ScoringMode = atlaspb.ScoringMode_ALL
 			}
 
-			if blockMin && index != 0 {
+			if overrides.blockMin && index != 0 {
 				testset.ScoringMode = atlaspb.ScoringMode_WORST
 				testset.DependencyMode = atlaspb.Testset_FIRST_POINT
 			}
@@ -937,7 +1281,7 @@ func (p *ProblemLoader) testing(ctx context.Context, path string, spec *Specific
 			}
 
 			for _, dep := range group.Dependencies {
-				testset.Dependencies = append(testset.Dependencies, testsetIndexByGroup[dep.Group])
+				testset.Dependencies = append(testset.Dependencies, offset+testsetIndexByGroup[dep.Group])
 			}
 		}
 
@@ -945,14 +1289,8 @@ func (p *ProblemLoader) testing(ctx context.Context, path string, spec *Specific
 		testsets = append(testsets, testset)
 	}
 
-	// create a group to upload tests in parallel
-	eg, ctx := errgroup.WithContext(ctx)
-
-	// limit number of parallel uploads
-	eg.SetLimit(5)
-
 	// read tests
-	var total float32
+	testsByGroup := map[string][]*atlaspb.Test{}
 	for index, polytest := range polyset.Tests {
 		testset, ok := testsetByGroup[polytest.Group]
 		if !ok {
@@ -963,14 +1301,21 @@ func (p *ProblemLoader) testing(ctx context.Context, path string, spec *Specific
 		test := &atlaspb.Test{
 			TestsetId: testset.GetId(),
 			Index:     int32(index + 1),
-			Example:   polytest.Sample,
-			Score:     polytest.Points,
+			Example:   polytest.Sample && final,
+		}
+
+		if final {
+			test.Score = polytest.Points
 		}
 
 		// make input
 		input := filepath.Join(path, fmt.Sprintf(polyset.InputPathPattern, index+1))
 		if polytest.Method == "generated" && !fileExists(input) {
-			command := strings.Split(polytest.Command, " ")
+			command := strings.Fields(polytest.Command)
+			if len(command) == 0 {
+				return nil, nil, fmt.Errorf("test %v is generated, but its input is missing and it has no generator command", index+1)
+			}
+
 			test.Input = &atlaspb.Test_InputGenerator{InputGenerator: &atlaspb.Test_Generator{ScriptName: command[0], Arguments: command[1:]}}
 		} else {
 			eg.Go(func() error {
@@ -994,7 +1339,7 @@ func (p *ProblemLoader) testing(ctx context.Context, path string, spec *Specific
 
 		// sample input and answer
 		// look for files named example.01, example.01.a alongside statements, normally each statement has a copy, take the first one.
-		if polytest.Sample {
+		if test.Example {
 			var sampleInputOk, sampleAnswerOk bool
 			for _, s := range spec.Statements {
 				if s.Type != "application/x-tex" {
@@ -1031,23 +1376,59 @@ func (p *ProblemLoader) testing(ctx context.Context, path string, spec *Specific
 
 		// add test to the list
 		tests = append(tests, test)
-		total += test.GetScore()
+		testsByGroup[polytest.Group] = append(testsByGroup[polytest.Group], test)
 	}
 
-	// set points evenly if total is 0
-	if total == 0 {
-		var credit float64 = 100
-		for i, test := range tests {
-			test.Score = float32(math.Min(math.Floor(credit/float64(len(tests)-i)), credit))
-			credit -= float64(test.Score)
-		}
+	if final {
+		p.groupPoints(polyset, testsByGroup)
 	}
 
-	if err := eg.Wait(); err != nil {
-		return nil, nil, err
+	return
+}
+
+// groupPoints assigns points of complete groups to their tests. Polygon often keeps points on such groups rather than
+// on their tests, the group is scored only when all its tests pass, so it's enough for its tests to add up to the
+// points of the group. Points of the tests are kept if they already add up to the points of the group.
+func (p *ProblemLoader) groupPoints(polyset SpecificationTestset, testsByGroup map[string][]*atlaspb.Test) {
+	for _, group := range polyset.Groups {
+		if group.PointsPolicy != "complete-group" || group.Points <= 0 {
+			continue
+		}
+
+		tests := testsByGroup[group.Name]
+		if len(tests) == 0 {
+			p.log.Errorf("Group %#v has %v points, but it has no tests", group.Name, group.Points)
+			continue
+		}
+
+		var sum float64
+		for _, test := range tests {
+			sum += float64(test.GetScore())
+		}
+
+		if sum == float64(group.Points) {
+			continue
+		}
+
+		if sum != 0 {
+			p.log.Printf("Group %#v has %v points, but its tests have %v points in total, using points of the group", group.Name, group.Points, sum)
+		}
+
+		spread(tests, float64(group.Points))
 	}
+}
 
-	return
+// spread credit across tests evenly using whole points, the last test gets the remainder, so the total is exact
+func spread(tests []*atlaspb.Test, credit float64) {
+	for i, test := range tests {
+		if i == len(tests)-1 {
+			test.Score = float32(credit)
+			break
+		}
+
+		test.Score = float32(math.Min(math.Floor(credit/float64(len(tests)-i)), credit))
+		credit -= float64(test.Score)
+	}
 }
 
 // uploadImagesFromLatex finds images in text, uploads them and replaces original names with links.
@@ -1091,6 +1472,21 @@ func (p *ProblemLoader) uploadImagesFromLatex(ctx context.Context, path, text st
 	return text
 }
 
+// testsetID derives testset ID from the problem, polygon testset and group names. Problem is identified by its URL or
+// short name, and by the package directory name if problem.xml has neither.
+func (p *ProblemLoader) testsetID(spec *Specification, path string, testset SpecificationTestset, group string) string {
+	problem := spec.URL
+	if problem == "" {
+		problem = spec.ShortName
+	}
+
+	if problem == "" {
+		problem = filepath.Base(path)
+	}
+
+	return uuid.NewSHA1(testsetNamespace, []byte(problem+"/"+testset.Name+"/"+group)).String()
+}
+
 // pickTestset find "main" testset for a problem
 func (p *ProblemLoader) pickTestset(spec *Specification) SpecificationTestset {
 	for _, set := range spec.Judging.Testsets {
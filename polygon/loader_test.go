@@ -1,18 +1,28 @@
 package polygon
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
 	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
 	"sort"
 	"testing"
 
+	"github.com/eolymp/go-problems/connector"
 	. "github.com/eolymp/go-problems/connector/testing"
+	"github.com/eolymp/go-problems/runtimes"
 	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
 	ecmpb "github.com/eolymp/go-sdk/eolymp/ecm"
 	executorpb "github.com/eolymp/go-sdk/eolymp/executor"
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
 )
 
 var opts = []cmp.Option{
@@ -527,4 +537,355 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 		}
 	})
 
+	t.Run("snapshot is deterministic", func(t *testing.T) {
+		for _, path := range []string{".testdata/03-test-scoring-with-points", ".testdata/13-templates"} {
+			first, err := loader.Snapshot(ctx, path)
+			if err != nil {
+				t.Fatal("Problem snapshot has failed:", err)
+			}
+
+			for i := 0; i < 5; i++ {
+				again, err := loader.Snapshot(ctx, path)
+				if err != nil {
+					t.Fatal("Problem snapshot has failed:", err)
+				}
+
+				want, _ := proto.MarshalOptions{Deterministic: true}.Marshal(first)
+				got, _ := proto.MarshalOptions{Deterministic: true}.Marshal(again)
+
+				if !bytes.Equal(want, got) {
+					t.Fatalf("Snapshots of %v do not match:\n%s", path, cmp.Diff(first, again, protocmp.Transform()))
+				}
+			}
+		}
+	})
+}
+
+func TestProblemLoader_Import(t *testing.T) {
+	ctx := context.Background()
+	loader := NewProblemLoader(MockUploader(), MockLogger(t))
+
+	archive := filepath.Join(t.TempDir(), "problem.zip")
+	if err := zipDir(".testdata/02-statements", archive); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	link := url.URL{Scheme: "file", User: url.UserPassword("key", "secret"), Path: archive}
+
+	result, err := loader.Import(ctx, link.String())
+	if err != nil {
+		t.Fatal("Problem import has failed:", err)
+	}
+
+	got := result.Provenance
+
+	if want := "file://" + archive; got.Origin != want {
+		t.Errorf("Origin must be %#v (without credentials), got %#v", want, got.Origin)
+	}
+
+	if want := fmt.Sprintf("%x", sha256.Sum256(data)); got.ArchiveSHA256 != want {
+		t.Errorf("Archive hash must be %v, got %v", want, got.ArchiveSHA256)
+	}
+
+	if got.Format != "polygon" || got.Name != "train-14-02-21-0" || got.Revision != "5" {
+		t.Errorf("Provenance does not match problem.xml: %+v", got)
+	}
+
+	if got.LoaderVersion == "" || got.ImportedAt.IsZero() {
+		t.Errorf("Provenance must have loader version and import time: %+v", got)
+	}
+
+	if len(result.Snapshot.GetStatements()) == 0 {
+		t.Error("Snapshot must have statements")
+	}
+}
+
+func TestProblemLoader_ImportDir_warnings(t *testing.T) {
+	ctx := context.Background()
+
+	registry, err := runtimes.Default().Override([]byte(`{pins: {cpp: "cpp:17-gnu10"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewProblemLoader(MockUploader(), MockLogger(t), UseRuntimes(registry))
+
+	result, err := loader.ImportDir(ctx, ".testdata/15-validator")
+	if err != nil {
+		t.Fatal("Problem import has failed:", err)
+	}
+
+	if got := result.Snapshot.GetValidator().GetRuntime(); got != "cpp:17-gnu10" {
+		t.Errorf("Validator must use pinned runtime, got %v", got)
+	}
+
+	want := []connector.Warning{{
+		Kind:    "runtime-downgrade",
+		Path:    "files/val.cpp",
+		Message: "cpp.gcc14-64-msys2-g++23 resolved to cpp:17-gnu10 which is older than cpp 23",
+	}}
+
+	if !cmp.Equal(want, result.Warnings) {
+		t.Errorf("Warnings do not match:\n%s", cmp.Diff(want, result.Warnings))
+	}
+}
+
+func TestProblemLoader_ImportDir_unmappedTags(t *testing.T) {
+	ctx := context.Background()
+	loader := NewProblemLoader(MockUploader(), MockLogger(t))
+
+	result, err := loader.ImportDir(ctx, ".testdata/08-custom-limit")
+	if err != nil {
+		t.Fatal("Problem import has failed:", err)
+	}
+
+	// control tags (eolymp_tl, eolymp_ml) are not reported
+	want := []connector.Warning{{Kind: "unmapped-tag", Message: `tag "array" does not match any topic`}}
+
+	if !cmp.Equal(want, result.Warnings) {
+		t.Errorf("Warnings do not match:\n%s", cmp.Diff(want, result.Warnings))
+	}
+}
+
+func zipDir(src, dst string) error {
+	file, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	w := zip.NewWriter(file)
+
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		name, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		f, err := w.Create(filepath.ToSlash(name))
+		if err != nil {
+			return err
+		}
+
+		_, err = f.Write(data)
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return w.Close()
+}
+
+func TestProblemLoader_ImportDir_missingGenerator(t *testing.T) {
+	ctx := context.Background()
+	loader := NewProblemLoader(MockUploader(), MockLogger(t))
+
+	// standard package without generated tests and without the generator
+	path := t.TempDir()
+	if err := os.CopyFS(path, os.DirFS(".testdata/11-tests-generator")); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := os.ReadFile(filepath.Join(path, "problem.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spec = regexp.MustCompile(`(?s)<executables>.*</executables>`).ReplaceAll(spec, nil)
+	if err := os.WriteFile(filepath.Join(path, "problem.xml"), spec, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := loader.ImportDir(ctx, path)
+	if err != nil {
+		t.Fatal("Problem import has failed:", err)
+	}
+
+	if got := result.Snapshot.GetTests()[1].GetInputGenerator(); got.GetScriptName() != "gen" || !cmp.Equal([]string{"10", "10", "100"}, got.GetArguments()) {
+		t.Errorf("Generated test must fall back to the generator, got %v", got)
+	}
+
+	want := []connector.Warning{{Kind: "missing-generator", Message: `tests are generated by script "gen" which is not imported`}}
+	if !cmp.Equal(want, result.Warnings) {
+		t.Errorf("Warnings do not match:\n%s", cmp.Diff(want, result.Warnings))
+	}
+}
+
+func TestProblemLoader_ImportDir_pretests(t *testing.T) {
+	ctx := context.Background()
+
+	describe := func(snap *atlaspb.Snapshot) (testsets []string, tests []string) {
+		index := map[string]uint32{}
+		for _, testset := range snap.GetTestsets() {
+			index[testset.GetId()] = testset.GetIndex()
+			testsets = append(testsets, fmt.Sprintf("%v:%v:%v", testset.GetIndex(), testset.GetCpuLimit(), testset.GetDependencies()))
+		}
+
+		for _, test := range snap.GetTests() {
+			tests = append(tests, fmt.Sprintf("%v/%v:%v:%v", index[test.GetTestsetId()], test.GetIndex(), test.GetScore(), test.GetExample()))
+		}
+
+		return
+	}
+
+	t.Run("default roles", func(t *testing.T) {
+		loader := NewProblemLoader(MockUploader(), MockLogger(t))
+
+		result, err := loader.ImportDir(ctx, ".testdata/20-pretests")
+		if err != nil {
+			t.Fatal("Problem import has failed:", err)
+		}
+
+		testsets, tests := describe(result.Snapshot)
+
+		if want := []string{"0:1000:[]", "1:1000:[]", "2:1000:[1]", "3:1000:[]", "4:3000:[]"}; !cmp.Equal(want, testsets) {
+			t.Errorf("Testsets do not match:\n%s", cmp.Diff(want, testsets))
+		}
+
+		want := []string{"0/1:0:true", "1/2:40:false", "2/3:30:false", "2/4:30:false", "3/1:0:false", "3/2:0:false", "4/1:0:false"}
+		if !cmp.Equal(want, tests) {
+			t.Errorf("Tests do not match:\n%s", cmp.Diff(want, tests))
+		}
+
+		reports := []connector.Warning{
+			{Kind: "testset-final", Message: `testset "tests" is imported as final tests into testsets 0-2`},
+			{Kind: "testset-pretests", Message: `testset "pretests" is imported as pretests without score into testset 3`},
+			{Kind: "testset-pretests", Message: `testset "stress" is imported as pretests without score into testset 4`},
+		}
+
+		if !cmp.Equal(reports, result.Warnings) {
+			t.Errorf("Warnings do not match:\n%s", cmp.Diff(reports, result.Warnings))
+		}
+	})
+
+	t.Run("configured roles", func(t *testing.T) {
+		loader := NewProblemLoader(MockUploader(), MockLogger(t), UseTestsetRoles(map[string]TestsetRole{
+			"Pretests": TestsetFinal,
+			"tests":    TestsetSkip,
+			"stress":   TestsetPretests,
+		}))
+
+		result, err := loader.ImportDir(ctx, ".testdata/20-pretests")
+		if err != nil {
+			t.Fatal("Problem import has failed:", err)
+		}
+
+		testsets, tests := describe(result.Snapshot)
+
+		if want := []string{"1:1000:[]", "2:3000:[]"}; !cmp.Equal(want, testsets) {
+			t.Errorf("Testsets do not match:\n%s", cmp.Diff(want, testsets))
+		}
+
+		// pretests have no points, so the score is spread evenly
+		if want := []string{"1/1:50:true", "1/2:50:false", "2/1:0:false"}; !cmp.Equal(want, tests) {
+			t.Errorf("Tests do not match:\n%s", cmp.Diff(want, tests))
+		}
+
+		var kinds []string
+		for _, w := range result.Warnings {
+			kinds = append(kinds, w.Kind+": "+w.Message)
+		}
+
+		want := []string{
+			`testset-final: testset "pretests" is imported as final tests into testset 1`,
+			`testset-skip: testset "tests" is skipped`,
+			`testset-pretests: testset "stress" is imported as pretests without score into testset 2`,
+		}
+
+		if !cmp.Equal(want, kinds) {
+			t.Errorf("Warnings do not match:\n%s", cmp.Diff(want, kinds))
+		}
+	})
+
+	t.Run("several final testsets", func(t *testing.T) {
+		loader := NewProblemLoader(MockUploader(), MockLogger(t), UseTestsetRoles(map[string]TestsetRole{"pretests": TestsetFinal}))
+
+		if _, err := loader.ImportDir(ctx, ".testdata/20-pretests"); err == nil {
+			t.Error("Problem import must fail when several testsets are final")
+		}
+	})
+}
+
+func TestProblemLoader_ImportDir_groupPoints(t *testing.T) {
+	ctx := context.Background()
+	loader := NewProblemLoader(MockUploader(), MockLogger(t))
+
+	tests := map[string]struct {
+		groups map[uint32]float32 // score of each testset
+		tests  []float32          // score of each test
+	}{
+		// points are kept on groups only, they are spread across tests of the group
+		".testdata/21-group-points": {
+			groups: map[uint32]float32{0: 0, 1: 17, 2: 33.5, 3: 49.5},
+			tests:  []float32{0, 5, 6, 6, 16, 17.5, 24, 25.5},
+		},
+		// tests which add up to points of the group keep their points, others are overridden by the group
+		".testdata/22-group-points-mixed": {
+			groups: map[uint32]float32{0: 0, 1: 20, 2: 30, 3: 25, 4: 25},
+			tests:  []float32{0, 5, 15, 15, 15, 12, 13, 12.5, 12.5},
+		},
+	}
+
+	for path, tc := range tests {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			snap, err := loader.Snapshot(ctx, path)
+			if err != nil {
+				t.Fatal("Problem snapshot has failed:", err)
+			}
+
+			index := map[string]uint32{}
+			for _, testset := range snap.GetTestsets() {
+				index[testset.GetId()] = testset.GetIndex()
+			}
+
+			groups := map[uint32]float32{}
+			var scores []float32
+
+			for _, test := range snap.GetTests() {
+				groups[index[test.GetTestsetId()]] += test.GetScore()
+				scores = append(scores, test.GetScore())
+			}
+
+			if !cmp.Equal(tc.groups, groups) {
+				t.Errorf("Subtask scores do not match:\n%s", cmp.Diff(tc.groups, groups))
+			}
+
+			if !cmp.Equal(tc.tests, scores) {
+				t.Errorf("Test scores do not match:\n%s", cmp.Diff(tc.tests, scores))
+			}
+		})
+	}
+}
+
+func TestProblemLoader_testsetID(t *testing.T) {
+	loader := NewProblemLoader(MockUploader(), MockLogger(t))
+	testset := SpecificationTestset{Name: "tests"}
+
+	named := loader.testsetID(&Specification{ShortName: "a-plus-b"}, "/tmp/first", testset, "1")
+	if again := loader.testsetID(&Specification{ShortName: "a-plus-b"}, "/tmp/second", testset, "1"); named != again {
+		t.Errorf("testset ID must depend on problem name only, got %v and %v", named, again)
+	}
+
+	first := loader.testsetID(&Specification{}, "/tmp/first", testset, "1")
+	second := loader.testsetID(&Specification{}, "/tmp/second", testset, "1")
+	if first == second {
+		t.Errorf("testsets of unnamed problems in different directories must have different IDs, got %v", first)
+	}
 }
@@ -0,0 +1,356 @@
+package polygon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// packageTypes lists package types and the types a package can be downloaded as, windows packages contain everything
+// linux packages have, and those contain everything standard packages have.
+var packageTypes = map[string][]string{
+	"windows":  {"windows", "linux", "standard"},
+	"linux":    {"linux", "standard"},
+	"standard": {"standard"},
+}
+
+// PackagePolicy defines which Polygon package is imported.
+type PackagePolicy struct {
+	Types       []string // package types in order of preference, windows, linux and standard by default
+	PackageID   int      // import exact package, other fields are ignored
+	MinRevision int      // packages for older revisions are not imported
+	Latest      bool     // prefer the newest revision over the preferred type
+}
+
+// UsePackagePolicy sets which packages are imported, by default windows packages are preferred over linux and standard
+// ones, and the newest ready package of the preferred type is used. It can be changed per link using query parameters types, packageId,
+// minRevision and latest: polygon://key:secret@/?problemId=123&types=linux,standard&minRevision=10&latest=true.
+func UsePackagePolicy(policy PackagePolicy) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.policy = policy
+	}
+}
+
+// BuildOptions configure package build, which is triggered when problem has no ready package for the latest revision.
+type BuildOptions struct {
+	Verify   bool          // run solutions and check their tags during the build
+	Timeout  time.Duration // how long to wait for the build, 30 minutes by default
+	Interval time.Duration // how often package state is checked, 15 seconds by default
+}
+
+// UsePackageBuild makes loader build a full package when problem has no ready package for the latest revision, by
+// default the newest ready package is used even if it's outdated. It can be changed per link using build and verify
+// query parameters: polygon://key:secret@/?problemId=123&build=true&verify=false.
+func UsePackageBuild(opts BuildOptions) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.build = &opts
+	}
+}
+
+// types returns package types in order of preference
+func (policy PackagePolicy) types() []string {
+	if len(policy.Types) == 0 {
+		return []string{"windows", "linux", "standard"}
+	}
+
+	return policy.Types
+}
+
+// download returns preferred type the package can be downloaded as
+func (policy PackagePolicy) download(pack Package) (string, bool) {
+	for _, kind := range policy.types() {
+		if slices.Contains(packageTypes[pack.Type], kind) {
+			return kind, true
+		}
+	}
+
+	return "", false
+}
+
+// accepts returns true if package can be imported
+func (policy PackagePolicy) accepts(pack Package) bool {
+	if pack.State != "READY" {
+		return false
+	}
+
+	if policy.PackageID != 0 {
+		return pack.ID == policy.PackageID
+	}
+
+	_, ok := policy.download(pack)
+
+	return ok && pack.Revision >= policy.MinRevision
+}
+
+// pick returns the best package to import with type set to the one it must be downloaded as
+func (policy PackagePolicy) pick(packages []Package) *Package {
+	rank := func(pack Package) int {
+		kind, _ := policy.download(pack)
+		return slices.Index(policy.types(), kind)
+	}
+
+	var best *Package
+	for _, pack := range packages {
+		if !policy.accepts(pack) {
+			continue
+		}
+
+		if best == nil {
+			best = &pack
+			continue
+		}
+
+		a, b := rank(pack), rank(*best)
+
+		switch {
+		case !policy.Latest && a != b:
+			if a < b {
+				best = &pack
+			}
+		case pack.Revision != best.Revision:
+			if pack.Revision > best.Revision {
+				best = &pack
+			}
+		case a != b:
+			if a < b {
+				best = &pack
+			}
+		case pack.ID > best.ID:
+			best = &pack
+		}
+	}
+
+	// exact package may have none of the preferred types, it's downloaded as is then
+	if best != nil {
+		if kind, ok := policy.download(*best); ok {
+			best.Type = kind
+		}
+	}
+
+	return best
+}
+
+// packagePolicy returns package policy for the link
+func (p *ProblemLoader) packagePolicy(query url.Values) (PackagePolicy, error) {
+	policy := p.policy
+	policy.Types = slices.Clone(policy.Types)
+
+	if query.Has("types") {
+		policy.Types = nil
+		for _, kind := range strings.Split(query.Get("types"), ",") {
+			kind = strings.TrimSpace(kind)
+			if _, ok := packageTypes[kind]; !ok {
+				return policy, fmt.Errorf("invalid problem origin: package type %#v is not supported", kind)
+			}
+
+			policy.Types = append(policy.Types, kind)
+		}
+	}
+
+	for name, value := range map[string]*int{"packageId": &policy.PackageID, "minRevision": &policy.MinRevision} {
+		if !query.Has(name) {
+			continue
+		}
+
+		number, err := strconv.Atoi(query.Get(name))
+		if err != nil {
+			return policy, fmt.Errorf("invalid problem origin: query parameter %v must be a valid integer", name)
+		}
+
+		*value = number
+	}
+
+	if query.Has("latest") {
+		latest, err := strconv.ParseBool(query.Get("latest"))
+		if err != nil {
+			return policy, errors.New("invalid problem origin: query parameter latest must be a boolean")
+		}
+
+		policy.Latest = latest
+	}
+
+	return policy, nil
+}
+
+// buildOptions returns build options for the link, nil if package must not be built
+func (p *ProblemLoader) buildOptions(query url.Values) (*BuildOptions, error) {
+	build := p.build
+
+	if query.Has("build") {
+		enabled, err := strconv.ParseBool(query.Get("build"))
+		if err != nil {
+			return nil, errors.New("invalid problem origin: query parameter build must be a boolean")
+		}
+
+		switch {
+		case !enabled:
+			build = nil
+		case build == nil:
+			build = &BuildOptions{}
+		}
+	}
+
+	if query.Has("verify") && build != nil {
+		verify, err := strconv.ParseBool(query.Get("verify"))
+		if err != nil {
+			return nil, errors.New("invalid problem origin: query parameter verify must be a boolean")
+		}
+
+		opts := *build
+		opts.Verify = verify
+		build = &opts
+	}
+
+	return build, nil
+}
+
+// pickPackage to download according to the policy. If build options are given and there is no package for the latest
+// revision, a new package is built. Exact package is never built.
+func (p *ProblemLoader) pickPackage(ctx context.Context, poly *Client, problem int, policy PackagePolicy, build *BuildOptions) (*Package, error) {
+	packages, err := poly.ListPackages(ctx, ListPackagesInput{ProblemID: problem})
+	if err != nil {
+		return nil, err
+	}
+
+	pack := policy.pick(packages)
+
+	if build == nil || policy.PackageID != 0 {
+		if pack == nil {
+			return nil, errors.New("no suitable packages")
+		}
+
+		return pack, nil
+	}
+
+	revision, err := p.revision(ctx, poly, problem)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find latest revision: %w", err)
+	}
+
+	if policy.MinRevision > revision {
+		return nil, fmt.Errorf("minimum revision %v is greater than the latest revision %v", policy.MinRevision, revision)
+	}
+
+	if pack != nil && pack.Revision >= revision {
+		return pack, nil
+	}
+
+	// wait for a build which is already running, instead of starting a new one
+	pending := newest(packages, func(pack Package) bool {
+		return pack.Revision >= revision && (pack.State == "PENDING" || pack.State == "RUNNING")
+	})
+
+	if pending == nil {
+		p.log.Printf("Building package for revision %v (verify=%v)", revision, build.Verify)
+
+		err := poly.BuildPackage(ctx, BuildPackageInput{ProblemID: problem, Full: true, Verify: build.Verify})
+
+		// building requires write access, outdated package is better than nothing for read-only keys
+		if errors.Is(err, ErrPermission) && pack != nil {
+			p.log.Errorf("Unable to build package, using package %v for revision %v instead: %v", pack.ID, pack.Revision, err)
+			return pack, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to build package: %w", err)
+		}
+	}
+
+	return p.waitPackage(ctx, poly, problem, revision, packages, policy, build)
+}
+
+// waitPackage polls package list until a package for the revision is built
+func (p *ProblemLoader) waitPackage(ctx context.Context, poly *Client, problem, revision int, known []Package, policy PackagePolicy, build *BuildOptions) (*Package, error) {
+	timeout, interval := build.Timeout, build.Interval
+	if timeout <= 0 {
+		timeout = 30 * time.Minute
+	}
+
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("package for revision %v is not ready after %v: %w", revision, time.Since(start).Round(time.Second), ctx.Err())
+		case <-ticker.C:
+		}
+
+		packages, err := poly.ListPackages(ctx, ListPackagesInput{ProblemID: problem})
+		if errors.Is(err, ErrAuth) || errors.Is(err, ErrPermission) || errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("unable to check package state: %w", err)
+		}
+
+		if err != nil {
+			p.log.Errorf("Unable to check package state: %v", err)
+			continue
+		}
+
+		pack := newest(packages, func(pack Package) bool {
+			return pack.Revision >= revision && (pack.State != "READY" || policy.accepts(pack)) && !slices.ContainsFunc(known, func(k Package) bool {
+				return k.ID == pack.ID && k.State == pack.State
+			})
+		})
+
+		if pack == nil {
+			p.log.Printf("Waiting for package build to start (%v)", time.Since(start).Round(time.Second))
+			continue
+		}
+
+		switch pack.State {
+		case "READY":
+			p.log.Printf("Package %v for revision %v is ready, built in %v", pack.ID, pack.Revision, time.Since(start).Round(time.Second))
+			pack.Type, _ = policy.download(*pack)
+			return pack, nil
+		case "FAILED":
+			return nil, fmt.Errorf("package %v for revision %v has failed to build: %v", pack.ID, pack.Revision, pack.Comment)
+		default:
+			p.log.Printf("Package %v is %v (%v)", pack.ID, pack.State, time.Since(start).Round(time.Second))
+		}
+	}
+}
+
+// revision returns the latest revision of the problem
+func (p *ProblemLoader) revision(ctx context.Context, poly *Client, problem int) (int, error) {
+	problems, err := poly.ListProblems(ctx, ListProblemsInput{ID: problem})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, item := range problems {
+		if item.ID == problem {
+			return item.Revision, nil
+		}
+	}
+
+	return 0, fmt.Errorf("problem %v is not found", problem)
+}
+
+// newest returns package with the highest revision (and ID) which matches the filter
+func newest(packages []Package, filter func(Package) bool) *Package {
+	var best *Package
+	for i, pack := range packages {
+		if !filter(pack) {
+			continue
+		}
+
+		if best == nil || pack.Revision > best.Revision || pack.Revision == best.Revision && pack.ID > best.ID {
+			best = &packages[i]
+		}
+	}
+
+	return best
+}
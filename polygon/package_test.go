@@ -0,0 +1,269 @@
+package polygon
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/eolymp/go-problems/connector/testing"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProblemLoader_pickPackage(t *testing.T) {
+	ctx := context.Background()
+	loader := NewProblemLoader(MockUploader(), MockLogger(t))
+
+	stub, poly := newPolygonStub(t)
+	stub.results["problem.packages"] = []Package{
+		{ID: 1, Revision: 1, State: "READY", Type: "windows"},
+		{ID: 3, Revision: 2, State: "READY", Type: "linux"},
+		{ID: 2, Revision: 2, State: "READY", Type: "windows"},
+		{ID: 4, Revision: 3, State: "FAILED", Type: "windows"},
+	}
+
+	pack, err := loader.pickPackage(ctx, poly, 1, PackagePolicy{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pack.ID != 2 {
+		t.Errorf("Newest ready windows package must be picked, got %v", pack.ID)
+	}
+
+	stub.results["problem.packages"] = []Package{{ID: 1, Revision: 1, State: "RUNNING", Type: "windows"}}
+
+	if _, err := loader.pickPackage(ctx, poly, 1, PackagePolicy{}, nil); err == nil {
+		t.Error("Picking package must fail without ready packages")
+	}
+}
+
+func TestProblemLoader_pickPackage_readOnly(t *testing.T) {
+	ctx := context.Background()
+	loader := NewProblemLoader(MockUploader(), MockLogger(t))
+
+	stub, poly := newPolygonStub(t)
+	stub.results["problems.list"] = []Problem{{ID: 1, Revision: 3}}
+	stub.results["problem.packages"] = []Package{{ID: 1, Revision: 2, State: "READY", Type: "windows"}}
+	stub.fail["problem.buildPackage"] = "problemId: You don't have WRITE access to the problem"
+
+	pack, err := loader.pickPackage(ctx, poly, 1, PackagePolicy{}, &BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pack.ID != 1 {
+		t.Errorf("Outdated package must be used when package can't be built, got %v", pack.ID)
+	}
+
+	stub.results["problem.packages"] = []Package{}
+
+	if _, err := loader.pickPackage(ctx, poly, 1, PackagePolicy{}, &BuildOptions{}); !errors.Is(err, ErrPermission) {
+		t.Errorf("Permission error must be returned when there is no package to fall back to, got %v", err)
+	}
+}
+
+func TestPackagePolicy_pick(t *testing.T) {
+	packages := []Package{
+		{ID: 1, Revision: 1, State: "READY", Type: "windows"},
+		{ID: 2, Revision: 2, State: "READY", Type: "linux"},
+		{ID: 3, Revision: 3, State: "READY", Type: "standard"},
+		{ID: 4, Revision: 4, State: "FAILED", Type: "windows"},
+	}
+
+	tests := map[string]struct {
+		policy PackagePolicy
+		want   *Package
+	}{
+		"default":        {want: &Package{ID: 1, Revision: 1, State: "READY", Type: "windows"}},
+		"latest":         {policy: PackagePolicy{Latest: true}, want: &Package{ID: 3, Revision: 3, State: "READY", Type: "standard"}},
+		"types":          {policy: PackagePolicy{Types: []string{"linux", "windows"}}, want: &Package{ID: 2, Revision: 2, State: "READY", Type: "linux"}},
+		"download as":    {policy: PackagePolicy{Types: []string{"standard"}, MinRevision: 1}, want: &Package{ID: 3, Revision: 3, State: "READY", Type: "standard"}},
+		"downgraded":     {policy: PackagePolicy{Types: []string{"linux"}, MinRevision: 1}, want: &Package{ID: 2, Revision: 2, State: "READY", Type: "linux"}},
+		"min revision":   {policy: PackagePolicy{MinRevision: 2}, want: &Package{ID: 2, Revision: 2, State: "READY", Type: "linux"}},
+		"exact package":  {policy: PackagePolicy{PackageID: 2, Types: []string{"windows"}}, want: &Package{ID: 2, Revision: 2, State: "READY", Type: "linux"}},
+		"failed package": {policy: PackagePolicy{PackageID: 4}},
+		"no match":       {policy: PackagePolicy{MinRevision: 5}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tc.policy.pick(packages)
+			if !cmp.Equal(tc.want, got) {
+				t.Errorf("Picked package does not match:\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestProblemLoader_packagePolicy(t *testing.T) {
+	loader := NewProblemLoader(MockUploader(), MockLogger(t), UsePackagePolicy(PackagePolicy{Types: []string{"linux"}, MinRevision: 3}))
+
+	query, _ := url.ParseQuery("problemId=1&types=standard,windows&packageId=7&latest=true")
+
+	got, err := loader.packagePolicy(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := PackagePolicy{Types: []string{"standard", "windows"}, PackageID: 7, MinRevision: 3, Latest: true}
+	if !cmp.Equal(want, got) {
+		t.Errorf("Package policy does not match:\n%s", cmp.Diff(want, got))
+	}
+
+	for _, invalid := range []string{"types=zip", "packageId=latest", "minRevision=", "latest=maybe"} {
+		query, _ := url.ParseQuery(invalid)
+		if _, err := loader.packagePolicy(query); err == nil {
+			t.Errorf("Query %#v must be rejected", invalid)
+		}
+	}
+}
+
+func TestProblemLoader_pickPackage_build(t *testing.T) {
+	ctx := context.Background()
+	loader := NewProblemLoader(MockUploader(), MockLogger(t))
+
+	stub, poly := newPolygonStub(t)
+
+	packages := []Package{{ID: 1, Revision: 2, State: "READY", Type: "windows"}}
+	states := []string{"PENDING", "RUNNING", "READY"}
+
+	stub.results["problems.list"] = []Problem{{ID: 1, Revision: 3}}
+	stub.results["problem.buildPackage"] = nil
+	stub.results["problem.packages"] = func() any {
+		if len(stub.calls) < 4 { // build has not been requested yet
+			return packages
+		}
+
+		state := states[0]
+		if len(states) > 1 {
+			states = states[1:]
+		}
+
+		return append(packages, Package{ID: 2, Revision: 3, State: state, Type: "windows"})
+	}
+
+	pack, err := loader.pickPackage(ctx, poly, 1, PackagePolicy{}, &BuildOptions{Verify: true, Interval: time.Millisecond, Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pack.ID != 2 || pack.State != "READY" {
+		t.Errorf("Built package must be picked, got %v in state %v", pack.ID, pack.State)
+	}
+
+	build := stub.calls[2]
+	if build.Get("full") != "true" || build.Get("verify") != "true" {
+		t.Errorf("Package must be built in full with verification, got %v", build)
+	}
+}
+
+func TestProblemLoader_pickPackage_running(t *testing.T) {
+	ctx := context.Background()
+	loader := NewProblemLoader(MockUploader(), MockLogger(t))
+
+	stub, poly := newPolygonStub(t)
+
+	state := "RUNNING"
+
+	stub.results["problems.list"] = []Problem{{ID: 1, Revision: 3}}
+	stub.results["problem.packages"] = func() any {
+		defer func() { state = "READY" }()
+		return []Package{{ID: 1, Revision: 3, State: state, Type: "windows"}}
+	}
+
+	pack, err := loader.pickPackage(ctx, poly, 1, PackagePolicy{}, &BuildOptions{Interval: time.Millisecond, Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pack.ID != 1 {
+		t.Errorf("Running build must be awaited, got package %v", pack.ID)
+	}
+
+	for _, call := range stub.calls {
+		if _, ok := call["full"]; ok {
+			t.Error("Package must not be built while another build is running")
+		}
+	}
+}
+
+func TestProblemLoader_pickPackage_failed(t *testing.T) {
+	ctx := context.Background()
+	loader := NewProblemLoader(MockUploader(), MockLogger(t))
+
+	stub, poly := newPolygonStub(t)
+	stub.results["problems.list"] = []Problem{{ID: 1, Revision: 3}}
+	stub.results["problem.buildPackage"] = nil
+	stub.results["problem.packages"] = func() any {
+		if len(stub.calls) < 4 {
+			return []Package{}
+		}
+
+		return []Package{{ID: 2, Revision: 3, State: "FAILED", Type: "windows", Comment: "validator failed"}}
+	}
+
+	if _, err := loader.pickPackage(ctx, poly, 1, PackagePolicy{}, &BuildOptions{Interval: time.Millisecond, Timeout: time.Second}); err == nil {
+		t.Error("Picking package must fail when build fails")
+	}
+}
+
+func TestProblemLoader_pickPackage_timeout(t *testing.T) {
+	ctx := context.Background()
+	loader := NewProblemLoader(MockUploader(), MockLogger(t))
+
+	stub, poly := newPolygonStub(t)
+	stub.results["problems.list"] = []Problem{{ID: 1, Revision: 3}}
+	stub.results["problem.buildPackage"] = nil
+	stub.results["problem.packages"] = func() any {
+		if len(stub.calls) < 4 {
+			return []Package{}
+		}
+
+		return []Package{{ID: 2, Revision: 3, State: "RUNNING", Type: "windows"}}
+	}
+
+	if _, err := loader.pickPackage(ctx, poly, 1, PackagePolicy{}, &BuildOptions{Interval: time.Millisecond, Timeout: 50 * time.Millisecond}); err == nil {
+		t.Error("Picking package must fail when build takes too long")
+	}
+}
+
+func TestProblemLoader_buildOptions(t *testing.T) {
+	defaults := &BuildOptions{Verify: true, Timeout: time.Minute}
+
+	tests := map[string]struct {
+		opts  []func(*ProblemLoader)
+		query string
+		want  *BuildOptions
+	}{
+		"disabled":          {query: "problemId=1"},
+		"enabled by link":   {query: "problemId=1&build=true", want: &BuildOptions{}},
+		"verify by link":    {query: "problemId=1&build=1&verify=true", want: &BuildOptions{Verify: true}},
+		"enabled by loader": {opts: []func(*ProblemLoader){UsePackageBuild(*defaults)}, query: "problemId=1", want: defaults},
+		"disabled by link":  {opts: []func(*ProblemLoader){UsePackageBuild(*defaults)}, query: "problemId=1&build=false"},
+		"verify override":   {opts: []func(*ProblemLoader){UsePackageBuild(*defaults)}, query: "verify=false", want: &BuildOptions{Timeout: time.Minute}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			loader := NewProblemLoader(MockUploader(), MockLogger(t), tc.opts...)
+
+			query, _ := url.ParseQuery(tc.query)
+
+			got, err := loader.buildOptions(query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(tc.want, got) {
+				t.Errorf("Build options do not match:\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+
+	loader := NewProblemLoader(MockUploader(), MockLogger(t))
+	if _, err := loader.buildOptions(url.Values{"build": {"maybe"}}); err == nil {
+		t.Error("Invalid build parameter must be rejected")
+	}
+}
@@ -1,28 +1,17 @@
 package polygon
 
+import (
+	"github.com/eolymp/go-problems/runtimes"
+)
+
 // LanguageMapping polygon to eolymp language name mapping
-var LanguageMapping = map[string]string{
-	"c":       "c",
-	"cpp":     "cpp",
-	"d":       "d",
-	"csharp":  "csharp",
-	"go":      "go",
-	"java":    "java",
-	"kotlin":  "kotlin",
-	"pas":     "pascal",
-	"php":     "php",
-	"py":      "python",
-	"python":  "python",
-	"ruby":    "ruby",
-	"rust":    "rust",
-	"haskell": "haskell",
-	"js":      "js",
-	"lua":     "lua",
-	"perl":    "perl",
-	"swift":   "swift",
-}
+//
+// Deprecated: use runtimes.Registry.ResolveLanguage, the mapping is derived from runtimes.Default().
+var LanguageMapping = runtimes.Default().Config().Formats["polygon"].Languages
 
 // ReverseLanguageMapping eolymp language to polygon name mapping
+//
+// Deprecated: use runtimes.Registry.ResolveLanguage.
 func ReverseLanguageMapping(t string) (string, bool) {
 	if v, ok := LanguageMapping[t]; ok {
 		return v, true
@@ -31,80 +20,53 @@ func ReverseLanguageMapping(t string) (string, bool) {
 }
 
 // LanguageExtensions eolymp language to file extension
-var LanguageExtensions = map[string]string{
-	"c":       "c",
-	"cpp":     "cpp",
-	"d":       "d",
-	"csharp":  "cs",
-	"go":      "go",
-	"java":    "java",
-	"kotlin":  "kt",
-	"pascal":  "pas",
-	"php":     "php",
-	"python":  "py",
-	"ruby":    "rb",
-	"rust":    "rs",
-	"haskell": "hs",
-	"js":      "js",
-	"lua":     "lua",
-	"perl":    "pl",
-	"swift":   "swift",
-}
+//
+// Deprecated: use runtimes.Language.Extension, the mapping is derived from runtimes.Default().
+var LanguageExtensions = func() map[string]string {
+	mapping := map[string]string{}
+	for _, lang := range runtimes.Default().Languages() {
+		mapping[lang.Name] = lang.Extension()
+	}
 
-// polygon to eolymp runtime name mapping
-var RuntimeMapping = map[string]string{
-	"c.gcc":                      "c:17-gnu10",
-	"cpp.g++":                    "cpp:17-gnu10",
-	"cpp.g++11":                  "cpp:17-gnu10",
-	"cpp.g++14":                  "cpp:17-gnu10",
-	"cpp.g++17":                  "cpp:17-gnu10",
-	"cpp.ms":                     "cpp:17-gnu10",
-	"cpp.msys2-mingw64-9-g++17":  "cpp:17-gnu10",
-	"cpp.g++20":                  "cpp:20-gnu14",
-	"cpp.gcc11-64-winlibs-g++20": "cpp:20-gnu14",
-	"cpp.gcc13-64-winlibs-g++20": "cpp:20-gnu14",
-	"cpp.gcc14-64-msys2-g++23":   "cpp:23-gnu14",
-	"csharp.mono":                "csharp:5-dotnet",
-	"d":                          "d:1-gdc",
-	"go":                         "go:1.20",
-	"java8":                      "java:1.8",
-	"java11":                     "java:1.17",
-	"java21":                     "java:1.21",
-	"kotlin":                     "kotlin:1.7",
-	"kotlin16":                   "kotlin:1.7",
-	"kotlin17":                   "kotlin:1.7",
-	"kotlin19":                   "kotlin:1.9",
-	"pas.dpr":                    "pascal:3.2",
-	"pas.fpc":                    "pascal:3.2",
-	"php.5":                      "php:7.4",
-	"python.2":                   "python:3-python",
-	"python.3":                   "python:3-python",
-	"python.pypy2":               "python:3-pypy",
-	"python.pypy3":               "python:3-pypy",
-	"python.pypy3-64":            "python:3-pypy",
-	"ruby":                       "ruby:2.4",
-	"ruby.2":                     "ruby:2.4",
-	"rust":                       "rust:1.78",
-}
+	return mapping
+}()
+
+// RuntimeMapping polygon to eolymp runtime name mapping
+//
+// Deprecated: use runtimes.Registry.Resolve, the mapping is derived from runtimes.Default().
+var RuntimeMapping = func() map[string]string {
+	registry := runtimes.Default()
+	format := registry.Config().Formats["polygon"]
+
+	mapping := map[string]string{}
+	for id := range format.Sources {
+		if res, ok := registry.Resolve("polygon", id); ok {
+			mapping[id] = res.Runtime.ID
+		}
+	}
+
+	for id := range format.Runtimes {
+		if res, ok := registry.Resolve("polygon", id); ok {
+			mapping[id] = res.Runtime.ID
+		}
+	}
+
+	return mapping
+}()
 
 // TemplateMapping eolymp language mapping to runtimes for templates
 // ie. what runtimes should template in given language be generated for
-var TemplateMapping = map[string][]string{
-	"c":       {"c:17-gnu10"},
-	"cpp":     {"cpp:11-gnu10", "cpp:17-gnu10", "cpp:17-gnu10-extra", "cpp:20-gnu10", "cpp:20-gnu10-extra", "cpp:20-gnu14", "cpp:20-gnu14-extra", "cpp:23-gnu10", "cpp:23-gnu10-extra", "cpp:23-gnu14", "cpp:23-gnu14-extra"},
-	"csharp":  {"csharp:5-dotnet", "csharp:5-mono"},
-	"d":       {"d:1-dmd", "d:1-gdc"},
-	"go":      {"go:1.20"},
-	"haskell": {"haskell:8.8-ghc"},
-	"java":    {"java:1.21"},
-	"js":      {"js:18"},
-	"kotlin":  {"kotlin:1.9"},
-	"lua":     {"lua:5.1"},
-	"pascal":  {"pascal:3.2"},
-	"perl":    {"perl:5.32"},
-	"php":     {"php:7.4"},
-	"python":  {"python:3.10-pypy", "python:3.10-pypy-extra", "python:3.11-ai", "python:3.11-python", "python:3.11-python-extra"},
-	"ruby":    {"ruby:2.4"},
-	"rust":    {"rust:1.78"},
-	"swift":   {"swift:5.6"},
-}
+//
+// Deprecated: use runtimes.Registry.Templates, the mapping is derived from runtimes.Default().
+var TemplateMapping = func() map[string][]string {
+	registry := runtimes.Default()
+
+	mapping := map[string][]string{}
+	for _, lang := range registry.Languages() {
+		if ids := registry.Templates(lang.Name); len(ids) > 0 {
+			mapping[lang.Name] = ids
+		}
+	}
+
+	return mapping
+}()
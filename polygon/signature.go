@@ -3,18 +3,48 @@ package polygon
 import (
 	"crypto/sha512"
 	"fmt"
-	"math/rand"
+	"math/rand/v2"
 	"net/url"
-	"time"
+	"sort"
+	"strings"
 )
 
-var rnd = rand.New(rand.NewSource(time.Now().Unix()))
-
+// Signature signs API request, it's safe for concurrent use.
 func Signature(method string, secret string, params url.Values) string {
-	salt := fmt.Sprint(100000 + rnd.Int()%899999)
+	salt := fmt.Sprint(100000 + rand.IntN(899999))
 
 	hash := sha512.New()
-	hash.Write([]byte(salt + "/" + method + "?" + params.Encode() + "#" + secret))
+	hash.Write([]byte(salt + "/" + method + "?" + canonical(params) + "#" + secret))
 
 	return salt + fmt.Sprintf("%x", hash.Sum(nil))
 }
+
+// canonical joins parameters sorted by name and value as Polygon does when it checks the signature, values are not
+// escaped
+func canonical(params url.Values) string {
+	var pairs [][2]string
+	for name, values := range params {
+		for _, value := range values {
+			pairs = append(pairs, [2]string{name, value})
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+
+		return pairs[i][1] < pairs[j][1]
+	})
+
+	var b strings.Builder
+	for i, pair := range pairs {
+		if i > 0 {
+			b.WriteByte('&')
+		}
+
+		b.WriteString(pair[0] + "=" + pair[1])
+	}
+
+	return b.String()
+}
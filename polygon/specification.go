@@ -3,6 +3,9 @@ package polygon
 import "strings"
 
 type Specification struct {
+	Revision    int                       `xml:"revision,attr"`
+	ShortName   string                    `xml:"short-name,attr"`
+	URL         string                    `xml:"url,attr"`
 	Names       []SpecificationName       `xml:"names>name"`
 	Statements  []SpecificationStatement  `xml:"statements>statement"`
 	Tutorials   []SpecificationTutorial   `xml:"tutorials>tutorial"`
@@ -168,3 +171,28 @@ type ProblemProperties struct {
 	AuthorName  string `json:"authorName"`
 	Solution    string `json:"tutorial"`
 }
+
+// ContestSpecification is contest.xml of the Polygon contest package.
+type ContestSpecification struct {
+	URL        string                          `xml:"url,attr"`
+	Names      []ContestSpecificationName      `xml:"names>name"`
+	Statements []ContestSpecificationStatement `xml:"statements>statement"`
+	Problems   []ContestSpecificationProblem   `xml:"problems>problem"`
+}
+
+type ContestSpecificationName struct {
+	Language string `xml:"language,attr"`
+	Main     bool   `xml:"main,attr"`
+	Value    string `xml:"value,attr"`
+}
+
+type ContestSpecificationStatement struct {
+	Language string `xml:"language,attr"`
+	Path     string `xml:"path,attr"`
+	Type     string `xml:"type,attr"`
+}
+
+type ContestSpecificationProblem struct {
+	Index string `xml:"index,attr"`
+	URL   string `xml:"url,attr"`
+}
@@ -0,0 +1,101 @@
+package polygon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eolymp/go-problems/connector"
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+)
+
+// TestsetRole defines how a Polygon testset is imported.
+type TestsetRole string
+
+const (
+	TestsetFinal    TestsetRole = "final"    // scored tests, groups become testsets starting from index 0, at most one testset can be final
+	TestsetPretests TestsetRole = "pretests" // tests without score, appended after the final testsets, examples are not taken from them
+	TestsetSkip     TestsetRole = "skip"     // testset is not imported
+)
+
+// ParseTestsetRole checks if testset role is supported.
+func ParseTestsetRole(role string) (TestsetRole, error) {
+	switch r := TestsetRole(role); r {
+	case TestsetFinal, TestsetPretests, TestsetSkip:
+		return r, nil
+	default:
+		return "", fmt.Errorf("testset role %#v is not supported", role)
+	}
+}
+
+// UseTestsetRoles sets roles of Polygon testsets by their names (case-insensitive). By default, testset "tests" (or
+// the first testset if there is no such testset) is final and all other testsets, e.g. "pretests", are pretests.
+func UseTestsetRoles(roles map[string]TestsetRole) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.roles = map[string]TestsetRole{}
+		for name, role := range roles {
+			p.roles[strings.ToLower(name)] = role
+		}
+	}
+}
+
+// assignment is a Polygon testset with the role it's imported in
+type assignment struct {
+	testset SpecificationTestset
+	role    TestsetRole
+}
+
+// testsetRoles assigns roles to the Polygon testsets, final testset goes first and the others keep their order
+func (p *ProblemLoader) testsetRoles(spec *Specification) ([]assignment, error) {
+	main := p.pickTestset(spec).Name
+
+	var final, other []assignment
+	for _, set := range spec.Judging.Testsets {
+		role, ok := p.roles[strings.ToLower(set.Name)]
+		if !ok {
+			role = TestsetPretests
+			if set.Name == main {
+				role = TestsetFinal
+			}
+		}
+
+		switch role {
+		case TestsetFinal:
+			final = append(final, assignment{testset: set, role: role})
+		case TestsetPretests, TestsetSkip:
+			other = append(other, assignment{testset: set, role: role})
+		default:
+			return nil, fmt.Errorf("testset %#v has unsupported role %#v", set.Name, role)
+		}
+	}
+
+	if len(final) > 1 {
+		return nil, fmt.Errorf("testsets %#v and %#v are both final, only one testset can be final", final[0].testset.Name, final[1].testset.Name)
+	}
+
+	return append(final, other...), nil
+}
+
+// testsetReport describes how the Polygon testset is imported, so the decision is visible in the import result
+func testsetReport(name string, role TestsetRole, testsets []*atlaspb.Testset) connector.Warning {
+	var indexes string
+	switch len(testsets) {
+	case 0:
+		indexes = "no testsets"
+	case 1:
+		indexes = fmt.Sprintf("testset %v", testsets[0].GetIndex())
+	default:
+		indexes = fmt.Sprintf("testsets %v-%v", testsets[0].GetIndex(), testsets[len(testsets)-1].GetIndex())
+	}
+
+	var message string
+	switch role {
+	case TestsetFinal:
+		message = fmt.Sprintf("testset %#v is imported as final tests into %v", name, indexes)
+	case TestsetPretests:
+		message = fmt.Sprintf("testset %#v is imported as pretests without score into %v", name, indexes)
+	default:
+		message = fmt.Sprintf("testset %#v is skipped", name)
+	}
+
+	return connector.Warning{Kind: "testset-" + string(role), Message: message}
+}
@@ -0,0 +1,313 @@
+package polygon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/eolymp/go-problems/connector"
+	"github.com/eolymp/go-problems/diff"
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// WatchState is what Watcher remembers about a problem between checks.
+type WatchState struct {
+	Revision int               // revision of the last imported snapshot
+	Snapshot *atlaspb.Snapshot // last imported snapshot, changes are computed against it
+}
+
+// StateStore keeps state of the watched problems, so Watcher does not re-import problems after restart.
+type StateStore interface {
+	// Load returns state of the problem, or nil if problem has never been imported.
+	Load(ctx context.Context, problem int) (*WatchState, error)
+	// Save replaces state of the problem.
+	Save(ctx context.Context, problem int, state *WatchState) error
+}
+
+// Update is emitted by Watcher when a problem is re-imported because its revision has advanced.
+type Update struct {
+	ProblemID int
+	Previous  int               // previously imported revision, zero if problem is imported for the first time
+	Revision  int               // imported revision
+	Result    *connector.Result // imported snapshot and its provenance
+	Diff      *diff.Diff        // changes since the previous import, nil if problem is imported for the first time
+}
+
+// Watcher periodically checks revisions of Polygon problems and re-imports those which have changed.
+//
+// Problems are given as polygon:// links, the same ones ProblemLoader imports. For links in API mode and for links
+// which build packages, the revision of the problem is watched. Otherwise, the revision of the package the loader
+// would import is watched, so problem is re-imported once a package for the new revision is built.
+type Watcher struct {
+	loader   *ProblemLoader
+	store    StateStore
+	log      connector.Logger
+	interval time.Duration
+	hasher   diff.Hasher
+}
+
+// UseWatchInterval sets how often problems are checked, 5 minutes by default.
+func UseWatchInterval(interval time.Duration) func(*Watcher) {
+	return func(w *Watcher) {
+		w.interval = interval
+	}
+}
+
+// UseWatchHasher sets how content of tests and other assets is hashed to compare snapshots, by default assets are
+// downloaded over HTTP, since re-imported assets are uploaded under new links.
+func UseWatchHasher(hasher diff.Hasher) func(*Watcher) {
+	return func(w *Watcher) {
+		w.hasher = hasher
+	}
+}
+
+func NewWatcher(loader *ProblemLoader, store StateStore, log connector.Logger, opts ...func(*Watcher)) *Watcher {
+	w := &Watcher{
+		loader:   loader,
+		store:    store,
+		log:      log,
+		interval: 5 * time.Minute,
+		hasher:   diff.HTTPHasher{},
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// Run checks problems periodically until the context is cancelled. Failures are logged and the problem is checked
+// again on the next round.
+func (w *Watcher) Run(ctx context.Context, links []string, handle func(context.Context, Update) error) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Check(ctx, links, handle); err != nil && ctx.Err() == nil {
+			w.log.Errorf("Unable to sync problems: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check compares revisions of the problems with the stored ones once. Changed problems are re-imported and passed to
+// the handler, state is saved only if the handler succeeds, so a failed update is emitted again on the next check.
+func (w *Watcher) Check(ctx context.Context, links []string, handle func(context.Context, Update) error) error {
+	var errs []error
+
+	for _, link := range links {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := w.check(ctx, link, handle); err != nil {
+			errs = append(errs, fmt.Errorf("problem %v: %w", connector.Redact(link), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (w *Watcher) check(ctx context.Context, link string, handle func(context.Context, Update) error) error {
+	problem, revision, err := w.revision(ctx, link)
+	if err != nil {
+		return fmt.Errorf("unable to check revision: %w", err)
+	}
+
+	state, err := w.store.Load(ctx, problem)
+	if err != nil {
+		return fmt.Errorf("unable to load state: %w", err)
+	}
+
+	if state != nil && state.Revision >= revision {
+		return nil
+	}
+
+	update := Update{ProblemID: problem, Revision: revision}
+	if state != nil {
+		update.Previous = state.Revision
+	}
+
+	w.log.Printf("Problem %v has changed (revision %v → %v), importing", problem, update.Previous, revision)
+
+	update.Result, err = w.loader.Import(ctx, link)
+	if err != nil {
+		return err
+	}
+
+	// package may be newer than the one seen during the check
+	if imported, err := strconv.Atoi(update.Result.Provenance.Revision); err == nil && imported > revision {
+		update.Revision = imported
+	}
+
+	if state != nil && state.Snapshot != nil {
+		update.Diff, err = diff.CompareContent(ctx, state.Snapshot, update.Result.Snapshot, w.hasher)
+		if err != nil {
+			w.log.Errorf("Unable to compare content of problem %v, comparing links instead: %v", problem, err)
+			update.Diff = diff.Compare(state.Snapshot, update.Result.Snapshot)
+		}
+	}
+
+	if err := handle(ctx, update); err != nil {
+		return fmt.Errorf("unable to handle update: %w", err)
+	}
+
+	if err := w.store.Save(ctx, problem, &WatchState{Revision: update.Revision, Snapshot: update.Result.Snapshot}); err != nil {
+		return fmt.Errorf("unable to save state: %w", err)
+	}
+
+	return nil
+}
+
+// revision returns problem ID and the revision which would be imported by the link
+func (w *Watcher) revision(ctx context.Context, link string) (int, int, error) {
+	origin, err := url.Parse(link)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid problem origin: %w", err)
+	}
+
+	if origin.Scheme != "polygon" {
+		return 0, 0, fmt.Errorf("invalid problem origin: schema %#v is not supported", origin.Scheme)
+	}
+
+	problem, err := strconv.Atoi(origin.Query().Get("problemId"))
+	if err != nil {
+		return 0, 0, errors.New("invalid problem origin: query parameter problemId must be a valid integer")
+	}
+
+	mode, err := w.loader.importMode(origin.Query())
+	if err != nil {
+		return 0, 0, err
+	}
+
+	policy, err := w.loader.packagePolicy(origin.Query())
+	if err != nil {
+		return 0, 0, err
+	}
+
+	build, err := w.loader.buildOptions(origin.Query())
+	if err != nil {
+		return 0, 0, err
+	}
+
+	secret, _ := origin.User.Password()
+	poly := New(origin.User.Username(), secret, w.loader.client...)
+
+	if mode == ModeAPI || build != nil && policy.PackageID == 0 {
+		revision, err := w.loader.revision(ctx, poly, problem)
+		return problem, revision, err
+	}
+
+	packages, err := poly.ListPackages(ctx, ListPackagesInput{ProblemID: problem})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	pack := policy.pick(packages)
+	if pack == nil {
+		return 0, 0, errors.New("no suitable packages")
+	}
+
+	return problem, pack.Revision, nil
+}
+
+// FileStore keeps state of each problem in a JSON file named after the problem ID.
+type FileStore struct {
+	path string
+}
+
+// NewFileStore creates store in the directory, the directory is created if it does not exist.
+func NewFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(path, 0777); err != nil {
+		return nil, fmt.Errorf("unable to create state directory: %w", err)
+	}
+
+	return &FileStore{path: path}, nil
+}
+
+// fileState is the format of the state file
+type fileState struct {
+	Revision int             `json:"revision"`
+	Snapshot json.RawMessage `json:"snapshot,omitempty"`
+}
+
+func (s *FileStore) Load(_ context.Context, problem int) (*WatchState, error) {
+	data, err := os.ReadFile(s.name(problem))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var file fileState
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("unable to parse state file: %w", err)
+	}
+
+	state := &WatchState{Revision: file.Revision}
+
+	if len(file.Snapshot) > 0 {
+		state.Snapshot = &atlaspb.Snapshot{}
+		if err := protojson.Unmarshal(file.Snapshot, state.Snapshot); err != nil {
+			return nil, fmt.Errorf("unable to parse snapshot: %w", err)
+		}
+	}
+
+	return state, nil
+}
+
+func (s *FileStore) Save(_ context.Context, problem int, state *WatchState) error {
+	file := fileState{Revision: state.Revision}
+
+	if state.Snapshot != nil {
+		data, err := protojson.Marshal(state.Snapshot)
+		if err != nil {
+			return fmt.Errorf("unable to serialize snapshot: %w", err)
+		}
+
+		file.Snapshot = data
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// write into a temporary file first, so state is not corrupted if the process is interrupted
+	tmp, err := os.CreateTemp(s.path, "state-*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.name(problem))
+}
+
+func (s *FileStore) name(problem int) string {
+	return filepath.Join(s.path, strconv.Itoa(problem)+".json")
+}
@@ -0,0 +1,178 @@
+package polygon
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	. "github.com/eolymp/go-problems/connector/testing"
+	"github.com/eolymp/go-problems/diff"
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWatcher_Check(t *testing.T) {
+	ctx := context.Background()
+	stub, client := newPolygonStub(t)
+
+	stub.results["problems.list"] = []Problem{{ID: 1, Name: "a-plus-b", Revision: 5}}
+	stub.results["problem.info"] = ProblemInfo{InputFile: "stdin", OutputFile: "stdout", TimeLimit: 1000, MemoryLimit: 256}
+	stub.results["problem.statements"] = map[string]Statement{"english": {Name: "A+B", Legend: "Sum two numbers"}}
+	stub.results["problem.statementResources"] = []File{}
+	stub.results["problem.files"] = Files{SourceFiles: []File{{Name: "check.cpp", SourceType: "cpp.g++17"}}}
+	stub.results["problem.checker"] = "check.cpp"
+	stub.results["problem.validator"] = ""
+	stub.results["problem.interactor"] = ""
+	stub.results["problem.solutions"] = []Solution{}
+	stub.results["problem.tests"] = []Test{{Index: 1, Manual: true}}
+	stub.results["problem.viewTags"] = []string{}
+	stub.raw["problem.viewFile"] = "int main() {}"
+	stub.raw["problem.testInput"] = "1 2\n"
+	stub.raw["problem.testAnswer"] = "3\n"
+
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal("Unable to create store:", err)
+	}
+
+	loader := NewProblemLoader(MockUploader(), MockLogger(t), UsePolygonOptions(UseBaseURL(client.base)))
+	watcher := NewWatcher(loader, store, MockLogger(t), UseWatchHasher(linkHasher{}))
+
+	link := url.URL{Scheme: "polygon", User: url.UserPassword("key", "secret"), Path: "/", RawQuery: "problemId=1&mode=api"}
+
+	var updates []Update
+	handle := func(ctx context.Context, update Update) error {
+		updates = append(updates, update)
+		return nil
+	}
+
+	// first check imports the problem
+	if err := watcher.Check(ctx, []string{link.String()}, handle); err != nil {
+		t.Fatal("Check has failed:", err)
+	}
+
+	if len(updates) != 1 || updates[0].ProblemID != 1 || updates[0].Previous != 0 || updates[0].Revision != 5 || updates[0].Diff != nil {
+		t.Fatalf("Problem must be imported for the first time, got %+v", updates)
+	}
+
+	// revision is the same, nothing is imported
+	if err := watcher.Check(ctx, []string{link.String()}, handle); err != nil {
+		t.Fatal("Check has failed:", err)
+	}
+
+	if len(updates) != 1 {
+		t.Fatalf("Problem must not be imported again, got %v updates", len(updates))
+	}
+
+	// revision advances, failed handler makes update to be emitted again
+	stub.results["problems.list"] = []Problem{{ID: 1, Name: "a-plus-b", Revision: 7}}
+	stub.results["problem.statements"] = map[string]Statement{"english": {Name: "A plus B", Legend: "Sum two numbers"}}
+
+	failing := func(ctx context.Context, update Update) error {
+		return errors.New("unavailable")
+	}
+
+	if err := watcher.Check(ctx, []string{link.String()}, failing); err == nil {
+		t.Fatal("Check must fail when handler fails")
+	}
+
+	if err := watcher.Check(ctx, []string{link.String()}, handle); err != nil {
+		t.Fatal("Check has failed:", err)
+	}
+
+	if len(updates) != 2 {
+		t.Fatalf("Problem must be imported again, got %v updates", len(updates))
+	}
+
+	update := updates[1]
+	if update.Previous != 5 || update.Revision != 7 || update.Diff == nil {
+		t.Fatalf("Update must describe revision change, got %+v", update)
+	}
+
+	want := []diff.Change{{Kind: diff.Changed, Section: "statement", Key: "en", Field: "title", Old: "A+B", New: "A plus B"}}
+	if got := update.Diff.Changes; !cmp.Equal(want, got) {
+		t.Errorf("Changes do not match:\n%s", cmp.Diff(want, got))
+	}
+
+	state, err := store.Load(ctx, 1)
+	if err != nil {
+		t.Fatal("Unable to load state:", err)
+	}
+
+	if state.Revision != 7 || state.Snapshot.GetStatements()[0].GetTitle() != "A plus B" {
+		t.Errorf("State must be saved, got %v", state)
+	}
+}
+
+// linkHasher uses links as digests, links of MockUploader depend on content only
+type linkHasher struct{}
+
+func (linkHasher) Hash(ctx context.Context, link string) (string, error) {
+	return link, nil
+}
+
+func TestWatcher_revision(t *testing.T) {
+	ctx := context.Background()
+	stub, client := newPolygonStub(t)
+
+	stub.results["problems.list"] = []Problem{{ID: 1, Revision: 9}}
+	stub.results["problem.packages"] = []Package{
+		{ID: 3, Revision: 7, State: "READY", Type: "windows"},
+		{ID: 4, Revision: 8, State: "READY", Type: "standard"},
+		{ID: 5, Revision: 9, State: "RUNNING", Type: "windows"},
+	}
+
+	loader := NewProblemLoader(MockUploader(), MockLogger(t), UsePolygonOptions(UseBaseURL(client.base)))
+	watcher := NewWatcher(loader, nil, MockLogger(t))
+
+	tests := map[string]int{
+		"problemId=1":                           7,
+		"problemId=1&latest=true":               8,
+		"problemId=1&build=true":                9,
+		"problemId=1&mode=api":                  9,
+		"problemId=1&packageId=4&types=windows": 8,
+	}
+
+	for query, want := range tests {
+		link := url.URL{Scheme: "polygon", User: url.UserPassword("key", "secret"), Path: "/", RawQuery: query}
+
+		problem, got, err := watcher.revision(ctx, link.String())
+		if err != nil {
+			t.Errorf("%v: unable to check revision: %v", query, err)
+			continue
+		}
+
+		if problem != 1 || got != want {
+			t.Errorf("%v: revision must be %v, got %v", query, want, got)
+		}
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal("Unable to create store:", err)
+	}
+
+	if state, err := store.Load(ctx, 1); err != nil || state != nil {
+		t.Fatalf("Unknown problem must have no state, got %v (%v)", state, err)
+	}
+
+	snap := &atlaspb.Snapshot{Problem: &atlaspb.Problem{Topics: []string{"x"}}}
+
+	if err := store.Save(ctx, 1, &WatchState{Revision: 3, Snapshot: snap}); err != nil {
+		t.Fatal("Unable to save state:", err)
+	}
+
+	state, err := store.Load(ctx, 1)
+	if err != nil {
+		t.Fatal("Unable to load state:", err)
+	}
+
+	if state.Revision != 3 || !cmp.Equal([]string{"x"}, state.Snapshot.GetProblem().GetTopics()) {
+		t.Errorf("State does not match, got %v", state)
+	}
+}
@@ -0,0 +1,684 @@
+// Package runtimes describes Eolymp languages and runtimes and maps runtime identifiers used by problem formats
+// (Polygon, Kattis) to Eolymp runtimes.
+//
+// Format runtime identifiers are described by language, version and flags (see Source). They are resolved to the
+// closest Eolymp runtime of the same language which is not older than the source, if there is no such runtime the
+// newest one is used and the resolution is reported as a downgrade.
+//
+// The default registry is embedded into the package (see registry.yaml), it can be extended or overridden using a
+// configuration file in the same format:
+//
+//	languages:
+//	  - {name: go, extensions: [go], runtime: "go:1.23"}
+//	runtimes:
+//	  - {id: "go:1.23", template: true}
+//	pins:
+//	  python: "python:3.11-python"
+//	formats:
+//	  polygon:
+//	    sources: {go: {language: go, version: "1.23"}}
+//	    runtimes: {cpp.ms: "cpp:17-gnu10"}
+//
+// Languages and runtimes are matched by name and ID, entries from the file replace default ones, new entries are
+// added. Pins and format mappings are merged key by key.
+package runtimes
+
+import (
+	"cmp"
+	_ "embed"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+//go:embed registry.yaml
+var defaults []byte
+
+// Language is an Eolymp programming language.
+type Language struct {
+	Name       string   `yaml:"name"`
+	Extensions []string `yaml:"extensions"` // source file extensions without dot, the first one is the primary one
+	Runtime    string   `yaml:"runtime"`    // runtime used when source version is unknown
+
+	// MajorIncompatible is set for languages where code written for one major version does not work in another,
+	// e.g. Python 2 and Python 3.
+	MajorIncompatible bool `yaml:"major_incompatible,omitempty"`
+}
+
+// Extension returns primary file extension of the language.
+func (l Language) Extension() string {
+	if len(l.Extensions) == 0 {
+		return ""
+	}
+
+	return l.Extensions[0]
+}
+
+// Runtime is an Eolymp runtime, e.g. cpp:17-gnu10 is C++ (language cpp) standard 17 (version) compiled with GNU 10
+// (flag).
+type Runtime struct {
+	ID       string   `yaml:"id"`
+	Language string   `yaml:"language,omitempty"` // parsed from ID if empty
+	Version  string   `yaml:"version,omitempty"`  // parsed from ID if empty
+	Flags    []string `yaml:"flags,omitempty"`    // parsed from ID if empty
+	Template bool     `yaml:"template,omitempty"` // templates are generated for this runtime
+}
+
+// ParseRuntime splits runtime ID into language, version and flags.
+func ParseRuntime(id string) Runtime {
+	rt := Runtime{ID: id}
+
+	lang, rest, ok := strings.Cut(id, ":")
+	rt.Language = lang
+
+	if ok && rest != "" {
+		parts := strings.Split(rest, "-")
+		rt.Version = parts[0]
+		rt.Flags = parts[1:]
+	}
+
+	return rt
+}
+
+// Has returns true if runtime has the flag.
+func (r Runtime) Has(flag string) bool {
+	return slices.Contains(r.Flags, flag)
+}
+
+// Source describes runtime a source file was written for: Eolymp language, language version (or standard) and
+// flags which must be present in the Eolymp runtime (e.g. pypy). Empty version means any.
+type Source struct {
+	Language string   `yaml:"language"`
+	Version  string   `yaml:"version,omitempty"`
+	Flags    []string `yaml:"flags,omitempty"`
+}
+
+func (s Source) String() string {
+	if s.Version == "" {
+		return s.Language
+	}
+
+	return s.Language + " " + s.Version
+}
+
+// Format describes how problem format refers to languages and runtimes.
+type Format struct {
+	Languages map[string]string `yaml:"languages,omitempty"` // format language name to Eolymp language
+	Sources   map[string]Source `yaml:"sources,omitempty"`   // format runtime identifier to source description
+	Runtimes  map[string]string `yaml:"runtimes,omitempty"`  // format runtime identifier to Eolymp runtime ID, has priority over sources
+}
+
+// Config is a serialized form of the registry.
+type Config struct {
+	Languages []Language        `yaml:"languages,omitempty"`
+	Runtimes  []Runtime         `yaml:"runtimes,omitempty"`
+	Pins      map[string]string `yaml:"pins,omitempty"` // language name to preferred runtime ID
+	Formats   map[string]Format `yaml:"formats,omitempty"`
+}
+
+// Status tells how the resolved runtime relates to the source version.
+type Status string
+
+const (
+	StatusUnknown      Status = "unknown"      // source version is not known
+	StatusExact        Status = "exact"        // runtime version matches the source
+	StatusUpgrade      Status = "upgrade"      // runtime is newer than the source
+	StatusDowngrade    Status = "downgrade"    // runtime is older than the source
+	StatusIncompatible Status = "incompatible" // runtime has a different major version which is not compatible
+)
+
+// Resolution is the Eolymp runtime picked for the source.
+type Resolution struct {
+	Source  Source
+	Runtime Runtime
+	Status  Status
+	Pinned  bool // runtime was pinned by configuration
+}
+
+// Warning returns description of the resolution if it may break the source (downgrade or incompatible version).
+func (r Resolution) Warning() (string, bool) {
+	switch r.Status {
+	case StatusDowngrade:
+		return fmt.Sprintf("resolved to %v which is older than %v", r.Runtime.ID, r.Source), true
+	case StatusIncompatible:
+		return fmt.Sprintf("resolved to %v which is not compatible with %v", r.Runtime.ID, r.Source), true
+	default:
+		return "", false
+	}
+}
+
+// Registry of languages and runtimes, it's immutable and safe for concurrent use.
+type Registry struct {
+	conf      Config
+	languages map[string]int // name to index in conf.Languages
+	runtimes  map[string]int // ID to index in conf.Runtimes
+	exts      map[string]string
+}
+
+var (
+	once     sync.Once
+	registry *Registry
+)
+
+// Default returns the registry embedded into the package.
+func Default() *Registry {
+	once.Do(func() {
+		r, err := Parse(defaults)
+		if err != nil {
+			panic(fmt.Errorf("embedded runtime registry is invalid: %w", err))
+		}
+
+		registry = r
+	})
+
+	return registry
+}
+
+// Parse creates registry from YAML (or JSON) configuration.
+func Parse(data []byte) (*Registry, error) {
+	conf := Config{}
+	if err := yaml.UnmarshalStrict(data, &conf); err != nil {
+		return nil, fmt.Errorf("unable to parse runtime registry: %w", err)
+	}
+
+	return New(conf)
+}
+
+// Load reads configuration file and applies it on top of the default registry.
+func Load(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read runtime registry: %w", err)
+	}
+
+	return Default().Override(data)
+}
+
+// New creates registry from configuration, it makes sure all references are valid.
+func New(conf Config) (*Registry, error) {
+	r := &Registry{
+		languages: map[string]int{},
+		runtimes:  map[string]int{},
+		exts:      map[string]string{},
+	}
+
+	for _, lang := range conf.Languages {
+		if lang.Name == "" {
+			return nil, fmt.Errorf("language name is required")
+		}
+
+		if _, ok := r.languages[lang.Name]; ok {
+			return nil, fmt.Errorf("language %#v is defined more than once", lang.Name)
+		}
+
+		lang.Extensions = slices.Clone(lang.Extensions)
+
+		r.languages[lang.Name] = len(r.conf.Languages)
+		r.conf.Languages = append(r.conf.Languages, lang)
+
+		for _, ext := range lang.Extensions {
+			if other, ok := r.exts[ext]; ok {
+				return nil, fmt.Errorf("extension %#v is used by languages %#v and %#v", ext, other, lang.Name)
+			}
+
+			r.exts[ext] = lang.Name
+		}
+	}
+
+	for _, rt := range conf.Runtimes {
+		if rt.ID == "" {
+			return nil, fmt.Errorf("runtime ID is required")
+		}
+
+		if _, ok := r.runtimes[rt.ID]; ok {
+			return nil, fmt.Errorf("runtime %#v is defined more than once", rt.ID)
+		}
+
+		parsed := ParseRuntime(rt.ID)
+		if rt.Language == "" {
+			rt.Language = parsed.Language
+		}
+
+		if rt.Version == "" {
+			rt.Version = parsed.Version
+		}
+
+		if rt.Flags == nil {
+			rt.Flags = parsed.Flags
+		}
+
+		rt.Flags = slices.Clone(rt.Flags)
+
+		if _, ok := r.languages[rt.Language]; !ok {
+			return nil, fmt.Errorf("runtime %#v refers to unknown language %#v", rt.ID, rt.Language)
+		}
+
+		r.runtimes[rt.ID] = len(r.conf.Runtimes)
+		r.conf.Runtimes = append(r.conf.Runtimes, rt)
+	}
+
+	for _, lang := range r.conf.Languages {
+		if lang.Runtime == "" {
+			continue
+		}
+
+		if rt, ok := r.Runtime(lang.Runtime); !ok || rt.Language != lang.Name {
+			return nil, fmt.Errorf("language %#v refers to unknown runtime %#v", lang.Name, lang.Runtime)
+		}
+	}
+
+	r.conf.Pins = map[string]string{}
+	for lang, runtime := range conf.Pins {
+		if rt, ok := r.Runtime(runtime); !ok || rt.Language != lang {
+			return nil, fmt.Errorf("language %#v is pinned to unknown runtime %#v", lang, runtime)
+		}
+
+		r.conf.Pins[lang] = runtime
+	}
+
+	r.conf.Formats = map[string]Format{}
+	for name, format := range conf.Formats {
+		for id, lang := range format.Languages {
+			if _, ok := r.languages[lang]; !ok {
+				return nil, fmt.Errorf("format %v: language %#v refers to unknown language %#v", name, id, lang)
+			}
+		}
+
+		for id, source := range format.Sources {
+			if _, ok := r.languages[source.Language]; !ok {
+				return nil, fmt.Errorf("format %v: source %#v refers to unknown language %#v", name, id, source.Language)
+			}
+		}
+
+		for id, runtime := range format.Runtimes {
+			if _, ok := r.runtimes[runtime]; !ok {
+				return nil, fmt.Errorf("format %v: runtime %#v refers to unknown runtime %#v", name, id, runtime)
+			}
+		}
+
+		r.conf.Formats[name] = format.clone()
+	}
+
+	return r, nil
+}
+
+// Override creates a new registry by applying configuration on top of this one.
+func (r *Registry) Override(data []byte) (*Registry, error) {
+	patch := Config{}
+	if err := yaml.UnmarshalStrict(data, &patch); err != nil {
+		return nil, fmt.Errorf("unable to parse runtime registry: %w", err)
+	}
+
+	conf := r.Config()
+
+	for _, lang := range patch.Languages {
+		if i := slices.IndexFunc(conf.Languages, func(l Language) bool { return l.Name == lang.Name }); i >= 0 {
+			conf.Languages[i] = lang
+		} else {
+			conf.Languages = append(conf.Languages, lang)
+		}
+	}
+
+	for _, rt := range patch.Runtimes {
+		if i := slices.IndexFunc(conf.Runtimes, func(x Runtime) bool { return x.ID == rt.ID }); i >= 0 {
+			conf.Runtimes[i] = rt
+		} else {
+			conf.Runtimes = append(conf.Runtimes, rt)
+		}
+	}
+
+	maps.Copy(conf.Pins, patch.Pins)
+
+	for name, format := range patch.Formats {
+		base := conf.Formats[name]
+		if base.Languages == nil {
+			base.Languages = map[string]string{}
+		}
+
+		if base.Sources == nil {
+			base.Sources = map[string]Source{}
+		}
+
+		if base.Runtimes == nil {
+			base.Runtimes = map[string]string{}
+		}
+
+		maps.Copy(base.Languages, format.Languages)
+		maps.Copy(base.Sources, format.Sources)
+		maps.Copy(base.Runtimes, format.Runtimes)
+
+		conf.Formats[name] = base
+	}
+
+	return New(conf)
+}
+
+// Config returns a copy of the registry configuration.
+func (r *Registry) Config() Config {
+	conf := Config{
+		Languages: slices.Clone(r.conf.Languages),
+		Runtimes:  slices.Clone(r.conf.Runtimes),
+		Pins:      maps.Clone(r.conf.Pins),
+		Formats:   map[string]Format{},
+	}
+
+	for name, format := range r.conf.Formats {
+		conf.Formats[name] = format.clone()
+	}
+
+	return conf
+}
+
+func (f Format) clone() Format {
+	return Format{Languages: maps.Clone(f.Languages), Sources: maps.Clone(f.Sources), Runtimes: maps.Clone(f.Runtimes)}
+}
+
+// Language returns Eolymp language by name.
+func (r *Registry) Language(name string) (Language, bool) {
+	i, ok := r.languages[name]
+	if !ok {
+		return Language{}, false
+	}
+
+	return r.conf.Languages[i], true
+}
+
+// LanguageByExtension returns Eolymp language by source file extension (without dot).
+func (r *Registry) LanguageByExtension(ext string) (Language, bool) {
+	return r.Language(r.exts[strings.ToLower(strings.TrimPrefix(ext, "."))])
+}
+
+// Languages returns all languages.
+func (r *Registry) Languages() []Language {
+	return slices.Clone(r.conf.Languages)
+}
+
+// Runtime returns Eolymp runtime by ID.
+func (r *Registry) Runtime(id string) (Runtime, bool) {
+	i, ok := r.runtimes[id]
+	if !ok {
+		return Runtime{}, false
+	}
+
+	return r.conf.Runtimes[i], true
+}
+
+// Runtimes returns all runtimes of the language, or all runtimes if language is empty.
+func (r *Registry) Runtimes(lang string) []Runtime {
+	var list []Runtime
+	for _, rt := range r.conf.Runtimes {
+		if lang == "" || rt.Language == lang {
+			list = append(list, rt)
+		}
+	}
+
+	return list
+}
+
+// Templates returns IDs of runtimes templates in the given language should be generated for.
+func (r *Registry) Templates(lang string) []string {
+	var ids []string
+	for _, rt := range r.conf.Runtimes {
+		if rt.Language == lang && rt.Template {
+			ids = append(ids, rt.ID)
+		}
+	}
+
+	return ids
+}
+
+// Resolve maps format runtime identifier (e.g. cpp.g++17 in Polygon) to Eolymp runtime. Explicit format mapping is
+// used as is, otherwise the source described by the format is resolved using Pick.
+func (r *Registry) Resolve(format, id string) (Resolution, bool) {
+	if runtime, ok := r.conf.Formats[format].Runtimes[id]; ok {
+		rt, ok := r.Runtime(runtime)
+		if !ok {
+			return Resolution{}, false
+		}
+
+		src := Source{Language: rt.Language, Version: rt.Version, Flags: rt.Flags}
+		return Resolution{Source: src, Runtime: rt, Status: StatusExact}, true
+	}
+
+	src, ok := r.conf.Formats[format].Sources[id]
+	if !ok {
+		return Resolution{}, false
+	}
+
+	return r.Pick(src)
+}
+
+// Reverse maps Eolymp runtime to format runtime identifier (e.g. cpp:17-gnu10 to cpp.g++17 in Polygon), it's used to
+// export problems. Explicit format mapping is used if there is one. Otherwise, format sources in the runtime language
+// which have no flags missing in the runtime are considered (or all sources of the language if none) and the one with
+// the matching version is picked (source version 3 matches runtime 3.12), then the one with the lowest newer version,
+// then the one with unknown version and finally the one with the newest older version. Ties are broken by identifier.
+func (r *Registry) Reverse(format, runtime string) (string, bool) {
+	var ids []string
+	for id, target := range r.conf.Formats[format].Runtimes {
+		if target == runtime {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) > 0 {
+		return slices.Min(ids), true
+	}
+
+	rt, ok := r.Runtime(runtime)
+	if !ok {
+		rt = ParseRuntime(runtime)
+	}
+
+	var candidates, all []string
+	for id, src := range r.conf.Formats[format].Sources {
+		if src.Language != rt.Language {
+			continue
+		}
+
+		all = append(all, id)
+		if !slices.ContainsFunc(src.Flags, func(flag string) bool { return !rt.Has(flag) }) {
+			candidates = append(candidates, id)
+		}
+	}
+
+	if len(candidates) == 0 {
+		candidates = all
+	}
+
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	sources := r.conf.Formats[format].Sources
+
+	// rank is 0 for matching version, 1 for newer, 2 for unknown and 3 for older
+	rank := func(src Source) int {
+		switch c := CompareVersions(src.Version, rt.Version); {
+		case src.Version == "":
+			return 2
+		case c == 0 || strings.HasPrefix(rt.Version, src.Version+"."):
+			return 0
+		case c > 0:
+			return 1
+		default:
+			return 3
+		}
+	}
+
+	slices.SortFunc(candidates, func(a, b string) int {
+		x, y := sources[a], sources[b]
+		if c := cmp.Compare(rank(x), rank(y)); c != 0 {
+			return c
+		}
+
+		switch rank(x) {
+		case 1:
+			if c := CompareVersions(x.Version, y.Version); c != 0 {
+				return c
+			}
+		case 3:
+			if c := CompareVersions(y.Version, x.Version); c != 0 {
+				return c
+			}
+		}
+
+		return strings.Compare(a, b)
+	})
+
+	return candidates[0], true
+}
+
+// Pick finds Eolymp runtime for the source. Pinned runtime is used if the language has one. Otherwise, runtimes with
+// all the source flags are considered (or all runtimes of the language if none has them) and the one with the lowest
+// version not older than the source is picked, if every runtime is older, the newest one is used. When versions are
+// equal, runtime with fewer flags is preferred, then the one defined later. If source version is unknown, the default
+// runtime of the language is used.
+func (r *Registry) Pick(src Source) (Resolution, bool) {
+	lang, ok := r.Language(src.Language)
+	if !ok {
+		return Resolution{}, false
+	}
+
+	if pin, ok := r.conf.Pins[lang.Name]; ok {
+		rt, _ := r.Runtime(pin)
+		return Resolution{Source: src, Runtime: rt, Status: compare(lang, src, rt), Pinned: true}, true
+	}
+
+	var candidates []Runtime
+	for _, rt := range r.Runtimes(lang.Name) {
+		if !slices.ContainsFunc(src.Flags, func(flag string) bool { return !rt.Has(flag) }) {
+			candidates = append(candidates, rt)
+		}
+	}
+
+	if len(candidates) == 0 {
+		candidates = r.Runtimes(lang.Name)
+	}
+
+	if len(candidates) == 0 {
+		return Resolution{}, false
+	}
+
+	var best Runtime
+
+	switch i := slices.IndexFunc(candidates, func(rt Runtime) bool { return rt.ID == lang.Runtime }); {
+	case src.Version == "" && i >= 0:
+		best = candidates[i]
+	case src.Version == "":
+		best = newest(candidates)
+	default:
+		var found bool
+		for _, rt := range candidates {
+			if CompareVersions(rt.Version, src.Version) < 0 {
+				continue
+			}
+
+			if !found || prefer(rt, best, -1) {
+				best, found = rt, true
+			}
+		}
+
+		if !found {
+			best = newest(candidates)
+		}
+	}
+
+	return Resolution{Source: src, Runtime: best, Status: compare(lang, src, best)}, true
+}
+
+// newest returns runtime with the highest version
+func newest(candidates []Runtime) Runtime {
+	best := candidates[0]
+	for _, rt := range candidates[1:] {
+		if prefer(rt, best, 1) {
+			best = rt
+		}
+	}
+
+	return best
+}
+
+// prefer returns true if a should be used instead of b: a version is lower (order -1) or higher (order 1) than b,
+// or versions are equal and a does not have more flags than b
+func prefer(a, b Runtime, order int) bool {
+	if c := CompareVersions(a.Version, b.Version); c != 0 {
+		return c == order
+	}
+
+	return len(a.Flags) <= len(b.Flags)
+}
+
+// compare tells how runtime relates to the source
+func compare(lang Language, src Source, rt Runtime) Status {
+	if src.Version == "" {
+		return StatusUnknown
+	}
+
+	if lang.MajorIncompatible && CompareVersions(major(rt.Version), major(src.Version)) != 0 {
+		return StatusIncompatible
+	}
+
+	switch c := CompareVersions(rt.Version, src.Version); {
+	case c < 0:
+		return StatusDowngrade
+	case c > 0:
+		return StatusUpgrade
+	default:
+		return StatusExact
+	}
+}
+
+func major(version string) string {
+	major, _, _ := strings.Cut(version, ".")
+	return major
+}
+
+// CompareVersions compares dot separated versions component by component, numeric components are compared as numbers,
+// missing components are treated as zeros. Result is -1 if a < b, 1 if a > b and 0 if they are equal.
+func CompareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		x, y := "0", "0"
+		if i < len(as) && as[i] != "" {
+			x = as[i]
+		}
+
+		if i < len(bs) && bs[i] != "" {
+			y = bs[i]
+		}
+
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+
+		var c int
+		if xerr == nil && yerr == nil {
+			c = cmp.Compare(xn, yn)
+		} else {
+			c = strings.Compare(x, y)
+		}
+
+		if c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+// ResolveLanguage maps format language name (e.g. python3 in Kattis) to Eolymp language.
+func (r *Registry) ResolveLanguage(format, name string) (Language, bool) {
+	lang, ok := r.conf.Formats[format].Languages[name]
+	if !ok {
+		return Language{}, false
+	}
+
+	return r.Language(lang)
+}
@@ -0,0 +1,228 @@
+package runtimes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseRuntime(t *testing.T) {
+	tests := map[string]Runtime{
+		"cpp:20-gnu14-extra": {ID: "cpp:20-gnu14-extra", Language: "cpp", Version: "20", Flags: []string{"gnu14", "extra"}},
+		"python:3.12-python": {ID: "python:3.12-python", Language: "python", Version: "3.12", Flags: []string{"python"}},
+		"go:1.22":            {ID: "go:1.22", Language: "go", Version: "1.22", Flags: []string{}},
+		"ctd":                {ID: "ctd", Language: "ctd"},
+	}
+
+	for id, want := range tests {
+		if got := ParseRuntime(id); !cmp.Equal(want, got) {
+			t.Errorf("ParseRuntime(%#v) does not match:\n%s", id, cmp.Diff(want, got))
+		}
+	}
+}
+
+func TestDefault(t *testing.T) {
+	r := Default()
+
+	if res, ok := r.Resolve("polygon", "cpp.g++20"); !ok || res.Runtime.ID != "cpp:20-gnu14" || res.Runtime.Language != "cpp" || res.Runtime.Version != "20" {
+		t.Errorf("cpp.g++20 must resolve to cpp:20-gnu14, got %+v", res)
+	}
+
+	if lang, ok := r.LanguageByExtension(".PY"); !ok || lang.Name != "python" || lang.Runtime != "python:3.12-python" {
+		t.Errorf("extension .py must belong to python, got %+v", lang)
+	}
+
+	if lang, ok := r.ResolveLanguage("kattis", "javascript"); !ok || lang.Name != "js" {
+		t.Errorf("kattis javascript must map to js, got %+v", lang)
+	}
+
+	if _, ok := r.Resolve("polygon", "brainfuck"); ok {
+		t.Error("unknown runtime must not resolve")
+	}
+
+	want := []string{"c:17-gnu10"}
+	if got := r.Templates("c"); !cmp.Equal(want, got) {
+		t.Errorf("C templates do not match:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runtimes.yaml")
+
+	conf := `
+languages:
+  - {name: go, extensions: [go], runtime: "go:1.24"}
+runtimes:
+  - {id: "go:1.24", template: true}
+  - {id: "go:1.20"}
+formats:
+  polygon:
+    runtimes: {go: "go:1.24"}
+`
+
+	if err := os.WriteFile(path, []byte(conf), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res, ok := r.Resolve("polygon", "go"); !ok || res.Runtime.ID != "go:1.24" {
+		t.Errorf("go must resolve to the new runtime, got %+v", res)
+	}
+
+	if res, ok := r.Resolve("polygon", "cpp.g++17"); !ok || res.Runtime.ID != "cpp:17-gnu10" {
+		t.Errorf("other runtimes must stay as they are, got %+v", res)
+	}
+
+	if lang, _ := r.Language("go"); lang.Runtime != "go:1.24" {
+		t.Errorf("go default runtime must be replaced, got %#v", lang.Runtime)
+	}
+
+	want := []string{"go:1.24"}
+	if got := r.Templates("go"); !cmp.Equal(want, got) {
+		t.Errorf("Go templates do not match:\n%s", cmp.Diff(want, got))
+	}
+
+	// default registry must not change
+	if res, _ := Default().Resolve("polygon", "go"); res.Runtime.ID != "go:1.22" {
+		t.Errorf("default registry must not be modified, got %+v", res)
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown runtime language": `{runtimes: [{id: "brainfuck:1"}]}`,
+		"unknown default runtime":  `{languages: [{name: go, runtime: "go:1.99"}]}`,
+		"unknown format runtime":   `{languages: [{name: go}], formats: {polygon: {runtimes: {go: "go:1.99"}}}}`,
+		"unknown source language":  `{formats: {polygon: {sources: {go: {language: go}}}}}`,
+		"unknown pinned runtime":   `{languages: [{name: go}], pins: {go: "go:1.99"}}`,
+		"pin of another language":  `{languages: [{name: go}, {name: c}], runtimes: [{id: "c:17"}], pins: {go: "c:17"}}`,
+		"duplicate extension":      `{languages: [{name: c, extensions: [h]}, {name: cpp, extensions: [h]}]}`,
+		"duplicate runtime":        `{languages: [{name: go}], runtimes: [{id: "go:1"}, {id: "go:1"}]}`,
+		"unknown field":            `{compilers: []}`,
+	}
+
+	for name, conf := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(conf)); err == nil {
+				t.Error("Parse must fail")
+			}
+		})
+	}
+}
+
+func TestRegistry_Resolve(t *testing.T) {
+	r := Default()
+
+	tests := map[string]struct {
+		runtime string
+		status  Status
+	}{
+		"cpp.g++11":       {runtime: "cpp:11-gnu10", status: StatusExact},
+		"cpp.g++14":       {runtime: "cpp:17-gnu10", status: StatusUpgrade},
+		"cpp.ms":          {runtime: "cpp:17-gnu10", status: StatusUnknown},
+		"cpp.g++20":       {runtime: "cpp:20-gnu14", status: StatusExact},
+		"csharp.mono":     {runtime: "csharp:5-mono", status: StatusUnknown},
+		"java11":          {runtime: "java:1.17", status: StatusUpgrade},
+		"python.2":        {runtime: "python:3-python", status: StatusIncompatible},
+		"python.3":        {runtime: "python:3-python", status: StatusExact},
+		"python.pypy3-64": {runtime: "python:3-pypy", status: StatusExact},
+		"php.5":           {runtime: "php:7.4", status: StatusUpgrade},
+	}
+
+	for id, want := range tests {
+		res, ok := r.Resolve("polygon", id)
+		if !ok || res.Runtime.ID != want.runtime || res.Status != want.status {
+			t.Errorf("%v must resolve to %v (%v), got %v (%v)", id, want.runtime, want.status, res.Runtime.ID, res.Status)
+		}
+	}
+}
+
+func TestRegistry_Reverse(t *testing.T) {
+	r := Default()
+
+	tests := map[string]string{
+		"cpp:17-gnu10":       "cpp.g++17",
+		"cpp:23-gnu14-extra": "cpp.gcc14-64-msys2-g++23",
+		"c:17-gnu10":         "c.gcc",
+		"csharp:5-dotnet":    "csharp.mono",
+		"java:1.17":          "java21",
+		"python:3.12-python": "python.3",
+		"python:3.10-pypy":   "python.pypy3",
+		"pascal:3.2":         "pas.fpc",
+		"php:8.2":            "php.5",
+	}
+
+	for runtime, want := range tests {
+		if got, ok := r.Reverse("polygon", runtime); !ok || got != want {
+			t.Errorf("%v must map to %v, got %#v", runtime, want, got)
+		}
+	}
+
+	if _, ok := r.Reverse("polygon", "zig:0.12"); ok {
+		t.Error("runtime without a source in the language must not map")
+	}
+
+	r, err := r.Override([]byte(`{formats: {polygon: {runtimes: {cpp.ms: "cpp:17-gnu10"}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok := r.Reverse("polygon", "cpp:17-gnu10"); !ok || got != "cpp.ms" {
+		t.Errorf("explicit mapping must be used, got %#v", got)
+	}
+}
+
+func TestRegistry_Pick(t *testing.T) {
+	r, err := Default().Override([]byte(`{pins: {java: "java:1.17"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, ok := r.Pick(Source{Language: "cpp", Version: "26"})
+	if !ok || res.Runtime.ID != "cpp:23-gnu14" || res.Status != StatusDowngrade {
+		t.Errorf("cpp 26 must be downgraded to cpp:23-gnu14, got %+v", res)
+	}
+
+	if _, ok := res.Warning(); !ok {
+		t.Error("downgrade must have a warning")
+	}
+
+	res, ok = r.Pick(Source{Language: "java", Version: "1.8"})
+	if !ok || res.Runtime.ID != "java:1.17" || !res.Pinned || res.Status != StatusUpgrade {
+		t.Errorf("java must use pinned runtime, got %+v", res)
+	}
+
+	res, ok = r.Pick(Source{Language: "java", Version: "1.21"})
+	if !ok || res.Runtime.ID != "java:1.17" || res.Status != StatusDowngrade {
+		t.Errorf("pinned runtime must be reported as a downgrade, got %+v", res)
+	}
+
+	if _, ok := r.Pick(Source{Language: "brainfuck"}); ok {
+		t.Error("unknown language must not resolve")
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "3", b: "3.0", want: 0},
+		{a: "1.8", b: "1.17", want: -1},
+		{a: "23", b: "20", want: 1},
+		{a: "3.12", b: "3", want: 1},
+		{a: "", b: "1", want: -1},
+	}
+
+	for _, test := range tests {
+		if got := CompareVersions(test.a, test.b); got != test.want {
+			t.Errorf("CompareVersions(%#v, %#v) = %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/eolymp/go-problems/connector"
+	"github.com/eolymp/go-problems/lint"
+	"github.com/eolymp/go-problems/transform"
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Status of the import job.
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Done returns true if job has finished and its status won't change anymore.
+func (s Status) Done() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCancelled
+}
+
+// Stage of the running job, reported as a part of job progress.
+type Stage string
+
+const (
+	StageQueued   Stage = "queued"
+	StageFetching Stage = "fetching"
+	StageLinting  Stage = "linting"
+	StageDone     Stage = "done"
+)
+
+// Options control how the problem is imported.
+type Options struct {
+	Format  string `json:"format,omitempty"`  // problem format (polygon or kattis), detected automatically if empty
+	Timeout int    `json:"timeout,omitempty"` // time limit for the job in seconds, it can't exceed the service one, which is used if 0
+
+	// Transforms are applied to the snapshot after it's built, see package transform
+	Transforms []transform.Step `json:"transforms,omitempty"`
+}
+
+// Request to import a problem.
+type Request struct {
+	Link    string  `json:"link"`
+	Options Options `json:"options"`
+}
+
+// Progress of the job, uploads are counted as they complete.
+type Progress struct {
+	Stage   Stage `json:"stage"`
+	Uploads int   `json:"uploads"`
+	Bytes   int64 `json:"bytes"`
+}
+
+// Log is a single line of the job log.
+type Log struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+}
+
+// Job is a state of the import job at a point in time.
+type Job struct {
+	ID         string                `json:"id"`
+	Link       string                `json:"link"` // link to the problem, credentials are removed
+	Options    Options               `json:"options"`
+	Status     Status                `json:"status"`
+	Progress   Progress              `json:"progress"`
+	Error      string                `json:"error,omitempty"`
+	Format     string                `json:"format,omitempty"`
+	Findings   []lint.Finding        `json:"findings,omitempty"`
+	Provenance *connector.Provenance `json:"provenance,omitempty"`
+	Warnings   []connector.Warning   `json:"warnings,omitempty"`
+	CreatedAt  time.Time             `json:"created_at"`
+	StartedAt  *time.Time            `json:"started_at,omitempty"`
+	FinishedAt *time.Time            `json:"finished_at,omitempty"`
+}
+
+// job is a mutable state of the job, all fields are protected by the lock
+type job struct {
+	lock     sync.Mutex
+	state    Job
+	link     string // link to the problem with credentials, it's never exposed
+	logs     []Log
+	snapshot *atlaspb.Snapshot
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func (j *job) view() Job {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	v := j.state
+	v.Findings = append([]lint.Finding(nil), j.state.Findings...)
+	v.Warnings = append([]connector.Warning(nil), j.state.Warnings...)
+	if j.state.Provenance != nil {
+		provenance := *j.state.Provenance
+		v.Provenance = &provenance
+	}
+
+	return v
+}
+
+func (j *job) update(fn func(state *Job)) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	fn(&j.state)
+}
+
+func (j *job) logsSince(offset int) []Log {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if offset >= len(j.logs) {
+		return []Log{}
+	}
+
+	return append([]Log(nil), j.logs[offset:]...)
+}
+
+func (j *job) result() (json.RawMessage, bool, error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.snapshot == nil {
+		return nil, false, nil
+	}
+
+	data, err := protojson.Marshal(j.snapshot)
+	if err != nil {
+		return nil, true, fmt.Errorf("unable to encode snapshot: %w", err)
+	}
+
+	return data, true, nil
+}
+
+func (j *job) Printf(format string, args ...any) {
+	j.log("info", format, args...)
+}
+
+func (j *job) Errorf(format string, args ...any) {
+	j.log("error", format, args...)
+}
+
+func (j *job) log(level, format string, args ...any) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	j.logs = append(j.logs, Log{Time: time.Now(), Level: level, Message: fmt.Sprintf(format, args...)})
+}
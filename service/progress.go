@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"github.com/eolymp/go-problems/connector"
+	assetpb "github.com/eolymp/go-sdk/eolymp/asset"
+	"google.golang.org/grpc"
+)
+
+// progressUploader counts completed uploads and uploaded bytes and reports them to the job
+type progressUploader struct {
+	connector.Uploader
+	job *job
+}
+
+func (u *progressUploader) UploadAsset(ctx context.Context, in *assetpb.UploadAssetInput, opts ...grpc.CallOption) (*assetpb.UploadAssetOutput, error) {
+	out, err := u.Uploader.UploadAsset(ctx, in, opts...)
+	if err == nil {
+		u.job.update(func(state *Job) {
+			state.Progress.Uploads++
+			state.Progress.Bytes += int64(len(in.GetData()))
+		})
+	}
+
+	return out, err
+}
+
+func (u *progressUploader) UploadPart(ctx context.Context, in *assetpb.UploadPartInput, opts ...grpc.CallOption) (*assetpb.UploadPartOutput, error) {
+	out, err := u.Uploader.UploadPart(ctx, in, opts...)
+	if err == nil {
+		u.job.update(func(state *Job) {
+			state.Progress.Bytes += int64(len(in.GetData()))
+		})
+	}
+
+	return out, err
+}
+
+func (u *progressUploader) CompleteMultipartUpload(ctx context.Context, in *assetpb.CompleteMultipartUploadInput, opts ...grpc.CallOption) (*assetpb.CompleteMultipartUploadOutput, error) {
+	out, err := u.Uploader.CompleteMultipartUpload(ctx, in, opts...)
+	if err == nil {
+		u.job.update(func(state *Job) {
+			state.Progress.Uploads++
+		})
+	}
+
+	return out, err
+}
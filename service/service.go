@@ -0,0 +1,456 @@
+// Package service runs problem imports in the background. It accepts import jobs, runs them on a bounded pool of
+// workers and keeps job state, logs and resulting snapshots in memory until they are evicted (see UseRetention and
+// UseMaxJobs).
+//
+// Service can be embedded into an existing HTTP server using Handler.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eolymp/go-problems/connector"
+	"github.com/eolymp/go-problems/importer"
+	"github.com/eolymp/go-problems/lint"
+	"github.com/eolymp/go-problems/transform"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotFound  = errors.New("job not found")
+	ErrQueueFull = errors.New("job queue is full")
+	ErrClosed    = errors.New("service is closed")
+	ErrLocalLink = errors.New("local files can not be imported")
+)
+
+// Service accepts import jobs and runs them in the background.
+type Service struct {
+	upload  connector.Uploader
+	workers int
+	queue   int
+	timeout time.Duration
+	options []importer.Option
+	local   bool
+	ttl     time.Duration
+	limit   int
+
+	lock   sync.RWMutex
+	jobs   map[string]*job
+	closed bool
+
+	pending []*job     // queued jobs in the order they are submitted
+	ready   *sync.Cond // signals workers about queued jobs and closing
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// UseWorkers sets number of jobs running concurrently, 2 by default.
+func UseWorkers(n int) func(*Service) {
+	return func(s *Service) {
+		s.workers = n
+	}
+}
+
+// UseQueueSize sets number of jobs which can wait for a worker, 100 by default.
+func UseQueueSize(n int) func(*Service) {
+	return func(s *Service) {
+		s.queue = n
+	}
+}
+
+// UseTimeout sets time limit for a job, 30 minutes by default. Jobs may ask for a shorter limit, but not a longer one.
+func UseTimeout(timeout time.Duration) func(*Service) {
+	return func(s *Service) {
+		s.timeout = timeout
+	}
+}
+
+// UseImportOptions configures loaders used by the service.
+func UseImportOptions(opts ...importer.Option) func(*Service) {
+	return func(s *Service) {
+		s.options = append(s.options, opts...)
+	}
+}
+
+// UseLocalLinks allows importing local files and directories (paths and file:// links). It's disabled by default,
+// since anyone who can submit a job would be able to read files of the server.
+func UseLocalLinks() func(*Service) {
+	return func(s *Service) {
+		s.local = true
+	}
+}
+
+// UseRetention sets how long finished jobs and their snapshots are kept, 24 hours by default.
+func UseRetention(ttl time.Duration) func(*Service) {
+	return func(s *Service) {
+		s.ttl = ttl
+	}
+}
+
+// UseMaxJobs sets maximum number of jobs kept by the service, 1000 by default. When the limit is reached, the oldest
+// finished jobs are removed to make room for the new ones.
+func UseMaxJobs(n int) func(*Service) {
+	return func(s *Service) {
+		s.limit = n
+	}
+}
+
+// New creates service and starts its workers. Assets of imported problems are uploaded using upload.
+func New(upload connector.Uploader, opts ...func(*Service)) *Service {
+	s := &Service{
+		upload:  upload,
+		workers: 2,
+		queue:   100,
+		timeout: 30 * time.Minute,
+		ttl:     24 * time.Hour,
+		limit:   1000,
+		jobs:    map[string]*job{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.ready = sync.NewCond(&s.lock)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	for i := 0; i < s.workers; i++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+
+	return s
+}
+
+// Submit adds the job to the queue.
+func (s *Service) Submit(req Request) (Job, error) {
+	if req.Link == "" {
+		return Job{}, errors.New("link is required")
+	}
+
+	if !s.local && local(req.Link) {
+		return Job{}, ErrLocalLink
+	}
+
+	if _, err := transform.New(req.Options.Transforms...); err != nil {
+		return Job{}, err
+	}
+
+	if req.Options.Format != "" {
+		if _, err := importer.ParseFormat(req.Options.Format); err != nil {
+			return Job{}, err
+		}
+	}
+
+	j := &job{
+		state: Job{
+			ID:        uuid.New().String(),
+			Link:      connector.Redact(req.Link),
+			Options:   req.Options,
+			Status:    StatusQueued,
+			Progress:  Progress{Stage: StageQueued},
+			CreatedAt: time.Now(),
+		},
+		link: req.Link,
+		done: make(chan struct{}),
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return Job{}, ErrClosed
+	}
+
+	s.evict()
+
+	if len(s.pending) >= s.queue {
+		return Job{}, ErrQueueFull
+	}
+
+	s.pending = append(s.pending, j)
+	s.jobs[j.state.ID] = j
+	s.ready.Signal()
+
+	return j.view(), nil
+}
+
+// Get returns current state of the job.
+func (s *Service) Get(id string) (Job, error) {
+	j, err := s.job(id)
+	if err != nil {
+		return Job{}, err
+	}
+
+	return j.view(), nil
+}
+
+// List returns all jobs ordered by creation time.
+func (s *Service) List() []Job {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j.view())
+	}
+
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].CreatedAt.Before(jobs[k].CreatedAt) })
+
+	return jobs
+}
+
+// Logs returns job log lines starting at offset.
+func (s *Service) Logs(id string, offset int) ([]Log, error) {
+	j, err := s.job(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return j.logsSince(offset), nil
+}
+
+// Cancel stops the job. Queued jobs are cancelled immediately, running jobs are cancelled once loader notices
+// context cancellation.
+func (s *Service) Cancel(id string) (Job, error) {
+	s.lock.Lock()
+
+	j, ok := s.jobs[id]
+	if !ok {
+		s.lock.Unlock()
+		return Job{}, ErrNotFound
+	}
+
+	j.lock.Lock()
+	switch {
+	case j.state.Status == StatusQueued:
+		now := time.Now()
+		j.state.Status = StatusCancelled
+		j.state.FinishedAt = &now
+		close(j.done)
+
+		// free the queue slot, so cancelled jobs do not prevent new ones from being submitted
+		if i := slices.Index(s.pending, j); i >= 0 {
+			s.pending = slices.Delete(s.pending, i, i+1)
+		}
+	case j.state.Status == StatusRunning && j.cancel != nil:
+		j.cancel()
+	}
+	j.lock.Unlock()
+
+	s.lock.Unlock()
+
+	return j.view(), nil
+}
+
+// Wait blocks until job is finished or context is cancelled.
+func (s *Service) Wait(ctx context.Context, id string) (Job, error) {
+	j, err := s.job(id)
+	if err != nil {
+		return Job{}, err
+	}
+
+	select {
+	case <-j.done:
+		return j.view(), nil
+	case <-ctx.Done():
+		return j.view(), ctx.Err()
+	}
+}
+
+// Close stops accepting new jobs, cancels running jobs and waits for workers to stop.
+func (s *Service) Close() {
+	s.lock.Lock()
+	s.closed = true
+	s.ready.Broadcast()
+	s.lock.Unlock()
+
+	s.cancel()
+	s.wg.Wait()
+}
+
+// evict removes finished jobs which are older than retention period, and the oldest finished jobs if there is no
+// room for a new one, must be called with the lock held
+func (s *Service) evict() {
+	var finished []Job
+	for id, j := range s.jobs {
+		state := j.view()
+		if !state.Status.Done() || state.FinishedAt == nil {
+			continue
+		}
+
+		if time.Since(*state.FinishedAt) > s.ttl {
+			delete(s.jobs, id)
+			continue
+		}
+
+		finished = append(finished, state)
+	}
+
+	sort.Slice(finished, func(i, k int) bool { return finished[i].FinishedAt.Before(*finished[k].FinishedAt) })
+
+	for _, state := range finished {
+		if len(s.jobs) < s.limit {
+			break
+		}
+
+		delete(s.jobs, state.ID)
+	}
+}
+
+func (s *Service) job(id string) (*job, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	j, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return j, nil
+}
+
+func (s *Service) work() {
+	defer s.wg.Done()
+
+	for {
+		s.lock.Lock()
+		for len(s.pending) == 0 && !s.closed {
+			s.ready.Wait()
+		}
+
+		// queued jobs are still taken after closing, so they are marked as cancelled
+		if len(s.pending) == 0 {
+			s.lock.Unlock()
+			return
+		}
+
+		j := s.pending[0]
+		s.pending = s.pending[1:]
+		s.lock.Unlock()
+
+		s.run(j)
+	}
+}
+
+func (s *Service) run(j *job) {
+	// job may ask for a shorter time limit, but not for a longer one
+	timeout := s.timeout
+	if t := time.Duration(j.view().Options.Timeout) * time.Second; t > 0 && t < timeout {
+		timeout = t
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, timeout)
+	defer cancel()
+
+	j.lock.Lock()
+	if j.state.Status != StatusQueued {
+		j.lock.Unlock()
+		return
+	}
+
+	now := time.Now()
+
+	// service is closing, do not start queued jobs
+	if s.ctx.Err() != nil {
+		j.state.Status = StatusCancelled
+		j.state.FinishedAt = &now
+		close(j.done)
+		j.lock.Unlock()
+		return
+	}
+
+	j.state.Status = StatusRunning
+	j.state.StartedAt = &now
+	j.state.Progress.Stage = StageFetching
+	j.cancel = cancel
+	link, opts := j.link, j.state.Options
+	j.lock.Unlock()
+
+	err := s.fetch(ctx, j, link, opts)
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	now = time.Now()
+	j.state.FinishedAt = &now
+	j.state.Progress.Stage = StageDone
+	j.cancel = nil
+
+	switch {
+	case err == nil:
+		j.state.Status = StatusSucceeded
+	case ctx.Err() != nil && errors.Is(ctx.Err(), context.Canceled):
+		j.state.Status = StatusCancelled
+		j.state.Error = err.Error()
+	default:
+		j.state.Status = StatusFailed
+		j.state.Error = err.Error()
+	}
+
+	close(j.done)
+}
+
+func (s *Service) fetch(ctx context.Context, j *job, link string, opts Options) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("loader has panicked: %v", r)
+		}
+	}()
+
+	pipeline, err := transform.New(opts.Transforms...)
+	if err != nil {
+		return err
+	}
+
+	format := importer.Format(opts.Format)
+	if format == "" {
+		if format, err = importer.Detect(link); err != nil {
+			return err
+		}
+	}
+
+	j.update(func(state *Job) { state.Format = string(format) })
+
+	result, err := importer.Import(ctx, format, link, &progressUploader{Uploader: s.upload, job: j}, j, s.options...)
+	if err != nil {
+		return err
+	}
+
+	snap := result.Snapshot
+
+	if err := pipeline.Apply(snap); err != nil {
+		return err
+	}
+
+	j.update(func(state *Job) { state.Progress.Stage = StageLinting })
+
+	findings := lint.Lint(snap)
+
+	j.lock.Lock()
+	j.snapshot = snap
+	j.state.Findings = findings
+	j.state.Provenance = result.Provenance
+	j.state.Warnings = result.Warnings
+	j.lock.Unlock()
+
+	return nil
+}
+
+// local returns true if the link points to a local file or directory
+func local(link string) bool {
+	origin, err := url.Parse(link)
+	if err != nil || !strings.Contains(link, "://") {
+		return true
+	}
+
+	return origin.Scheme == "" || strings.EqualFold(origin.Scheme, "file")
+}
@@ -0,0 +1,384 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/eolymp/go-problems/connector/testing"
+	"github.com/eolymp/go-problems/transform"
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestService_Import(t *testing.T) {
+	svc := New(MockUploader(), UseLocalLinks())
+	defer svc.Close()
+
+	server := httptest.NewServer(svc.Handler())
+	defer server.Close()
+
+	path, err := filepath.Abs("../polygon/.testdata/03-test-scoring-with-points")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var job Job
+	req := Request{Link: path, Options: Options{Transforms: []transform.Step{
+		{Name: "feedback", With: map[string]any{"policy": "icpc"}},
+	}}}
+
+	call(t, http.MethodPost, server.URL+"/jobs", req, http.StatusAccepted, &job)
+
+	if job.Status != StatusQueued && job.Status != StatusRunning {
+		t.Errorf("job must be queued, got %v", job.Status)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := svc.Wait(ctx, job.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	call(t, http.MethodGet, server.URL+"/jobs/"+job.ID, nil, http.StatusOK, &job)
+
+	if job.Status != StatusSucceeded {
+		t.Fatalf("job must succeed, got %v: %v", job.Status, job.Error)
+	}
+
+	if job.Format != "polygon" {
+		t.Errorf("job format must be polygon, got %#v", job.Format)
+	}
+
+	if job.Progress.Stage != StageDone || job.Progress.Uploads == 0 {
+		t.Errorf("job progress must report uploads, got %+v", job.Progress)
+	}
+
+	var logs struct {
+		Items  []Log `json:"items"`
+		Offset int   `json:"offset"`
+	}
+
+	call(t, http.MethodGet, server.URL+"/jobs/"+job.ID+"/logs", nil, http.StatusOK, &logs)
+
+	if len(logs.Items) == 0 || logs.Offset != len(logs.Items) {
+		t.Errorf("job logs must not be empty, got %+v", logs)
+	}
+
+	resp, err := http.Get(server.URL + "/jobs/" + job.ID + "/snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		t.Fatal(err)
+	}
+
+	snap := &atlaspb.Snapshot{}
+	if err := protojson.Unmarshal(buf.Bytes(), snap); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(snap.GetTests()) == 0 {
+		t.Error("snapshot must contain tests")
+	}
+
+	for _, testset := range snap.GetTestsets() {
+		if testset.GetFeedbackPolicy() != atlaspb.FeedbackPolicy_ICPC {
+			t.Errorf("transforms must be applied, testset %v has feedback policy %v", testset.GetIndex(), testset.GetFeedbackPolicy())
+		}
+	}
+}
+
+func TestService_Cancel(t *testing.T) {
+	started := make(chan struct{})
+	archive := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	}))
+
+	defer archive.Close()
+
+	svc := New(MockUploader(), UseWorkers(1))
+	defer svc.Close()
+
+	running, err := svc.Submit(Request{Link: archive.URL + "/problem.zip", Options: Options{Format: "kattis"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queued, err := svc.Submit(Request{Link: archive.URL + "/another.zip", Options: Options{Format: "kattis"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	<-started
+
+	if job, err := svc.Cancel(queued.ID); err != nil || job.Status != StatusCancelled {
+		t.Fatalf("queued job must be cancelled immediately, got %v (%v)", job.Status, err)
+	}
+
+	if _, err := svc.Cancel(running.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	job, err := svc.Wait(ctx, running.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if job.Status != StatusCancelled {
+		t.Errorf("running job must be cancelled, got %v", job.Status)
+	}
+}
+
+func TestService_Submit(t *testing.T) {
+	svc := New(MockUploader(), UseWorkers(0), UseQueueSize(1))
+	defer svc.Close()
+
+	server := httptest.NewServer(svc.Handler())
+	defer server.Close()
+
+	call(t, http.MethodPost, server.URL+"/jobs", Request{}, http.StatusBadRequest, nil)
+	call(t, http.MethodPost, server.URL+"/jobs", Request{Link: "x", Options: Options{Format: "zip"}}, http.StatusBadRequest, nil)
+	call(t, http.MethodPost, server.URL+"/jobs", Request{Link: "x", Options: Options{Transforms: []transform.Step{{Name: "magic"}}}}, http.StatusBadRequest, nil)
+	call(t, http.MethodPost, server.URL+"/jobs", Request{Link: "https://example.com/1.zip"}, http.StatusAccepted, nil)
+	call(t, http.MethodPost, server.URL+"/jobs", Request{Link: "https://example.com/2.zip"}, http.StatusServiceUnavailable, nil)
+	call(t, http.MethodGet, server.URL+"/jobs/unknown", nil, http.StatusNotFound, nil)
+
+	var list struct {
+		Items []Job `json:"items"`
+	}
+
+	call(t, http.MethodGet, server.URL+"/jobs", nil, http.StatusOK, &list)
+
+	if len(list.Items) != 1 {
+		t.Errorf("list must contain one job, got %v", len(list.Items))
+	}
+}
+
+func TestService_Submit_cancelled(t *testing.T) {
+	svc := New(MockUploader(), UseWorkers(0), UseQueueSize(1))
+	defer svc.Close()
+
+	for i := 0; i < 3; i++ {
+		job, err := svc.Submit(Request{Link: "https://example.com/1.zip"})
+		if err != nil {
+			t.Fatalf("job %v must be queued, cancelled jobs must not take queue slots: %v", i, err)
+		}
+
+		if _, err := svc.Cancel(job.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := svc.Submit(Request{Link: "https://example.com/2.zip"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.Submit(Request{Link: "https://example.com/3.zip"}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("queue must be full, got %v", err)
+	}
+}
+
+func TestService_timeout(t *testing.T) {
+	archive := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+
+	defer archive.Close()
+
+	svc := New(MockUploader(), UseTimeout(100*time.Millisecond))
+	defer svc.Close()
+
+	job, err := svc.Submit(Request{Link: archive.URL + "/problem.zip", Options: Options{Format: "kattis", Timeout: 3600}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	job, err = svc.Wait(ctx, job.ID)
+	if err != nil {
+		t.Fatal("job must be stopped by the service time limit:", err)
+	}
+
+	if job.Status != StatusFailed {
+		t.Errorf("job must fail, got %v", job.Status)
+	}
+}
+
+func TestService_Submit_local(t *testing.T) {
+	svc := New(MockUploader(), UseWorkers(0))
+	defer svc.Close()
+
+	server := httptest.NewServer(svc.Handler())
+	defer server.Close()
+
+	path, err := filepath.Abs("../polygon/.testdata/03-test-scoring-with-points")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, link := range []string{path, "file://" + path, "FILE:///etc", "../polygon", "/does/not/exist"} {
+		if _, err := svc.Submit(Request{Link: link}); !errors.Is(err, ErrLocalLink) {
+			t.Errorf("link %#v must be rejected, got %v", link, err)
+		}
+	}
+
+	call(t, http.MethodPost, server.URL+"/jobs", Request{Link: path}, http.StatusBadRequest, nil)
+
+	if jobs := svc.List(); len(jobs) != 0 {
+		t.Errorf("local links must not be queued, got %v jobs", len(jobs))
+	}
+}
+
+func TestService_Get_credentials(t *testing.T) {
+	svc := New(MockUploader(), UseWorkers(0))
+	defer svc.Close()
+
+	server := httptest.NewServer(svc.Handler())
+	defer server.Close()
+
+	var job Job
+	call(t, http.MethodPost, server.URL+"/jobs", Request{Link: "polygon://key:secret@/?problemId=1"}, http.StatusAccepted, &job)
+
+	for _, path := range []string{"/jobs/" + job.ID, "/jobs"} {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if bytes.Contains(body, []byte("secret")) || bytes.Contains(body, []byte("key:")) {
+			t.Errorf("GET %v must not expose credentials, got %s", path, body)
+		}
+	}
+
+	if got, err := svc.Get(job.ID); err != nil || got.Link != "polygon:///?problemId=1" {
+		t.Errorf("job link must be redacted, got %#v (%v)", got.Link, err)
+	}
+}
+
+func TestService_evict(t *testing.T) {
+	t.Run("retention", func(t *testing.T) {
+		svc := New(MockUploader(), UseWorkers(0), UseRetention(time.Millisecond))
+		defer svc.Close()
+
+		finished, err := svc.Submit(Request{Link: "https://example.com/1.zip"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		queued, err := svc.Submit(Request{Link: "https://example.com/2.zip"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := svc.Cancel(finished.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+
+		if _, err := svc.Submit(Request{Link: "https://example.com/3.zip"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := svc.Get(finished.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("finished job must be evicted after retention period, got %v", err)
+		}
+
+		if _, err := svc.Get(queued.ID); err != nil {
+			t.Errorf("queued job must not be evicted, got %v", err)
+		}
+	})
+
+	t.Run("max jobs", func(t *testing.T) {
+		svc := New(MockUploader(), UseWorkers(0), UseMaxJobs(2))
+		defer svc.Close()
+
+		var ids []string
+		for _, link := range []string{"https://example.com/1.zip", "https://example.com/2.zip"} {
+			job, err := svc.Submit(Request{Link: link})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := svc.Cancel(job.ID); err != nil {
+				t.Fatal(err)
+			}
+
+			ids = append(ids, job.ID)
+		}
+
+		if _, err := svc.Submit(Request{Link: "https://example.com/3.zip"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := svc.Get(ids[0]); !errors.Is(err, ErrNotFound) {
+			t.Errorf("the oldest finished job must be evicted, got %v", err)
+		}
+
+		if _, err := svc.Get(ids[1]); err != nil {
+			t.Errorf("the newest finished job must be kept, got %v", err)
+		}
+
+		if jobs := svc.List(); len(jobs) != 2 {
+			t.Errorf("service must keep 2 jobs, got %v", len(jobs))
+		}
+	})
+}
+
+func call(t *testing.T, method, url string, in any, status int, out any) {
+	t.Helper()
+
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req, err := http.NewRequest(method, url, &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != status {
+		t.Fatalf("%v %v: status must be %v, got %v", method, url, status, resp.StatusCode)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
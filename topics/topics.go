@@ -0,0 +1,347 @@
+// Package topics maps problem tags (Polygon tags, Kattis keywords) to Eolymp topics.
+//
+// The default mapping is embedded into the package (see topics.yaml), it can be extended or overridden using a
+// configuration file in the same format:
+//
+//	topics:
+//	  - id: 3hr591p5lh7a9c5k9bg8kpvctg
+//	    name: Binary Search
+//	    tags: [binary search, binsearch, двоичный поиск]
+//	ignore: [ad-hoc]
+//
+// Topics are matched by ID, entries from the file replace default ones, new entries are added. Ignored tags are
+// appended to the default list.
+//
+// Tags are normalized before matching (see Normalize). If a tag still does not match, it's compared with known tags
+// ignoring spaces, so "Dijkstra's", "dijkstra" and "Dijk stra" find the same topic. Map also allows a typo in long
+// tags ("dijktra"), but reports such matches, since different words may happen to be close.
+package topics
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"gopkg.in/yaml.v2"
+)
+
+//go:embed topics.yaml
+var defaults []byte
+
+// Topic is an Eolymp topic and tags which refer to it.
+type Topic struct {
+	ID   string   `yaml:"id"`
+	Name string   `yaml:"name,omitempty"`
+	Tags []string `yaml:"tags"` // aliases in any language
+}
+
+// Config is a serialized form of the mapping.
+type Config struct {
+	Topics []Topic  `yaml:"topics,omitempty"`
+	Ignore []string `yaml:"ignore,omitempty"` // known tags without a topic, they are not reported as unmapped
+}
+
+// Mapper maps tags to topics, it's immutable and safe for concurrent use.
+type Mapper struct {
+	conf    Config
+	tags    map[string][]string // normalized tag to topic IDs
+	compact map[string]string   // normalized tag without spaces to normalized tag
+	ignore  map[string]bool
+}
+
+var (
+	once   sync.Once
+	mapper *Mapper
+)
+
+// Default returns the mapping embedded into the package.
+func Default() *Mapper {
+	once.Do(func() {
+		m, err := Parse(defaults)
+		if err != nil {
+			panic(fmt.Errorf("embedded topic mapping is invalid: %w", err))
+		}
+
+		mapper = m
+	})
+
+	return mapper
+}
+
+// Parse creates mapping from YAML (or JSON) configuration.
+func Parse(data []byte) (*Mapper, error) {
+	conf := Config{}
+	if err := yaml.UnmarshalStrict(data, &conf); err != nil {
+		return nil, fmt.Errorf("unable to parse topic mapping: %w", err)
+	}
+
+	return New(conf)
+}
+
+// Load reads configuration file and applies it on top of the default mapping.
+func Load(path string) (*Mapper, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read topic mapping: %w", err)
+	}
+
+	return Default().Override(data)
+}
+
+// New creates mapping from configuration.
+func New(conf Config) (*Mapper, error) {
+	m := &Mapper{
+		tags:    map[string][]string{},
+		compact: map[string]string{},
+		ignore:  map[string]bool{},
+	}
+
+	seen := map[string]bool{}
+	for _, topic := range conf.Topics {
+		if topic.ID == "" {
+			return nil, fmt.Errorf("topic ID is required")
+		}
+
+		if seen[topic.ID] {
+			return nil, fmt.Errorf("topic %#v is defined more than once", topic.ID)
+		}
+
+		seen[topic.ID] = true
+
+		topic.Tags = slices.Clone(topic.Tags)
+		m.conf.Topics = append(m.conf.Topics, topic)
+
+		for _, tag := range topic.Tags {
+			key := Normalize(tag)
+			if key == "" {
+				return nil, fmt.Errorf("topic %#v has an empty tag", topic.ID)
+			}
+
+			if !slices.Contains(m.tags[key], topic.ID) {
+				m.tags[key] = append(m.tags[key], topic.ID)
+			}
+
+			m.compact[strings.ReplaceAll(key, " ", "")] = key
+		}
+	}
+
+	m.conf.Ignore = slices.Clone(conf.Ignore)
+	for _, tag := range conf.Ignore {
+		m.ignore[Normalize(tag)] = true
+	}
+
+	return m, nil
+}
+
+// Override creates a new mapping by applying configuration on top of this one.
+func (m *Mapper) Override(data []byte) (*Mapper, error) {
+	patch := Config{}
+	if err := yaml.UnmarshalStrict(data, &patch); err != nil {
+		return nil, fmt.Errorf("unable to parse topic mapping: %w", err)
+	}
+
+	conf := m.Config()
+
+	for _, topic := range patch.Topics {
+		if i := slices.IndexFunc(conf.Topics, func(t Topic) bool { return t.ID == topic.ID }); i >= 0 {
+			conf.Topics[i] = topic
+		} else {
+			conf.Topics = append(conf.Topics, topic)
+		}
+	}
+
+	conf.Ignore = append(conf.Ignore, patch.Ignore...)
+
+	return New(conf)
+}
+
+// Config returns a copy of the mapping configuration.
+func (m *Mapper) Config() Config {
+	conf := Config{Ignore: slices.Clone(m.conf.Ignore)}
+	for _, topic := range m.conf.Topics {
+		topic.Tags = slices.Clone(topic.Tags)
+		conf.Topics = append(conf.Topics, topic)
+	}
+
+	return conf
+}
+
+// Topic returns topic by ID, its first tag is the preferred one when topic is converted back to a tag.
+func (m *Mapper) Topic(id string) (Topic, bool) {
+	for _, topic := range m.conf.Topics {
+		if topic.ID == id {
+			topic.Tags = slices.Clone(topic.Tags)
+			return topic, true
+		}
+	}
+
+	return Topic{}, false
+}
+
+// Fuzzy is a tag which matches a known tag only approximately.
+type Fuzzy struct {
+	Tag   string // tag as it's given
+	Match string // normalized known tag it's matched to
+}
+
+// Lookup returns topics the tag refers to. The second value is false if the tag is unknown, known tags may have no
+// topics (see Config.Ignore). Tags with typos are not matched, see Map.
+func (m *Mapper) Lookup(tag string) ([]string, bool) {
+	key := Normalize(tag)
+	if key == "" {
+		return nil, false
+	}
+
+	if m.ignore[key] {
+		return nil, true
+	}
+
+	if topics, ok := m.tags[key]; ok {
+		return slices.Clone(topics), true
+	}
+
+	if match, ok := m.compact[strings.ReplaceAll(key, " ", "")]; ok {
+		return slices.Clone(m.tags[match]), true
+	}
+
+	return nil, false
+}
+
+// Map returns sorted unique topics for the tags and tags which could not be mapped, in the order they are given.
+// Long tags which do not match exactly are matched to a known tag with a typo, such matches are returned as fuzzy,
+// so they can be reported and checked.
+func (m *Mapper) Map(tags []string) (topics []string, unmapped []string, fuzzy []Fuzzy) {
+	for _, tag := range tags {
+		links, ok := m.Lookup(tag)
+		if !ok {
+			if match, found := m.closest(Normalize(tag)); found {
+				links, ok = m.tags[match], true
+				if !slices.Contains(fuzzy, Fuzzy{Tag: tag, Match: match}) {
+					fuzzy = append(fuzzy, Fuzzy{Tag: tag, Match: match})
+				}
+			}
+		}
+
+		if !ok {
+			if strings.TrimSpace(tag) != "" && !slices.Contains(unmapped, tag) {
+				unmapped = append(unmapped, tag)
+			}
+
+			continue
+		}
+
+		for _, link := range links {
+			if !slices.Contains(topics, link) {
+				topics = append(topics, link)
+			}
+		}
+	}
+
+	slices.Sort(topics)
+
+	return
+}
+
+// closest finds the only known tag which differs from the key by a single edit (two for long tags), short tags must
+// match exactly
+func (m *Mapper) closest(key string) (string, bool) {
+	limit := 0
+	switch n := len([]rune(key)); {
+	case n >= 12:
+		limit = 2
+	case n >= 6:
+		limit = 1
+	}
+
+	if limit == 0 {
+		return "", false
+	}
+
+	best, distance, unique := "", limit+1, false
+	for tag := range m.tags {
+		d := levenshtein(key, tag)
+		switch {
+		case d < distance:
+			best, distance, unique = tag, d, true
+		case d == distance && best != tag:
+			unique = false
+		}
+	}
+
+	return best, unique && distance <= limit
+}
+
+// Normalize converts tag into the form used for matching: lower case without diacritics, Cyrillic transliterated into
+// Latin, possessive 's dropped and everything except letters and digits replaced by a single space.
+func Normalize(tag string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), strings.ToLower(tag))
+	if err != nil {
+		folded = strings.ToLower(tag)
+	}
+
+	folded = strings.NewReplacer("'s ", " ", "’s ", " ").Replace(folded + " ")
+
+	var b strings.Builder
+	space := false
+
+	for _, r := range folded {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			space = b.Len() > 0
+			continue
+		}
+
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+
+		if latin, ok := cyrillic[r]; ok {
+			b.WriteString(latin)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// cyrillic letters used in Russian and Ukrainian, letters with diacritics (й, ё, ї) are decomposed before lookup
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'ґ': "g", 'д': "d", 'е': "e", 'є': "ye", 'ж': "zh", 'з': "z", 'и': "i",
+	'і': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e",
+	'ю': "yu", 'я': "ya",
+}
+
+func levenshtein(a, b string) int {
+	x, y := []rune(a), []rune(b)
+	prev := make([]int, len(y)+1)
+	curr := make([]int, len(y)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(x); i++ {
+		curr[0] = i
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(y)]
+}
@@ -0,0 +1,123 @@
+package topics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Binary-Search":        "binary search",
+		"binary_search":        "binary search",
+		"  Two   Pointers ":    "two pointers",
+		"Dijkstra's algorithm": "dijkstra algorithm",
+		"Árbol":                "arbol",
+		"двоичный поиск":       "dvoichnyi poisk",
+		"Бінарний пошук":       "binarnii poshuk",
+		"2-SAT":                "2 sat",
+	}
+
+	for tag, want := range tests {
+		if got := Normalize(tag); got != want {
+			t.Errorf("Normalize(%#v) = %#v, want %#v", tag, got, want)
+		}
+	}
+}
+
+func TestMapper_Map(t *testing.T) {
+	m := Default()
+
+	binary := "3hr591p5lh7a9c5k9bg8kpvctg"
+
+	for _, tag := range []string{"Binary-Search", "binary_search", "BinarySearch", "двоичный поиск", "Бинарный  поиск"} {
+		if got, ok := m.Lookup(tag); !ok || !cmp.Equal([]string{binary}, got) {
+			t.Errorf("tag %#v must map to binary search, got %v", tag, got)
+		}
+	}
+
+	if topic, ok := m.Topic(binary); !ok || topic.Name != "Binary Search" || topic.Tags[0] != "binary search" {
+		t.Errorf("topic must be found by ID, got %+v", topic)
+	}
+
+	if got, ok := m.Lookup("dijkstrra"); ok {
+		t.Errorf("misspelled tag must not be looked up, got %v", got)
+	}
+
+	if topics, unmapped, fuzzy := m.Map([]string{"dijkstrra", "dp2"}); !cmp.Equal([]string{"httb8civtl0u74jm2e143pm5ok"}, topics) {
+		t.Errorf("misspelled tag must map to Dijkstra, got %v", topics)
+	} else if want := []Fuzzy{{Tag: "dijkstrra", Match: "dijkstra"}}; !cmp.Equal(want, fuzzy) {
+		t.Errorf("misspelled tag must be reported as fuzzy match:\n%s", cmp.Diff(want, fuzzy))
+	} else if want := []string{"dp2"}; !cmp.Equal(want, unmapped) {
+		t.Errorf("short tags must match exactly, got unmapped %v", unmapped)
+	}
+
+	topics, unmapped, fuzzy := m.Map([]string{"dp", "bitmask-dp", "ad-hoc", "quantum computing", "dp", "quantum computing"})
+
+	if want := []string{"ab0l1n5rsl3618ntv9ode0qn5k", "agd25lpqt52m565ljb65l0mqg0"}; !cmp.Equal(want, topics) {
+		t.Errorf("topics do not match:\n%s", cmp.Diff(want, topics))
+	}
+
+	if want := []string{"quantum computing"}; !cmp.Equal(want, unmapped) {
+		t.Errorf("unmapped tags do not match:\n%s", cmp.Diff(want, unmapped))
+	}
+
+	if len(fuzzy) != 0 {
+		t.Errorf("exact matches must not be reported as fuzzy, got %v", fuzzy)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "topics.yaml")
+
+	conf := `
+topics:
+  - {id: 3hr591p5lh7a9c5k9bg8kpvctg, name: Binary Search, tags: [bisect]}
+  - {id: newtopic, tags: [quantum computing]}
+ignore: [olympiad]
+`
+
+	if err := os.WriteFile(path, []byte(conf), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	topics, unmapped, _ := m.Map([]string{"bisect", "binsearch", "Quantum-Computing", "olympiad", "dp"})
+
+	if want := []string{"3hr591p5lh7a9c5k9bg8kpvctg", "agd25lpqt52m565ljb65l0mqg0", "newtopic"}; !cmp.Equal(want, topics) {
+		t.Errorf("topics do not match:\n%s", cmp.Diff(want, topics))
+	}
+
+	// tags of the overridden topic are replaced
+	if want := []string{"binsearch"}; !cmp.Equal(want, unmapped) {
+		t.Errorf("unmapped tags do not match:\n%s", cmp.Diff(want, unmapped))
+	}
+
+	// default mapping must not change
+	if _, ok := Default().Lookup("bisect"); ok {
+		t.Error("default mapping must not be modified")
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	tests := map[string]string{
+		"missing ID":      `{topics: [{tags: [dp]}]}`,
+		"duplicate topic": `{topics: [{id: a, tags: [dp]}, {id: a, tags: [bfs]}]}`,
+		"empty tag":       `{topics: [{id: a, tags: ["--"]}]}`,
+		"unknown field":   `{keywords: []}`,
+	}
+
+	for name, conf := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(conf)); err == nil {
+				t.Error("Parse must fail")
+			}
+		})
+	}
+}
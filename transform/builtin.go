@@ -0,0 +1,321 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+)
+
+func init() {
+	Register("limits", limits)
+	Register("feedback", feedback)
+	Register("scoring", scoring)
+	Register("topics", topics)
+	Register("drop-solutions", dropSolutions)
+	Register("rename-scripts", renameScripts)
+	Register("attachment", attachment)
+}
+
+// selector picks testsets by index
+type selector struct {
+	Testsets []uint32 `yaml:"testsets"`
+}
+
+func (s selector) match(testset *atlaspb.Testset) bool {
+	return len(s.Testsets) == 0 || slices.Contains(s.Testsets, testset.GetIndex())
+}
+
+func limits(decode func(v any) error) (Transform, error) {
+	conf := struct {
+		selector  `yaml:",inline"`
+		Time      uint32 `yaml:"time"`
+		MinTime   uint32 `yaml:"min_time"`
+		MaxTime   uint32 `yaml:"max_time"`
+		Memory    uint64 `yaml:"memory"`
+		MinMemory uint64 `yaml:"min_memory"`
+		MaxMemory uint64 `yaml:"max_memory"`
+	}{}
+
+	if err := decode(&conf); err != nil {
+		return nil, err
+	}
+
+	if conf.MaxTime > 0 && conf.MinTime > conf.MaxTime || conf.MaxMemory > 0 && conf.MinMemory > conf.MaxMemory {
+		return nil, errors.New("minimum limit must not exceed maximum limit")
+	}
+
+	return Func(func(snap *atlaspb.Snapshot) error {
+		for _, testset := range snap.GetTestsets() {
+			if !conf.match(testset) {
+				continue
+			}
+
+			if testset.GetTimeLimit() > 0 || conf.Time > 0 {
+				testset.TimeLimit = clamp(set(testset.GetTimeLimit(), conf.Time), conf.MinTime, conf.MaxTime)
+			}
+
+			if testset.GetCpuLimit() > 0 || conf.Time > 0 {
+				testset.CpuLimit = clamp(set(testset.GetCpuLimit(), conf.Time), conf.MinTime, conf.MaxTime)
+			}
+
+			testset.MemoryLimit = clamp(set(testset.GetMemoryLimit(), conf.Memory), conf.MinMemory, conf.MaxMemory)
+		}
+
+		return nil
+	}), nil
+}
+
+func feedback(decode func(v any) error) (Transform, error) {
+	conf := struct {
+		selector `yaml:",inline"`
+		Policy   string `yaml:"policy"`
+	}{}
+
+	if err := decode(&conf); err != nil {
+		return nil, err
+	}
+
+	policy, ok := atlaspb.FeedbackPolicy_value[enum(conf.Policy)]
+	if !ok {
+		return nil, fmt.Errorf("unknown feedback policy %#v", conf.Policy)
+	}
+
+	return Func(func(snap *atlaspb.Snapshot) error {
+		for _, testset := range snap.GetTestsets() {
+			if conf.match(testset) {
+				testset.FeedbackPolicy = atlaspb.FeedbackPolicy(policy)
+			}
+		}
+
+		return nil
+	}), nil
+}
+
+func scoring(decode func(v any) error) (Transform, error) {
+	conf := struct {
+		selector `yaml:",inline"`
+		Mode     string  `yaml:"mode"`
+		Total    float32 `yaml:"total"`
+	}{}
+
+	if err := decode(&conf); err != nil {
+		return nil, err
+	}
+
+	mode, ok := atlaspb.ScoringMode_value[enum(conf.Mode)]
+	if conf.Mode != "" && !ok {
+		return nil, fmt.Errorf("unknown scoring mode %#v", conf.Mode)
+	}
+
+	if conf.Mode == "" && conf.Total <= 0 {
+		return nil, errors.New("either mode or total must be set")
+	}
+
+	return Func(func(snap *atlaspb.Snapshot) error {
+		selected := map[string]bool{}
+		for _, testset := range snap.GetTestsets() {
+			if !conf.match(testset) {
+				continue
+			}
+
+			selected[testset.GetId()] = true
+
+			if conf.Mode != "" {
+				testset.ScoringMode = atlaspb.ScoringMode(mode)
+			}
+		}
+
+		if conf.Total <= 0 {
+			return nil
+		}
+
+		var sum float32
+		for _, test := range snap.GetTests() {
+			if selected[test.GetTestsetId()] {
+				sum += test.GetScore()
+			}
+		}
+
+		if sum == 0 {
+			return errors.New("unable to rescale scores, selected tests have no score")
+		}
+
+		for _, test := range snap.GetTests() {
+			if selected[test.GetTestsetId()] {
+				test.Score = test.GetScore() * conf.Total / sum
+			}
+		}
+
+		return nil
+	}), nil
+}
+
+func topics(decode func(v any) error) (Transform, error) {
+	conf := struct {
+		Add    []string `yaml:"add"`
+		Remove []string `yaml:"remove"`
+	}{}
+
+	if err := decode(&conf); err != nil {
+		return nil, err
+	}
+
+	return Func(func(snap *atlaspb.Snapshot) error {
+		if snap.Problem == nil {
+			snap.Problem = &atlaspb.Problem{}
+		}
+
+		var result []string
+		for _, topic := range snap.Problem.GetTopics() {
+			if !slices.Contains(conf.Remove, topic) && !slices.Contains(result, topic) {
+				result = append(result, topic)
+			}
+		}
+
+		for _, topic := range conf.Add {
+			if !slices.Contains(result, topic) {
+				result = append(result, topic)
+			}
+		}
+
+		slices.Sort(result)
+		snap.Problem.Topics = result
+
+		return nil
+	}), nil
+}
+
+func dropSolutions(decode func(v any) error) (Transform, error) {
+	conf := struct {
+		Runtimes []string `yaml:"runtimes"`
+		Names    []string `yaml:"names"`
+	}{}
+
+	if err := decode(&conf); err != nil {
+		return nil, err
+	}
+
+	for _, pattern := range append(conf.Runtimes, conf.Names...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %#v: %w", pattern, err)
+		}
+	}
+
+	return Func(func(snap *atlaspb.Snapshot) error {
+		snap.Solutions = slices.DeleteFunc(snap.Solutions, func(solution *atlaspb.Solution) bool {
+			return matchAny(conf.Runtimes, solution.GetRuntime()) || matchAny(conf.Names, solution.GetName())
+		})
+
+		return nil
+	}), nil
+}
+
+func renameScripts(decode func(v any) error) (Transform, error) {
+	conf := struct {
+		Names map[string]string `yaml:"names"`
+	}{}
+
+	if err := decode(&conf); err != nil {
+		return nil, err
+	}
+
+	return Func(func(snap *atlaspb.Snapshot) error {
+		names := map[string]bool{}
+		for _, script := range snap.GetScripts() {
+			if name, ok := conf.Names[script.GetName()]; ok {
+				script.Name = name
+			}
+
+			if names[script.GetName()] {
+				return fmt.Errorf("script %#v is defined more than once", script.GetName())
+			}
+
+			names[script.GetName()] = true
+		}
+
+		for _, test := range snap.GetTests() {
+			if gen := test.GetInputGenerator(); gen != nil {
+				if name, ok := conf.Names[gen.GetScriptName()]; ok {
+					gen.ScriptName = name
+				}
+			}
+
+			if gen := test.GetAnswerGenerator(); gen != nil {
+				if name, ok := conf.Names[gen.GetScriptName()]; ok {
+					gen.ScriptName = name
+				}
+			}
+		}
+
+		return nil
+	}), nil
+}
+
+func attachment(decode func(v any) error) (Transform, error) {
+	conf := struct {
+		Name string `yaml:"name"`
+		Link string `yaml:"link"`
+	}{}
+
+	if err := decode(&conf); err != nil {
+		return nil, err
+	}
+
+	if conf.Name == "" || conf.Link == "" {
+		return nil, errors.New("name and link are required")
+	}
+
+	return Func(func(snap *atlaspb.Snapshot) error {
+		for _, a := range snap.GetAttachments() {
+			if a.GetName() == conf.Name {
+				a.Link = conf.Link
+				return nil
+			}
+		}
+
+		snap.Attachments = append(snap.Attachments, &atlaspb.Attachment{Name: conf.Name, Link: conf.Link})
+
+		return nil
+	}), nil
+}
+
+// enum converts a name like icpc-expanded to ICPC_EXPANDED
+func enum(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// set replaces value with v unless v is zero
+func set[T uint32 | uint64](value, v T) T {
+	if v > 0 {
+		return v
+	}
+
+	return value
+}
+
+// clamp value into [lo, hi], zero bounds are ignored
+func clamp[T uint32 | uint64](value, lo, hi T) T {
+	if lo > 0 && value < lo {
+		value = lo
+	}
+
+	if hi > 0 && value > hi {
+		value = hi
+	}
+
+	return value
+}
+
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,190 @@
+// Package transform post-processes snapshots produced by loaders.
+//
+// Transforms are small named functions which modify the snapshot in place, for example clamp limits or change
+// feedback policy. They are combined into a Pipeline, which can be declared in YAML or JSON:
+//
+//	transforms:
+//	  - name: limits
+//	    with: {max_memory: 268435456}
+//	  - name: feedback
+//	    with: {policy: icpc}
+//	  - name: drop-solutions
+//	    with: {runtimes: ["python:*-pypy*"]}
+//
+// Built-in transforms:
+//
+//	limits          clamp or set time (ms) and memory (bytes) limits: time, min_time, max_time, memory, min_memory,
+//	                max_memory
+//	feedback        set feedback policy: policy (complete, icpc or icpc-expanded)
+//	scoring         set scoring mode or rescale test scores: mode (each, all, worst, best or no-score), total
+//	topics          add or remove problem topics: add, remove
+//	drop-solutions  remove solutions by runtime or name (glob patterns): runtimes, names
+//	rename-scripts  rename scripts and update tests which use them: names (old name to new name)
+//	attachment      add an attachment: name, link
+//
+// Transforms changing testsets accept optional testsets parameter, a list of testset indices to change, all
+// testsets are changed if it's empty. Custom transforms can be added using Register.
+package transform
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	"gopkg.in/yaml.v2"
+)
+
+// Transform modifies the snapshot in place.
+type Transform interface {
+	Apply(snap *atlaspb.Snapshot) error
+}
+
+// Func is a function implementing Transform.
+type Func func(snap *atlaspb.Snapshot) error
+
+func (f Func) Apply(snap *atlaspb.Snapshot) error {
+	return f(snap)
+}
+
+// Factory creates transform from its configuration. Decode unmarshals configuration (the "with" section) into a
+// struct, unknown fields are reported as errors.
+type Factory func(decode func(v any) error) (Transform, error)
+
+var (
+	lock     sync.RWMutex
+	registry = map[string]Factory{}
+)
+
+// Register makes transform available under the given name, registering the same name twice replaces the factory.
+func Register(name string, factory Factory) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	registry[name] = factory
+}
+
+// Names returns names of all registered transforms.
+func Names() []string {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Step is a declaration of a single transform in the pipeline.
+type Step struct {
+	Name string         `yaml:"name" json:"name"`
+	With map[string]any `yaml:"with,omitempty" json:"with,omitempty"`
+}
+
+// Config is a declaration of the pipeline.
+type Config struct {
+	Transforms []Step `yaml:"transforms" json:"transforms"`
+}
+
+// Pipeline applies transforms one after another.
+type Pipeline struct {
+	names      []string
+	transforms []Transform
+}
+
+// New creates pipeline from steps.
+func New(steps ...Step) (*Pipeline, error) {
+	p := &Pipeline{}
+
+	for i, step := range steps {
+		t, err := Build(step)
+		if err != nil {
+			return nil, fmt.Errorf("transform #%d: %w", i+1, err)
+		}
+
+		p.Append(step.Name, t)
+	}
+
+	return p, nil
+}
+
+// Parse creates pipeline from YAML or JSON configuration.
+func Parse(data []byte) (*Pipeline, error) {
+	conf := Config{}
+	if err := yaml.UnmarshalStrict(data, &conf); err != nil {
+		return nil, fmt.Errorf("unable to parse pipeline configuration: %w", err)
+	}
+
+	return New(conf.Transforms...)
+}
+
+// Load reads pipeline configuration from the file.
+func Load(path string) (*Pipeline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read pipeline configuration: %w", err)
+	}
+
+	return Parse(data)
+}
+
+// Build creates transform declared by the step.
+func Build(step Step) (Transform, error) {
+	lock.RLock()
+	factory, ok := registry[step.Name]
+	lock.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("transform %#v is not registered", step.Name)
+	}
+
+	t, err := factory(func(v any) error {
+		data, err := yaml.Marshal(step.With)
+		if err != nil {
+			return err
+		}
+
+		return yaml.UnmarshalStrict(data, v)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration of transform %#v: %w", step.Name, err)
+	}
+
+	return t, nil
+}
+
+// Append adds transform to the end of the pipeline.
+func (p *Pipeline) Append(name string, t Transform) {
+	p.names = append(p.names, name)
+	p.transforms = append(p.transforms, t)
+}
+
+// Len returns number of transforms in the pipeline.
+func (p *Pipeline) Len() int {
+	if p == nil {
+		return 0
+	}
+
+	return len(p.transforms)
+}
+
+// Apply runs all transforms, it stops at the first error. Nil pipeline does nothing.
+func (p *Pipeline) Apply(snap *atlaspb.Snapshot) error {
+	if p == nil {
+		return nil
+	}
+
+	for i, t := range p.transforms {
+		if err := t.Apply(snap); err != nil {
+			return fmt.Errorf("transform %#v has failed: %w", p.names[i], err)
+		}
+	}
+
+	return nil
+}
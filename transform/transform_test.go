@@ -0,0 +1,140 @@
+package transform
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func snapshot() *atlaspb.Snapshot {
+	return &atlaspb.Snapshot{
+		Problem: &atlaspb.Problem{Topics: []string{"dp", "graphs"}},
+		Testsets: []*atlaspb.Testset{
+			{Id: "ts0", Index: 0, CpuLimit: 1000, MemoryLimit: 64 << 20, ScoringMode: atlaspb.ScoringMode_EACH},
+			{Id: "ts1", Index: 1, CpuLimit: 5000, MemoryLimit: 1 << 30, ScoringMode: atlaspb.ScoringMode_EACH},
+		},
+		Tests: []*atlaspb.Test{
+			{TestsetId: "ts0", Index: 1, Example: true},
+			{TestsetId: "ts1", Index: 1, Score: 20, Input: &atlaspb.Test_InputGenerator{InputGenerator: &atlaspb.Test_Generator{ScriptName: "gen"}}},
+			{TestsetId: "ts1", Index: 2, Score: 30},
+		},
+		Scripts: []*atlaspb.Script{{Name: "gen"}, {Name: "val"}},
+		Solutions: []*atlaspb.Solution{
+			{Name: "main.cpp", Runtime: "cpp:17-gnu10"},
+			{Name: "slow.py", Runtime: "python:3.10-pypy"},
+			{Name: "wa.java", Runtime: "java:1.21"},
+		},
+	}
+}
+
+func TestParse(t *testing.T) {
+	yaml := `
+transforms:
+  - name: limits
+    with: {max_time: 2000, max_memory: 268435456}
+  - name: feedback
+    with: {policy: icpc, testsets: [1]}
+  - name: scoring
+    with: {mode: all, total: 100}
+  - name: topics
+    with: {add: [math], remove: [graphs]}
+  - name: drop-solutions
+    with: {runtimes: ["python:*-pypy*"], names: ["wa.*"]}
+  - name: rename-scripts
+    with: {names: {gen: generator}}
+  - name: attachment
+    with: {name: rules.pdf, link: "https://example.com/rules.pdf"}
+`
+
+	json := `{"transforms": [
+		{"name": "limits", "with": {"max_time": 2000, "max_memory": 268435456}},
+		{"name": "feedback", "with": {"policy": "icpc", "testsets": [1]}},
+		{"name": "scoring", "with": {"mode": "all", "total": 100}},
+		{"name": "topics", "with": {"add": ["math"], "remove": ["graphs"]}},
+		{"name": "drop-solutions", "with": {"runtimes": ["python:*-pypy*"], "names": ["wa.*"]}},
+		{"name": "rename-scripts", "with": {"names": {"gen": "generator"}}},
+		{"name": "attachment", "with": {"name": "rules.pdf", "link": "https://example.com/rules.pdf"}}
+	]}`
+
+	want := snapshot()
+	want.Problem.Topics = []string{"dp", "math"}
+	want.Testsets[1].CpuLimit = 2000
+	want.Testsets[1].MemoryLimit = 268435456
+	want.Testsets[1].FeedbackPolicy = atlaspb.FeedbackPolicy_ICPC
+	want.Testsets[0].ScoringMode = atlaspb.ScoringMode_ALL
+	want.Testsets[1].ScoringMode = atlaspb.ScoringMode_ALL
+	want.Tests[1].Score = 40
+	want.Tests[1].GetInputGenerator().ScriptName = "generator"
+	want.Tests[2].Score = 60
+	want.Scripts[0].Name = "generator"
+	want.Solutions = want.Solutions[:1]
+	want.Attachments = []*atlaspb.Attachment{{Name: "rules.pdf", Link: "https://example.com/rules.pdf"}}
+
+	for name, conf := range map[string]string{"yaml": yaml, "json": json} {
+		t.Run(name, func(t *testing.T) {
+			pipeline, err := Parse([]byte(conf))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if pipeline.Len() != 7 {
+				t.Errorf("pipeline must have 7 transforms, got %v", pipeline.Len())
+			}
+
+			got := snapshot()
+			if err := pipeline.Apply(got); err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(want, got, protocmp.Transform()) {
+				t.Errorf("snapshot does not match:\n%s", cmp.Diff(want, got, protocmp.Transform()))
+			}
+		})
+	}
+}
+
+func TestParse_errors(t *testing.T) {
+	tests := map[string]string{
+		"unknown transform": `{"transforms": [{"name": "magic"}]}`,
+		"unknown parameter": `{"transforms": [{"name": "limits", "with": {"max_cpu": 1}}]}`,
+		"unknown policy":    `{"transforms": [{"name": "feedback", "with": {"policy": "ioi"}}]}`,
+		"invalid pattern":   `{"transforms": [{"name": "drop-solutions", "with": {"names": ["["]}}]}`,
+		"invalid limits":    `{"transforms": [{"name": "limits", "with": {"min_time": 10, "max_time": 5}}]}`,
+		"unknown field":     `{"steps": []}`,
+	}
+
+	for name, conf := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(conf)); err == nil {
+				t.Error("Parse must fail")
+			}
+		})
+	}
+}
+
+func TestPipeline_Apply(t *testing.T) {
+	Register("test-fail", func(decode func(v any) error) (Transform, error) {
+		return Func(func(snap *atlaspb.Snapshot) error { return errTest }), nil
+	})
+
+	pipeline, err := New(Step{Name: "topics", With: map[string]any{"add": []string{"math"}}}, Step{Name: "test-fail"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pipeline.Apply(snapshot())
+	if err == nil || !strings.Contains(err.Error(), `"test-fail"`) {
+		t.Errorf("error must name the failed transform, got %v", err)
+	}
+
+	var empty *Pipeline
+	if err := empty.Apply(snapshot()); err != nil {
+		t.Errorf("nil pipeline must do nothing, got %v", err)
+	}
+}
+
+var errTest = errors.New("failed")